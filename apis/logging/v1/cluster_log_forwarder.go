@@ -18,6 +18,28 @@ var ReservedInputNames = sets.NewString(InputNameApplication, InputNameInfrastru
 
 func IsInputTypeName(s string) bool { return ReservedInputNames.Has(s) }
 
+// Infrastructure and audit input sources.
+const (
+	// InfrastructureSourceContainer selects logs from containers in infrastructure namespaces.
+	InfrastructureSourceContainer = "container"
+	// InfrastructureSourceNode selects journal logs from the node.
+	InfrastructureSourceNode = "node"
+
+	// AuditSourceAuditd selects the linux audit logs of the node.
+	AuditSourceAuditd = "auditd"
+	// AuditSourceKube selects the kube-apiserver audit logs.
+	AuditSourceKube = "kubeAPI"
+	// AuditSourceOpenShift selects the openshift-apiserver and oauth-apiserver audit logs.
+	AuditSourceOpenShift = "openshiftAPI"
+	// AuditSourceOVN selects the open virtual network (OVN) audit logs.
+	AuditSourceOVN = "ovn"
+)
+
+var (
+	InfrastructureSources = sets.NewString(InfrastructureSourceContainer, InfrastructureSourceNode)
+	AuditSources          = sets.NewString(AuditSourceAuditd, AuditSourceKube, AuditSourceOpenShift, AuditSourceOVN)
+)
+
 // Default log store output name.
 const OutputNameDefault = "default"
 
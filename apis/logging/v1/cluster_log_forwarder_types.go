@@ -97,19 +97,129 @@ type Application struct {
 	// Namespaces is a list of namespaces from which to collect application logs.
 	// If the list is empty, logs are collected from all namespaces.
 	//
+	// Entries may be glob patterns where `*` matches any sequence of characters, for example `team-a-*`.
+	//
 	// +optional
 	Namespaces []string `json:"namespaces"`
+
+	// ExcludeNamespaces is a list of namespaces from which application logs are not collected,
+	// even if they are included by `namespaces`.
+	//
+	// Entries may be glob patterns where `*` matches any sequence of characters.
+	//
+	// +optional
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
 	// Selector selects logs from all pods with matching labels.
-	// For testing purpose, MatchLabels is only supported.
+	//
+	// The `Exists` and `DoesNotExist` operators of `matchExpressions` are not supported
+	// by the fluentd collector.
+	//
 	// +optional
 	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// DetectExceptions enables grouping the lines of multiline exception stack traces into a single record.
+	//
+	// Stack traces are detected in the container logs of the `namespaces` of the input, or of all containers
+	// if no namespaces are listed, before any other processing. `excludeNamespaces` and `selector` do not
+	// restrict the detection. If the namespaces of several inputs overlap, the first input applies.
+	// Not supported by the vector collector.
+	//
+	// +optional
+	DetectExceptions *DetectExceptions `json:"detectExceptions,omitempty"`
+
+	// RateLimit limits the rate of the logs of each namespace or container selected by the input.
+	//
+	// The limit applies to the logs of the input sent to each pipeline.
+	// Not supported by the vector collector.
+	//
+	// +optional
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
 }
 
-// Infrastructure enables infrastructure logs. Filtering may be added in future.
-type Infrastructure struct{}
+// RateLimit limits the rate of logs, records exceeding the limit are dropped.
+type RateLimit struct {
+	// RecordsPerSecond is the maximum average rate of records of a namespace or container.
+	//
+	// +kubebuilder:validation:Minimum:=1
+	// +required
+	RecordsPerSecond int64 `json:"recordsPerSecond"`
+
+	// Burst is the maximum number of records of a namespace or container accepted at once,
+	// defaults to `recordsPerSecond`. Must not be less than `recordsPerSecond`.
+	// The rate is limited over periods of `burst / recordsPerSecond` seconds, rounded up.
+	//
+	// +optional
+	Burst int64 `json:"burst,omitempty"`
 
-// Audit enables audit logs. Filtering may be added in future.
-type Audit struct{}
+	// Per is the scope of the limit, the logs of each `namespace` or of each `container`.
+	// Defaults to `namespace`.
+	//
+	// +kubebuilder:validation:Enum:=namespace;container
+	// +optional
+	Per RateLimitScope `json:"per,omitempty"`
+
+	// Policy is `drop` to drop the records exceeding the limit, or `dropAndCount` to also count the
+	// records subject to the limit and the records accepted. The counters are published by the collector
+	// metrics endpoint as `cluster_logging_collector_rate_limit_input_record_total` and
+	// `cluster_logging_collector_rate_limit_output_record_total`, the number of dropped records is recorded
+	// as `cluster_logging_collector_rate_limit_dropped_record_total`. Defaults to `drop`.
+	//
+	// +kubebuilder:validation:Enum:=drop;dropAndCount
+	// +optional
+	Policy RateLimitPolicy `json:"policy,omitempty"`
+}
+
+// RateLimitScope is the scope of a rate limit
+type RateLimitScope string
+
+const (
+	// RateLimitPerNamespace limits the rate of the logs of each namespace
+	RateLimitPerNamespace RateLimitScope = "namespace"
+	// RateLimitPerContainer limits the rate of the logs of each container
+	RateLimitPerContainer RateLimitScope = "container"
+)
+
+// RateLimitPolicy is the handling of records exceeding a rate limit
+type RateLimitPolicy string
+
+const (
+	// RateLimitPolicyDrop drops the records exceeding the limit
+	RateLimitPolicyDrop RateLimitPolicy = "drop"
+	// RateLimitPolicyDropAndCount drops the records exceeding the limit, and counts the records subject to
+	// the limit and accepted
+	RateLimitPolicyDropAndCount RateLimitPolicy = "dropAndCount"
+)
+
+// DetectExceptions configures the detection of multiline exception stack traces.
+type DetectExceptions struct {
+	// Languages is the list of languages of the stack traces to detect.
+	// If the list is empty, stack traces of all supported languages are detected.
+	//
+	// +optional
+	Languages []ExceptionLanguage `json:"languages,omitempty"`
+}
+
+// ExceptionLanguage is a language of which stack traces are detected
+// +kubebuilder:validation:Enum:=java;python;go;ruby;js;csharp;php;dart
+type ExceptionLanguage string
+
+// Infrastructure enables infrastructure logs.
+type Infrastructure struct {
+	// Sources lists the infrastructure sources to collect, `container` and/or `node`.
+	// If the list is empty, logs are collected from all infrastructure sources.
+	//
+	// +optional
+	Sources []string `json:"sources,omitempty"`
+}
+
+// Audit enables audit logs.
+type Audit struct {
+	// Sources lists the audit sources to collect, any of `auditd`, `kubeAPI`, `openshiftAPI` and `ovn`.
+	// If the list is empty, logs are collected from all audit sources.
+	//
+	// +optional
+	Sources []string `json:"sources,omitempty"`
+}
 
 // Output defines a destination for log messages.
 type OutputSpec struct {
@@ -121,7 +231,7 @@ type OutputSpec struct {
 
 	// Type of output plugin.
 	//
-	// +kubebuilder:validation:Enum:=syslog;fluentdForward;elasticsearch;kafka;cloudwatch;loki
+	// +kubebuilder:validation:Enum:=syslog;fluentdForward;elasticsearch;kafka;cloudwatch;loki;http;splunk;googleCloudLogging
 	// +required
 	Type string `json:"type"`
 
@@ -155,6 +265,15 @@ type OutputSpec struct {
 	//
 	// +optional
 	Secret *OutputSecretSpec `json:"secret,omitempty"`
+
+	// Buffer tunes the delivery of log records to this output.
+	//
+	// Parameters set here override the global `forwarder.fluentd.buffer` settings of
+	// the ClusterLogging instance for this output only. Unset parameters use the global
+	// setting or its default.
+	//
+	// +optional
+	Buffer *FluentdBufferSpec `json:"buffer,omitempty"`
 }
 
 // OutputSecretSpec is a secret reference containing name only, no namespace.
@@ -204,6 +323,99 @@ type PipelineSpec struct {
 	// +kubebuilder:validation:Enum:=json
 	// +optional
 	Parse string `json:"parse,omitempty"`
+
+	// Filters lists rules to keep or drop log records before they are sent to the outputs.
+	//
+	// Filters are applied in the order they are listed. A record is sent to the outputs
+	// only if it is not dropped by any of the filters.
+	// Not supported by the vector collector.
+	//
+	// +optional
+	Filters []FilterSpec `json:"filters,omitempty"`
+
+	// Fields lists changes to the fields of the log records, applied after `filters` before the records
+	// are sent to the outputs.
+	//
+	// +optional
+	Fields *FieldsSpec `json:"fields,omitempty"`
+}
+
+// FieldsSpec keeps, prunes and renames log record fields.
+//
+// Fields are dot-delimited paths of record fields, for example `kubernetes.flat_labels`. A field is changed with
+// its nested fields. Fields are kept first, then pruned, then renamed.
+// Fields required by the outputs of the pipeline, for example the fields of Loki labels or the index, id and
+// timestamp of Elasticsearch records, can not be removed. Fields must not contain quotes or commas.
+type FieldsSpec struct {
+	// Keep lists the fields to keep, all other fields are removed. Kept fields absent from a record are not added.
+	// If the list is empty, all fields are kept.
+	//
+	// +optional
+	Keep []string `json:"keep,omitempty"`
+
+	// Prune lists the fields to remove.
+	//
+	// +optional
+	Prune []string `json:"prune,omitempty"`
+
+	// Rename lists the fields to move to a new top level field, in order.
+	//
+	// +optional
+	Rename []RenameField `json:"rename,omitempty"`
+}
+
+// RenameField moves a log record field.
+type RenameField struct {
+	// From is the dot-delimited path of the field to move.
+	//
+	// +required
+	From string `json:"from"`
+
+	// To is the name of the top level field the field is moved to, it must not contain dots.
+	//
+	// +required
+	To string `json:"to"`
+}
+
+// FilterAction is the action taken on the log records matched by a filter.
+type FilterAction string
+
+const (
+	// FilterActionKeep keeps only the records that match the filter.
+	FilterActionKeep FilterAction = "keep"
+	// FilterActionDrop drops the records that match the filter.
+	FilterActionDrop FilterAction = "drop"
+)
+
+// FilterSpec matches log records by the content of a record field.
+//
+// Exactly one of `field` or `levels` must be set.
+type FilterSpec struct {
+	// Action is the action taken on the records that match this filter.
+	//
+	// +kubebuilder:validation:Enum:=keep;drop
+	// +required
+	Action FilterAction `json:"action"`
+
+	// Field is the dot-delimited path of the record field to match, for example
+	// `message`, `kubernetes.namespace_name` or `kubernetes.labels.app.kubernetes.io/name`.
+	//
+	// +optional
+	Field string `json:"field,omitempty"`
+
+	// Pattern is a regular expression matched against the value of `field`.
+	//
+	// The pattern is matched by the collector as a Ruby regular expression: `^` and `$` match at
+	// line boundaries, and `\Q...\E`, `(?P<name>...)` and the `m`, `s` and `U` flags are not supported.
+	//
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+
+	// Levels lists the log levels (for example `debug` or `trace`) to match against the
+	// normalized `level` field of the record.
+	//
+	// +optional
+	Levels []string `json:"levels,omitempty"`
 }
 
 type OutputDefaults struct {
@@ -232,6 +444,10 @@ type OutputDefaults struct {
 // you can define your own outputs with a URL and other connection information
 // to forward logs to other stores or processors, inside or outside the cluster.
 //
+// Each ClusterLogForwarder in the operator namespace adds its pipelines to the
+// collector configuration. Input, output and pipeline names of instances other
+// than `instance` are prefixed with `<name>.` to keep them apart.
+//
 // For more details see the documentation on the API fields.
 type ClusterLogForwarder struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -19,6 +19,7 @@ package v1
 import (
 	"github.com/openshift/cluster-logging-operator/internal/status"
 	elasticsearch "github.com/openshift/elasticsearch-operator/pkg/apis/logging/v1"
+	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -188,6 +189,44 @@ type LogCollectionSpec struct {
 
 	// Specification of the Fluentd Log Collection component
 	FluentdSpec `json:"fluentd,omitempty"`
+
+	// RolloutPolicy defines how changes of the collector configuration are rolled out.
+	// Without a policy, a change is rolled out to all collectors at once.
+	// Not supported by the vector collector.
+	//
+	// +optional
+	RolloutPolicy *RolloutPolicySpec `json:"rolloutPolicy,omitempty"`
+}
+
+// RolloutPolicySpec rolls out a collector configuration change to the collectors of canary nodes first.
+// The change is rolled out to all collectors once the canary collectors are healthy, otherwise
+// the collectors are reverted to the last good configuration.
+type RolloutPolicySpec struct {
+	// CanaryNodeSelector selects the canary nodes.
+	//
+	// +optional
+	CanaryNodeSelector map[string]string `json:"canaryNodeSelector,omitempty"`
+
+	// CanaryPercent is the percentage of collector nodes used as canary nodes
+	// when no canaryNodeSelector is given. Defaults to 10.
+	//
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=100
+	// +optional
+	CanaryPercent int32 `json:"canaryPercent,omitempty"`
+
+	// HealthCheckSeconds is how long the canary collectors must be ready, without restarts
+	// and output errors, before the change is rolled out to all collectors. Defaults to 300.
+	//
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	HealthCheckSeconds int32 `json:"healthCheckSeconds,omitempty"`
+
+	// MaxOutputErrors is the number of output errors tolerated on the canary collectors.
+	//
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	MaxOutputErrors int32 `json:"maxOutputErrors,omitempty"`
 }
 
 type EventCollectionSpec struct {
@@ -206,6 +245,40 @@ type FluentdSpec struct {
 	// +nullable
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 	Tolerations  []v1.Toleration   `json:"tolerations,omitempty"`
+
+	// UpdateStrategy of the collector DaemonSet, including the maxUnavailable collectors
+	// of a rolling update. Defaults to a rolling update of one collector at a time.
+	//
+	// +optional
+	UpdateStrategy *apps.DaemonSetUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// PodAnnotations are added to the annotations of the collector pods.
+	// Annotations set by the operator take precedence.
+	//
+	// +optional
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+
+	// PodLabels are added to the labels of the collector pods.
+	// Labels set by the operator take precedence.
+	//
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+
+	// Affinity defines scheduling constraints of the collector pods.
+	//
+	// +optional
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
+
+	// PriorityClassName of the collector pods. Defaults to the cluster-logging priority class.
+	//
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Env are added to the environment of the collector container.
+	// Variables set by the operator take precedence.
+	//
+	// +optional
+	Env []v1.EnvVar `json:"env,omitempty"`
 }
 
 // This is the struct that will contain information pertinent to Log curation (Curator)
@@ -524,6 +597,7 @@ type LogCollectionType string
 
 const (
 	LogCollectionTypeFluentd LogCollectionType = "fluentd"
+	LogCollectionTypeVector  LogCollectionType = "vector"
 )
 
 type EventCollectionType string
@@ -547,6 +621,7 @@ const (
 	Unschedulable       ConditionType = "Unschedulable"
 	NodeStorage         ConditionType = "NodeStorage"
 	CollectorDeadEnd    ConditionType = "CollectorDeadEnd"
+	CollectorRollout    ConditionType = "CollectorRollout"
 )
 
 // `operator-sdk generate crds` does not allow map-of-slice, must use a named type.
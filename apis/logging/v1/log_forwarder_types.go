@@ -0,0 +1,42 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const LogForwarderKind = "LogForwarder"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=logging,shortName=lf
+
+// LogForwarder is a namespaced API to configure forwarding of the application logs
+// of its own namespace.
+//
+// The spec is the same as for a ClusterLogForwarder, but only application logs from
+// the namespace of the LogForwarder are forwarded. The `application` input and custom
+// application inputs are limited to that namespace, inputs that select infrastructure
+// or audit logs or other namespaces are invalid. Outputs may not reference secrets and
+// `outputDefaults` are ignored.
+//
+// The pipelines of all LogForwarders are added to the collector configuration next
+// to those of the ClusterLogForwarders, isolated from each other.
+type LogForwarder struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterLogForwarderSpec   `json:"spec,omitempty"`
+	Status ClusterLogForwarderStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// LogForwarderList contains a list of LogForwarder
+type LogForwarderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LogForwarder `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LogForwarder{}, &LogForwarderList{})
+}
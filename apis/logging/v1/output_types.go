@@ -11,6 +11,10 @@ const (
 	OutputTypeSyslog         = "syslog"
 	OutputTypeKafka          = "kafka"
 	OutputTypeLoki           = "loki"
+	OutputTypeHttp           = "http"
+	OutputTypeSplunk         = "splunk"
+
+	OutputTypeGoogleCloudLogging = "googleCloudLogging"
 )
 
 // OutputTypeSpec is a union of optional additional configuration specific to an
@@ -28,24 +32,63 @@ type OutputTypeSpec struct {
 	Cloudwatch *Cloudwatch `json:"cloudwatch,omitempty"`
 	// +optional
 	Loki *Loki `json:"loki,omitempty"`
+	// +optional
+	Http *Http `json:"http,omitempty"`
+	// +optional
+	Splunk *Splunk `json:"splunk,omitempty"`
+	// +optional
+	GoogleCloudLogging *GoogleCloudLogging `json:"googleCloudLogging,omitempty"`
 }
 
 // Cloudwatch provides configuration for the output type `cloudwatch`
+//
+// The AWS credentials are read from the output secret, either static access keys from secret keys
+// `aws_access_key_id` and `aws_secret_access_key`, or the ARN of a role from secret key `role_arn`.
+// The role is assumed with the projected service account token of the collector, or the web identity
+// token from secret key `token` when present.
+//
+// The output URL is optional, it overrides the endpoint of the region, e.g. for GovCloud or FIPS endpoints
+// or a local Cloudwatch emulator.
 type Cloudwatch struct {
 	// +required
 	Region string `json:"region,omitempty"`
 
 	//GroupBy defines the strategy for grouping logstreams
 	// +required
-	//+kubebuilder:validation:Enum:=logType;namespaceName;namespaceUUID
+	//+kubebuilder:validation:Enum:=logType;namespaceName;namespaceUUID;key
 	GroupBy LogGroupByType `json:"groupBy,omitempty"`
 
+	// GroupByKey is the path of the log record field naming the group of application logs, required by groupBy `key`.
+	// Keys of `kubernetes.labels` and `kubernetes.namespace_labels` may contain dots, for example
+	// `kubernetes.labels.app.kubernetes.io/name`. Application logs without the field are grouped as "application"
+	// +optional
+	GroupByKey string `json:"groupByKey,omitempty"`
+
+	// StreamName defines the strategy for naming the logstreams of application logs, `tag` if unspecified.
+	// Infrastructure and audit logs are always streamed by host and tag
+	// +optional
+	//+kubebuilder:validation:Enum:=tag;podName;containerName
+	StreamName LogStreamNameType `json:"streamName,omitempty"`
+
 	//GroupPrefix Add this prefix to all group names.
 	//  Useful to avoid group name clashes if an AWS account is used for multiple clusters and
 	//  used verbatim (e.g. "" means no prefix)
 	//  The default prefix is cluster-name/log-type
 	// +optional
 	GroupPrefix *string `json:"groupPrefix,omitempty"`
+
+	// CreateLogGroup enables creating the log groups which do not exist yet. Logs sent to missing groups are
+	// otherwise dropped. The fluentd collector creates missing groups along with their streams, it defaults
+	// to true and cannot be disabled with the fluentd collector. It defaults to false with the vector collector
+	// +optional
+	CreateLogGroup *bool `json:"createLogGroup,omitempty"`
+
+	// RetentionInDays is the number of days the logs of the created log groups are retained, logs are never
+	// expired if unspecified. It is not applied to existing groups.
+	// Not supported by the vector collector
+	// +optional
+	//+kubebuilder:validation:Enum:=1;3;5;7;14;30;60;90;120;150;180;365;400;545;731;1827;3653
+	RetentionInDays *int32 `json:"retentionInDays,omitempty"`
 }
 
 // LogGroupByType defines a fixed strategy type
@@ -62,9 +105,31 @@ const (
 	// LogGroupByNamespaceUUID  is the strategy to use for grouping logs by namespace UUID. Infrastructure and
 	// audit logs are always grouped by "logType"
 	LogGroupByNamespaceUUID LogGroupByType = "namespaceUUID"
+
+	// LogGroupByKey is the strategy to use for grouping logs by the value of the log record field GroupByKey.
+	// Infrastructure and audit logs are always grouped by "logType"
+	LogGroupByKey LogGroupByType = "key"
+)
+
+// LogStreamNameType defines a fixed strategy for naming logstreams
+type LogStreamNameType string
+
+const (
+	// LogStreamNameByTag is the strategy to name logstreams by the tag of the logs (e.g. the log file path)
+	LogStreamNameByTag LogStreamNameType = "tag"
+
+	// LogStreamNameByPodName is the strategy to name logstreams by namespace and pod, e.g. "mynamespace_mypod"
+	LogStreamNameByPodName LogStreamNameType = "podName"
+
+	// LogStreamNameByContainerName is the strategy to name logstreams by namespace, pod and container,
+	// e.g. "mynamespace_mypod_mycontainer"
+	LogStreamNameByContainerName LogStreamNameType = "containerName"
 )
 
 // Syslog provides optional extra properties for output type `syslog`
+//
+// Outputs using the `tls` or `udps` scheme verify the server with the CA bundle from secret key `ca-bundle.crt`,
+// and authenticate with the client certificate from secret keys `tls.crt` and `tls.key` when present.
 type Syslog struct {
 	// Severity to set on outgoing syslog records.
 	//
@@ -146,12 +211,43 @@ type Syslog struct {
 	//
 	// +optional
 	MsgID string `json:"msgID,omitempty"`
+
+	// Framing of syslog messages sent over TCP, as defined by https://tools.ietf.org/html/rfc6587#section-3.4
+	//
+	// Framing values can be one of:
+	//  - octetCounting: each message is prefixed by its length in bytes
+	//  - nonTransparent: messages are delimited by a trailing newline
+	//
+	// If unspecified, nonTransparent will be assumed.
+	//
+	// +kubebuilder:validation:Enum:=octetCounting;nonTransparent
+	// +optional
+	Framing string `json:"framing,omitempty"`
+
+	// MaxMessageSize is the maximum size in bytes of a syslog message, longer messages are truncated.
+	//
+	// If unspecified, 4096 will be assumed.
+	//
+	// +kubebuilder:validation:Minimum:=480
+	// +optional
+	MaxMessageSize int `json:"maxMessageSize,omitempty"`
 }
 
+const (
+	SyslogFramingOctetCounting  = "octetCounting"
+	SyslogFramingNonTransparent = "nonTransparent"
+)
+
 // Kafka provides optional extra properties for `type: kafka`
+//
+// SASL authentication uses the secret keys `username` and `password`. The SASL mechanism is read from
+// secret key `sasl_mechanism`, one of `PLAIN`, `SCRAM-SHA-256` or `SCRAM-SHA-512`. If unspecified, `PLAIN` is used.
 type Kafka struct {
 	// Topic specifies the target topic to send logs to.
 	//
+	// Fields of the log record may be referenced as `{.field.path}`, for example `{.log_type}` or
+	// `app-{.kubernetes.namespace_name}`.
+	//
 	// +optional
 	Topic string `json:"topic,omitempty"`
 
@@ -161,10 +257,31 @@ type Kafka struct {
 	//
 	// +optional
 	Brokers []string `json:"brokers,omitempty"`
+
+	// Key specifies the log record field used as message key, for example `{.kubernetes.pod_id}`.
+	// Messages with the same key are written to the same partition, preserving their order.
+	//
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Headers specifies the headers added to each message. A header value is either static or
+	// a reference to a single log record field, for example `{.kubernetes.namespace_name}`.
+	//
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Compression specifies the codec used to compress messages.
+	//
+	// +kubebuilder:validation:Enum:=gzip;snappy;lz4;zstd
+	// +optional
+	Compression string `json:"compression,omitempty"`
 }
 
 type FluentdForward struct{}
 
+// Elasticsearch provides optional extra properties for `type: elasticsearch`
+//
+// For API key authentication, set secret key `apiKey` to the base64 encoded `id:api_key` of the key.
 type Elasticsearch struct {
 	// StructuredTypeKey specifies the metadata key to be used as name of elasticsearch index
 	// It takes precedence over StructuredTypeName
@@ -176,9 +293,29 @@ type Elasticsearch struct {
 	//
 	// +optional
 	StructuredTypeName string `json:"structuredTypeName,omitempty"`
+
+	// Index is the template of the name of the index logs are written to, replacing the
+	// default `app-write`, `infra-write` and `audit-write` indices.
+	//
+	// Fields of the log record are referenced as `{.field.path}`, strftime conversion specifications
+	// are replaced with the date of the log record, for example `logs-{.log_type}-%Y.%m.%d`.
+	// Structured application logs are written to the index of their structured type, if any.
+	//
+	// +optional
+	Index string `json:"index,omitempty"`
+
+	// DataStream writes logs to the data streams named by Index, for Elasticsearch 7.9 and later.
+	// Data streams are created from a matching index template, for example the built-in `logs-*-*` template.
+	// Data stream names have the form `<type>-<dataset>-<namespace>`, for example `logs-{.log_type}-default`.
+	//
+	// +optional
+	DataStream bool `json:"dataStream,omitempty"`
 }
 
 // Loki provides optional extra properties for `type: loki`
+//
+// The path of the output URL is kept as a prefix of the Loki push API path, for Loki behind a reverse proxy.
+// To authenticate as the tenant of the output, set secret key `token` to the bearer token of the tenant.
 type Loki struct {
 	// TenantKey is a meta-data key field to use as the TenantID,
 	// For example: 'TenantKey: kubernetes.namespace_name` will use the kubernetes
@@ -203,4 +340,106 @@ type Loki struct {
 	//
 	// +optional
 	LabelKeys []string `json:"labelKeys,omitempty"`
+
+	// Labels are additional Loki labels whose values are built from templates.
+	// A template is either static or references fields of the log record as `{.field.path}`,
+	// for example `cluster: east` or `app: "{.kubernetes.labels.app}"`.
+	//
+	// A label replaces the label of a LabelKeys key with the same name.
+	// Label names must match the regular expression "[a-zA-Z_:][a-zA-Z0-9_:]*".
+	//
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Http provides optional extra properties for `type: http`
+//
+// For basic authentication, set secret keys `username` and `password`.
+// For bearer token authentication, set secret key `token`.
+type Http struct {
+	// Method is the HTTP method used to send log records, `POST` or `PUT`.
+	//
+	// If unspecified, `POST` is used.
+	//
+	// +kubebuilder:validation:Enum:=POST;PUT
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// Headers are additional HTTP headers sent with every request.
+	//
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Format of the request payload.
+	//
+	// Format values can be one of:
+	//  - json: a JSON array of log records
+	//  - ndjson: newline-delimited JSON log records
+	//
+	// If unspecified, `ndjson` is used.
+	//
+	// +kubebuilder:validation:Enum:=json;ndjson
+	// +optional
+	Format string `json:"format,omitempty"`
+
+	// Compression of the request payload, `none` or `gzip`.
+	//
+	// If unspecified, the payload is not compressed.
+	//
+	// +kubebuilder:validation:Enum:=none;gzip
+	// +optional
+	Compression string `json:"compression,omitempty"`
+}
+
+// Splunk provides optional extra properties for `type: splunk`
+//
+// The HTTP Event Collector (HEC) token is read from secret key `hecToken`.
+//
+// Index, Source and SourceType may reference fields of the log record as `{.field.path}`,
+// for example `{.kubernetes.namespace_name}` or `app-{.kubernetes.labels.app}`.
+type Splunk struct {
+	// Index is the Splunk index events are sent to.
+	//
+	// If unspecified, the default index of the HEC token is used.
+	//
+	// +optional
+	Index string `json:"index,omitempty"`
+
+	// Source is the Splunk source of the events.
+	//
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// SourceType is the Splunk sourcetype of the events.
+	//
+	// +optional
+	SourceType string `json:"sourceType,omitempty"`
+}
+
+// GoogleCloudLogging provides configuration for the output type `googleCloudLogging`
+//
+// The service account credentials are read from secret key `google-application-credentials.json`.
+// Exactly one of ProjectID, FolderID, OrganizationID or BillingAccountID must be set.
+// The output URL is optional and overrides the Google Cloud Logging API endpoint.
+//
+// The fluentd collector only supports ProjectID, and all its Google Cloud Logging outputs must use the same secret.
+type GoogleCloudLogging struct {
+	// +optional
+	BillingAccountID string `json:"billingAccountId,omitempty"`
+
+	// +optional
+	OrganizationID string `json:"organizationId,omitempty"`
+
+	// +optional
+	FolderID string `json:"folderId,omitempty"`
+
+	// +optional
+	ProjectID string `json:"projectId,omitempty"`
+
+	// LogID is the log ID to which logs are published.
+	//
+	// Fields of the log record may be referenced as `{.field.path}`, for example `app-{.kubernetes.namespace_name}`.
+	//
+	// +required
+	LogID string `json:"logId"`
 }
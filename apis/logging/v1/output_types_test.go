@@ -14,6 +14,8 @@ var _ = Describe("OutputSpec", func() {
 			OutputTypeSyslog,
 			OutputTypeCloudwatch,
 			OutputTypeLoki,
+			OutputTypeSplunk,
+			OutputTypeGoogleCloudLogging,
 		} {
 			Expect(IsOutputTypeName(s)).To(BeTrue(), "expect recognize %s", s)
 		}
@@ -7,6 +7,7 @@ package v1
 import (
 	"github.com/openshift/cluster-logging-operator/internal/status"
 	loggingv1 "github.com/openshift/elasticsearch-operator/pkg/apis/logging/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -21,11 +22,26 @@ func (in *Application) DeepCopyInto(out *Application) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExcludeNamespaces != nil {
+		in, out := &in.ExcludeNamespaces, &out.ExcludeNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Selector != nil {
 		in, out := &in.Selector, &out.Selector
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DetectExceptions != nil {
+		in, out := &in.DetectExceptions, &out.DetectExceptions
+		*out = new(DetectExceptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimit)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Application.
@@ -41,6 +57,11 @@ func (in *Application) DeepCopy() *Application {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Audit) DeepCopyInto(out *Audit) {
 	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Audit.
@@ -61,6 +82,16 @@ func (in *Cloudwatch) DeepCopyInto(out *Cloudwatch) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.CreateLogGroup != nil {
+		in, out := &in.CreateLogGroup, &out.CreateLogGroup
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RetentionInDays != nil {
+		in, out := &in.RetentionInDays, &out.RetentionInDays
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Cloudwatch.
@@ -486,6 +517,26 @@ func (in *CuratorStatus) DeepCopy() *CuratorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DetectExceptions) DeepCopyInto(out *DetectExceptions) {
+	*out = *in
+	if in.Languages != nil {
+		in, out := &in.Languages, &out.Languages
+		*out = make([]ExceptionLanguage, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DetectExceptions.
+func (in *DetectExceptions) DeepCopy() *DetectExceptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DetectExceptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Elasticsearch) DeepCopyInto(out *Elasticsearch) {
 	*out = *in
@@ -668,6 +719,56 @@ func (in *EventCollectionStatus) DeepCopy() *EventCollectionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldsSpec) DeepCopyInto(out *FieldsSpec) {
+	*out = *in
+	if in.Keep != nil {
+		in, out := &in.Keep, &out.Keep
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Prune != nil {
+		in, out := &in.Prune, &out.Prune
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rename != nil {
+		in, out := &in.Rename, &out.Rename
+		*out = make([]RenameField, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldsSpec.
+func (in *FieldsSpec) DeepCopy() *FieldsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilterSpec) DeepCopyInto(out *FilterSpec) {
+	*out = *in
+	if in.Levels != nil {
+		in, out := &in.Levels, &out.Levels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilterSpec.
+func (in *FilterSpec) DeepCopy() *FilterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FilterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FluentdBufferSpec) DeepCopyInto(out *FluentdBufferSpec) {
 	*out = *in
@@ -846,6 +947,37 @@ func (in *FluentdSpec) DeepCopyInto(out *FluentdSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(appsv1.DaemonSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodLabels != nil {
+		in, out := &in.PodLabels, &out.PodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FluentdSpec.
@@ -878,9 +1010,51 @@ func (in *ForwarderSpec) DeepCopy() *ForwarderSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoogleCloudLogging) DeepCopyInto(out *GoogleCloudLogging) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleCloudLogging.
+func (in *GoogleCloudLogging) DeepCopy() *GoogleCloudLogging {
+	if in == nil {
+		return nil
+	}
+	out := new(GoogleCloudLogging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Http) DeepCopyInto(out *Http) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Http.
+func (in *Http) DeepCopy() *Http {
+	if in == nil {
+		return nil
+	}
+	out := new(Http)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Infrastructure) DeepCopyInto(out *Infrastructure) {
 	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Infrastructure.
@@ -904,12 +1078,12 @@ func (in *InputSpec) DeepCopyInto(out *InputSpec) {
 	if in.Infrastructure != nil {
 		in, out := &in.Infrastructure, &out.Infrastructure
 		*out = new(Infrastructure)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Audit != nil {
 		in, out := &in.Audit, &out.Audit
 		*out = new(Audit)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -931,6 +1105,13 @@ func (in *Kafka) DeepCopyInto(out *Kafka) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kafka.
@@ -1039,6 +1220,11 @@ func (in *KibanaStatus) DeepCopy() *KibanaStatus {
 func (in *LogCollectionSpec) DeepCopyInto(out *LogCollectionSpec) {
 	*out = *in
 	in.FluentdSpec.DeepCopyInto(&out.FluentdSpec)
+	if in.RolloutPolicy != nil {
+		in, out := &in.RolloutPolicy, &out.RolloutPolicy
+		*out = new(RolloutPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogCollectionSpec.
@@ -1067,6 +1253,65 @@ func (in *LogCollectionStatus) DeepCopy() *LogCollectionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogForwarder) DeepCopyInto(out *LogForwarder) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogForwarder.
+func (in *LogForwarder) DeepCopy() *LogForwarder {
+	if in == nil {
+		return nil
+	}
+	out := new(LogForwarder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LogForwarder) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogForwarderList) DeepCopyInto(out *LogForwarderList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LogForwarder, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogForwarderList.
+func (in *LogForwarderList) DeepCopy() *LogForwarderList {
+	if in == nil {
+		return nil
+	}
+	out := new(LogForwarderList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LogForwarderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogStoreSpec) DeepCopyInto(out *LogStoreSpec) {
 	*out = *in
@@ -1118,6 +1363,13 @@ func (in *Loki) DeepCopyInto(out *Loki) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Loki.
@@ -1217,6 +1469,11 @@ func (in *OutputSpec) DeepCopyInto(out *OutputSpec) {
 		*out = new(OutputSecretSpec)
 		**out = **in
 	}
+	if in.Buffer != nil {
+		in, out := &in.Buffer, &out.Buffer
+		*out = new(FluentdBufferSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputSpec.
@@ -1262,6 +1519,21 @@ func (in *OutputTypeSpec) DeepCopyInto(out *OutputTypeSpec) {
 		*out = new(Loki)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Http != nil {
+		in, out := &in.Http, &out.Http
+		*out = new(Http)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Splunk != nil {
+		in, out := &in.Splunk, &out.Splunk
+		*out = new(Splunk)
+		**out = **in
+	}
+	if in.GoogleCloudLogging != nil {
+		in, out := &in.GoogleCloudLogging, &out.GoogleCloudLogging
+		*out = new(GoogleCloudLogging)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputTypeSpec.
@@ -1294,6 +1566,18 @@ func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]FilterSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = new(FieldsSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSpec.
@@ -1355,6 +1639,36 @@ func (in *ProxySpec) DeepCopy() *ProxySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimit) DeepCopyInto(out *RateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimit.
+func (in *RateLimit) DeepCopy() *RateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenameField) DeepCopyInto(out *RenameField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenameField.
+func (in *RenameField) DeepCopy() *RenameField {
+	if in == nil {
+		return nil
+	}
+	out := new(RenameField)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RetentionPoliciesSpec) DeepCopyInto(out *RetentionPoliciesSpec) {
 	*out = *in
@@ -1400,6 +1714,28 @@ func (in *RetentionPolicySpec) DeepCopy() *RetentionPolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutPolicySpec) DeepCopyInto(out *RolloutPolicySpec) {
+	*out = *in
+	if in.CanaryNodeSelector != nil {
+		in, out := &in.CanaryNodeSelector, &out.CanaryNodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutPolicySpec.
+func (in *RolloutPolicySpec) DeepCopy() *RolloutPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in RouteMap) DeepCopyInto(out *RouteMap) {
 	{
@@ -1480,6 +1816,21 @@ func (in *Routes) DeepCopy() *Routes {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Splunk) DeepCopyInto(out *Splunk) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Splunk.
+func (in *Splunk) DeepCopy() *Splunk {
+	if in == nil {
+		return nil
+	}
+	out := new(Splunk)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Syslog) DeepCopyInto(out *Syslog) {
 	*out = *in
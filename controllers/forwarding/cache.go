@@ -0,0 +1,80 @@
+package forwarding
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/log"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewCache creates the manager cache. Objects are cached in the namespace of the manager,
+// except LogForwarders and Nodes which are cached for the whole cluster.
+func NewCache(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+	namespaced, err := cache.New(config, opts)
+	if err != nil {
+		return nil, err
+	}
+	opts.Namespace = ""
+	clusterWide, err := cache.New(config, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &forwarderCache{Cache: namespaced, clusterWide: clusterWide}, nil
+}
+
+// forwarderCache delegates LogForwarders and Nodes to a cache for the whole cluster
+type forwarderCache struct {
+	cache.Cache
+	clusterWide cache.Cache
+}
+
+func (c *forwarderCache) cacheFor(obj runtime.Object) cache.Cache {
+	switch obj.(type) {
+	case *logging.LogForwarder, *logging.LogForwarderList, *corev1.Node, *corev1.NodeList:
+		return c.clusterWide
+	}
+	return c.Cache
+}
+
+func (c *forwarderCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	return c.cacheFor(obj).Get(ctx, key, obj)
+}
+
+func (c *forwarderCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	return c.cacheFor(list).List(ctx, list, opts...)
+}
+
+func (c *forwarderCache) GetInformer(ctx context.Context, obj runtime.Object) (cache.Informer, error) {
+	return c.cacheFor(obj).GetInformer(ctx, obj)
+}
+
+func (c *forwarderCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind) (cache.Informer, error) {
+	switch gvk.GroupKind() {
+	case logging.GroupVersion.WithKind(logging.LogForwarderKind).GroupKind(), corev1.SchemeGroupVersion.WithKind("Node").GroupKind():
+		return c.clusterWide.GetInformerForKind(ctx, gvk)
+	}
+	return c.Cache.GetInformerForKind(ctx, gvk)
+}
+
+func (c *forwarderCache) IndexField(ctx context.Context, obj runtime.Object, field string, extractValue client.IndexerFunc) error {
+	return c.cacheFor(obj).IndexField(ctx, obj, field, extractValue)
+}
+
+func (c *forwarderCache) Start(stop <-chan struct{}) error {
+	go func() {
+		if err := c.clusterWide.Start(stop); err != nil {
+			log.Error(err, "Cluster wide cache exited non-zero")
+		}
+	}()
+	return c.Cache.Start(stop)
+}
+
+func (c *forwarderCache) WaitForCacheSync(stop <-chan struct{}) bool {
+	return c.Cache.WaitForCacheSync(stop) && c.clusterWide.WaitForCacheSync(stop)
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/ViaQ/logerr/log"
 	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
-	"github.com/openshift/cluster-logging-operator/internal/constants"
 	"github.com/openshift/cluster-logging-operator/internal/k8shandler"
 	"github.com/openshift/cluster-logging-operator/internal/status"
 	corev1 "k8s.io/api/core/v1"
@@ -72,18 +71,10 @@ var (
 
 var condReady = status.Condition{Type: logging.ConditionReady, Status: corev1.ConditionTrue}
 
-func condNotReady(r status.ConditionReason, format string, args ...interface{}) status.Condition {
-	return logging.NewCondition(logging.ConditionReady, corev1.ConditionFalse, r, format, args...)
-}
-
 func condDegraded(r status.ConditionReason, format string, args ...interface{}) status.Condition {
 	return logging.NewCondition(logging.ConditionReady, corev1.ConditionTrue, r, format, args...)
 }
 
-func condInvalid(format string, args ...interface{}) status.Condition {
-	return condNotReady(logging.ReasonInvalid, format, args...)
-}
-
 // Reconcile reads that state of the cluster for a ClusterLogForwarder object and makes changes based on the state read
 // and what is in the Logging.Spec
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
@@ -99,8 +90,8 @@ func (r *ReconcileForwarder) Reconcile(request reconcile.Request) (reconcile.Res
 			// Error reading - requeue the request.
 			return reconcileResult, err
 		}
-		// else the object is not found -- meaning it was removed so stop reconciliation
-		return reconcile.Result{}, nil
+		// else the object is not found -- meaning it was removed so drop its pipelines from the collector
+		return reconcile.Result{}, k8shandler.ReconcileForClusterLogForwarder(nil, r.Client)
 	}
 
 	log.V(3).Info("clusterlogforwarder-controller run reconciler...")
@@ -110,12 +101,6 @@ func (r *ReconcileForwarder) Reconcile(request reconcile.Request) (reconcile.Res
 		log.V(2).Error(reconcileErr, "clusterlogforwarder-controller returning, error")
 	}
 
-	//check for instancename and then update status
-	if instance.Name != constants.SingletonName {
-		instance.Status.Conditions.SetCondition(condInvalid("Invalid name %q, singleton instance must be named %q", instance.Name, constants.SingletonName))
-		return r.updateStatus(instance)
-	}
-
 	if instance.Status.IsReady() {
 		if instance.Status.Conditions.SetCondition(condReady) {
 			r.Recorder.Event(instance, "Normal", string(condReady.Type), "All pipelines are valid")
@@ -0,0 +1,97 @@
+package forwarding
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+var (
+	testEnv   *envtest.Environment
+	k8sClient client.Client
+	stop      chan struct{}
+)
+
+func TestForwarding(t *testing.T) {
+	// envtest runs a local kube-apiserver and etcd, see https://book.kubebuilder.io/reference/envtest.html
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		if _, err := os.Stat("/usr/local/kubebuilder/bin/kube-apiserver"); err != nil {
+			t.Skip("envtest assets not found, run make test-envtest or set KUBEBUILDER_ASSETS to run the webhook tests")
+		}
+	}
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Forwarding Suite")
+}
+
+var _ = BeforeSuite(func() {
+	testEnv = &envtest.Environment{
+		CRDDirectoryPaths: []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			DirectoryPaths: []string{filepath.Join("..", "..", "config", "webhook")},
+		},
+	}
+	cfg, err := testEnv.Start()
+	Expect(err).To(BeNil())
+
+	scheme := runtime.NewScheme()
+	Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	Expect(logging.AddToScheme(scheme)).To(Succeed())
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme})
+	Expect(err).To(BeNil())
+	Expect(k8sClient.Create(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: constants.OpenshiftNS}})).To(Succeed())
+
+	webhookOptions := &testEnv.WebhookInstallOptions
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:             scheme,
+		Namespace:          constants.OpenshiftNS,
+		NewCache:           NewCache,
+		Host:               webhookOptions.LocalServingHost,
+		Port:               webhookOptions.LocalServingPort,
+		CertDir:            webhookOptions.LocalServingCertDir,
+		MetricsBindAddress: "0",
+		LeaderElection:     false,
+	})
+	Expect(err).To(BeNil())
+	Expect(SetupWebhookWithManager(mgr)).To(Succeed())
+
+	stop = make(chan struct{})
+	go func() {
+		defer GinkgoRecover()
+		Expect(mgr.Start(stop)).To(Succeed())
+	}()
+
+	// Wait for the webhook server to serve
+	address := fmt.Sprintf("%s:%d", webhookOptions.LocalServingHost, webhookOptions.LocalServingPort)
+	Eventually(func() error {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", address, &tls.Config{InsecureSkipVerify: true}) // #nosec G402
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+}, 120)
+
+var _ = AfterSuite(func() {
+	if stop != nil {
+		close(stop)
+	}
+	Expect(testEnv.Stop()).To(Succeed())
+})
@@ -0,0 +1,78 @@
+package forwarding
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/log"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/k8shandler"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ reconcile.Reconciler = &ReconcileLogForwarder{}
+
+// ReconcileLogForwarder reconciles a LogForwarder object
+type ReconcileLogForwarder struct {
+	// This Client, initialized using mgr.Client(), reads LogForwarders of all namespaces
+	// from the cache (see NewCache) and writes to the apiserver
+	Client   client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// Reconcile reads that state of the cluster for a LogForwarder object and regenerates the collector
+// configuration with the pipelines of all forwarders.
+func (r *ReconcileLogForwarder) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	log.V(3).Info("logforwarder-controller fetching LF instance", "namespace", request.Namespace)
+
+	// Fetch the LogForwarder instance
+	instance := &logging.LogForwarder{}
+	if err := r.Client.Get(context.TODO(), request.NamespacedName, instance); err != nil {
+		log.V(2).Info("logforwarder-controller Error getting instance. It will be retried if other then 'NotFound'", "error", err)
+		if !errors.IsNotFound(err) {
+			// Error reading - requeue the request.
+			return reconcileResult, err
+		}
+		// else the object is not found -- meaning it was removed so drop its pipelines from the collector
+		return reconcile.Result{}, k8shandler.ReconcileForLogForwarder(nil, r.Client)
+	}
+
+	log.V(3).Info("logforwarder-controller run reconciler...")
+
+	reconcileErr := k8shandler.ReconcileForLogForwarder(instance, r.Client)
+	if reconcileErr != nil {
+		log.V(2).Error(reconcileErr, "logforwarder-controller returning, error")
+	}
+
+	if instance.Status.IsReady() {
+		if instance.Status.Conditions.SetCondition(condReady) {
+			r.Recorder.Event(instance, "Normal", string(condReady.Type), "All pipelines are valid")
+		}
+	}
+
+	if instance.Status.IsDegraded() {
+		msg := "Some pipelines are degraded or invalid"
+		if instance.Status.Conditions.SetCondition(condDegraded(logging.ReasonInvalid, msg)) {
+			r.Recorder.Event(instance, "Error", string(logging.ReasonInvalid), msg)
+		}
+	}
+
+	if err := r.Client.Status().Update(context.TODO(), instance); err != nil {
+		log.Error(err, "logforwarder-controller error updating status")
+		return reconcileResult, err
+	}
+
+	return reconcile.Result{}, reconcileErr
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ReconcileLogForwarder) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&logging.LogForwarder{}).
+		Complete(r)
+}
@@ -0,0 +1,73 @@
+package forwarding
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ViaQ/logerr/log"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/k8shandler"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-logging-openshift-io-v1-clusterlogforwarder,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1beta1,groups=logging.openshift.io,resources=clusterlogforwarders,verbs=create;update,versions=v1,name=vclusterlogforwarder.logging.openshift.io
+// +kubebuilder:webhook:path=/validate-logging-openshift-io-v1-logforwarder,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1beta1,groups=logging.openshift.io,resources=logforwarders,verbs=create;update,versions=v1,name=vlogforwarder.logging.openshift.io
+
+const (
+	ValidateClusterLogForwarderPath = "/validate-logging-openshift-io-v1-clusterlogforwarder"
+	ValidateLogForwarderPath        = "/validate-logging-openshift-io-v1-logforwarder"
+)
+
+// SetupWebhookWithManager registers the validating webhooks for ClusterLogForwarder and LogForwarder
+// with the webhook server of the manager.
+func SetupWebhookWithManager(mgr ctrl.Manager) error {
+	server := mgr.GetWebhookServer()
+	server.Register(ValidateClusterLogForwarderPath, &webhook.Admission{Handler: &ForwarderValidator{Client: mgr.GetClient()}})
+	server.Register(ValidateLogForwarderPath, &webhook.Admission{Handler: &ForwarderValidator{Client: mgr.GetClient()}})
+	return nil
+}
+
+var _ admission.Handler = &ForwarderValidator{}
+var _ admission.DecoderInjector = &ForwarderValidator{}
+
+// ForwarderValidator rejects ClusterLogForwarders and LogForwarders with invalid inputs, outputs or pipelines
+// before they are persisted, using the verification applied when generating the collector configuration.
+type ForwarderValidator struct {
+	Client  client.Client
+	decoder *admission.Decoder
+}
+
+// InjectDecoder injects the decoder for admission requests
+func (v *ForwarderValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// Handle validates the forwarder of an admission request
+func (v *ForwarderValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var err error
+	switch req.Kind.Kind {
+	case logging.ClusterLogForwarderKind:
+		forwarder := &logging.ClusterLogForwarder{}
+		if err := v.decoder.Decode(req, forwarder); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		err = k8shandler.ValidateClusterLogForwarder(forwarder, v.Client)
+	case logging.LogForwarderKind:
+		forwarder := &logging.LogForwarder{}
+		if err := v.decoder.Decode(req, forwarder); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		err = k8shandler.ValidateLogForwarder(forwarder, v.Client)
+	default:
+		return admission.Allowed("")
+	}
+	if err != nil {
+		log.V(3).Info("forwarder-webhook rejected forwarder", "kind", req.Kind.Kind, "namespace", req.Namespace, "name", req.Name, "reason", err.Error())
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
@@ -0,0 +1,101 @@
+package forwarding
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Validating forwarders on admission", func() {
+	var (
+		forwarder *logging.ClusterLogForwarder
+	)
+	BeforeEach(func() {
+		forwarder = &logging.ClusterLogForwarder{
+			ObjectMeta: metav1.ObjectMeta{Name: constants.SingletonName, Namespace: constants.OpenshiftNS},
+			Spec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{Name: "es", Type: logging.OutputTypeElasticsearch, URL: "http://es.svc:9200"},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{Name: "apps", InputRefs: []string{logging.InputNameApplication}, OutputRefs: []string{"es"}},
+				},
+			},
+		}
+	})
+	AfterEach(func() {
+		_ = k8sClient.Delete(context.TODO(), forwarder)
+	})
+
+	It("should accept a valid ClusterLogForwarder", func() {
+		Expect(k8sClient.Create(context.TODO(), forwarder)).To(Succeed())
+	})
+
+	It("should reject duplicate output names", func() {
+		forwarder.Spec.Outputs = append(forwarder.Spec.Outputs, forwarder.Spec.Outputs[0])
+		err := k8sClient.Create(context.TODO(), forwarder)
+		Expect(err).To(MatchError(ContainSubstring(`output output_1_: duplicate name: "es"`)))
+	})
+
+	It("should reject unknown input and output references", func() {
+		forwarder.Spec.Pipelines[0].InputRefs = append(forwarder.Spec.Pipelines[0].InputRefs, "nosuchinput")
+		forwarder.Spec.Pipelines[0].OutputRefs = append(forwarder.Spec.Pipelines[0].OutputRefs, "nosuchoutput")
+		err := k8sClient.Create(context.TODO(), forwarder)
+		Expect(err).To(MatchError(ContainSubstring("pipeline apps: invalid: unrecognized inputs: [nosuchinput], unrecognized outputs: [nosuchoutput]")))
+	})
+
+	It("should reject malformed URLs", func() {
+		forwarder.Spec.Outputs[0].URL = "es.svc:9200"
+		err := k8sClient.Create(context.TODO(), forwarder)
+		Expect(err).To(MatchError(ContainSubstring(`output es: invalid URL: no host: "es.svc:9200"`)))
+	})
+
+	It("should reject templates evaluating ruby outside of record field references", func() {
+		forwarder.Spec.Outputs[0].Elasticsearch = &logging.Elasticsearch{Index: "app-#{`id`}-{.log_type}"}
+		err := k8sClient.Create(context.TODO(), forwarder)
+		Expect(err).To(MatchError(ContainSubstring(`index "app-#{` + "`id`" + `}-{.log_type}" may only be literal text and log record field references`)))
+	})
+
+	It("should reject missing secrets and accept them once created", func() {
+		forwarder.Spec.Outputs[0].URL = "https://es.svc:9200"
+		forwarder.Spec.Outputs[0].Secret = &logging.OutputSecretSpec{Name: "es-secret"}
+		err := k8sClient.Create(context.TODO(), forwarder)
+		Expect(err).To(MatchError(ContainSubstring(`output es: secret "es-secret" not found`)))
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "es-secret", Namespace: constants.OpenshiftNS},
+			Data: map[string][]byte{
+				"tls.crt":       []byte("cert"),
+				"tls.key":       []byte("key"),
+				"ca-bundle.crt": []byte("ca"),
+			},
+		}
+		Expect(k8sClient.Create(context.TODO(), secret)).To(Succeed())
+		defer func() { _ = k8sClient.Delete(context.TODO(), secret) }()
+		Eventually(func() error {
+			return k8sClient.Create(context.TODO(), forwarder)
+		}, 10, 0.5).Should(Succeed())
+	})
+
+	It("should reject a LogForwarder selecting logs outside of its namespace", func() {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-app"}}
+		Expect(k8sClient.Create(context.TODO(), namespace)).To(Succeed())
+		lf := &logging.LogForwarder{
+			ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: namespace.Name},
+			Spec:       forwarder.Spec,
+		}
+		lf.Spec.Pipelines = []logging.PipelineSpec{
+			{Name: "infra", InputRefs: []string{logging.InputNameInfrastructure}, OutputRefs: []string{"es"}},
+		}
+		err := k8sClient.Create(context.TODO(), lf)
+		Expect(err).To(MatchError(ContainSubstring("pipeline infra: invalid: unrecognized inputs: [infrastructure]")))
+
+		lf.Spec.Pipelines[0].InputRefs = []string{logging.InputNameApplication}
+		Expect(k8sClient.Create(context.TODO(), lf)).To(Succeed())
+	})
+})
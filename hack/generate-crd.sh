@@ -7,8 +7,8 @@ set -euo pipefail
 BUNDLE_DIR=${1:-"bundle/manifests"}
 CLF_CRD_FILE="logging.openshift.io_clusterlogforwarders_crd.yaml"
 CLO_CRD_FILE="logging.openshift.io_clusterloggings_crd.yaml"
+LF_CRD_FILE="logging.openshift.io_logforwarders_crd.yaml"
 CLO_PATCH_FILE="crd-v1-clusterloggings-patches.yaml"
-CLF_PATCH_FILE="crd-v1-singleton-patch.yaml"
 KUSTOMIZATIONS_FILE="kustomization.yaml"
 
 BUNDLE_VERSION=${LOGGING_VERSION}.0
@@ -30,9 +30,9 @@ $OPERATOR_SDK generate kustomize manifests -q
 rm ${BUNDLE_DIR}/cluster-logging-operator.clusterserviceversion.yaml
 mv ${BUNDLE_DIR}/logging.openshift.io_clusterlogforwarders.yaml ${BUNDLE_DIR}/${CLF_CRD_FILE}
 mv ${BUNDLE_DIR}/logging.openshift.io_clusterloggings.yaml ${BUNDLE_DIR}/${CLO_CRD_FILE}
+mv ${BUNDLE_DIR}/logging.openshift.io_logforwarders.yaml ${BUNDLE_DIR}/${LF_CRD_FILE}
 
 cp manifests/patches/${CLO_PATCH_FILE} ${BUNDLE_DIR}
-cp manifests/patches/${CLF_PATCH_FILE} ${BUNDLE_DIR}
 cp manifests/patches/${KUSTOMIZATIONS_FILE} ${BUNDLE_DIR}
 
 echo "---------------------------------------------------------------"
@@ -45,11 +45,11 @@ oc kustomize "${BUNDLE_DIR}" | \
 
 cp ${BUNDLE_DIR}/${CLF_CRD_FILE}  manifests/${LOGGING_VERSION}/${CLF_CRD_FILE}
 cp ${BUNDLE_DIR}/${CLO_CRD_FILE}  manifests/${LOGGING_VERSION}/${CLO_CRD_FILE}
+cp ${BUNDLE_DIR}/${LF_CRD_FILE}  manifests/${LOGGING_VERSION}/${LF_CRD_FILE}
 echo "---------------------------------------------------------------"
 echo "Cleanup operator-sdk generation folder"
 echo "---------------------------------------------------------------"
 rm -rf deploy
 rm ${BUNDLE_DIR}/${CLO_PATCH_FILE}
-rm ${BUNDLE_DIR}/${CLF_PATCH_FILE}
 rm ${BUNDLE_DIR}/${KUSTOMIZATIONS_FILE}
 
@@ -4,29 +4,39 @@ const (
 	SingletonName = "instance"
 	OpenshiftNS   = "openshift-logging"
 	// global proxy / trusted ca bundle consts
-	ProxyName                  = "cluster"
-	SharedKey                  = "shared_key"
-	Passphrase                 = "passphrase"
-	TrustedCABundleKey         = "ca-bundle.crt"
-	SaslOverSSL                = "sasl_over_ssl"
-	AWSSecretAccessKey         = "aws_secret_access_key" //nolint:gosec
-	AWSAccessKeyID             = "aws_access_key_id"
-	ClientCertKey              = "tls.crt"
-	ClientPrivateKey           = "tls.key"
-	ClientUsername             = "username"
-	ClientPassword             = "password"
-	InjectTrustedCABundleLabel = "config.openshift.io/inject-trusted-cabundle"
-	TrustedCABundleMountFile   = "tls-ca-bundle.pem"
-	TrustedCABundleMountDir    = "/etc/pki/ca-trust/extracted/pem/"
-	TrustedCABundleHashName    = "logging.openshift.io/hash"
-	SecretHashPrefix           = "logging.openshift.io/"
-	KibanaTrustedCAName        = "kibana-trusted-ca-bundle"
+	ProxyName                       = "cluster"
+	SharedKey                       = "shared_key"
+	Passphrase                      = "passphrase"
+	TrustedCABundleKey              = "ca-bundle.crt"
+	SaslOverSSL                     = "sasl_over_ssl"
+	SaslMechanism                   = "sasl_mechanism"
+	SaslMechanismPlain              = "PLAIN"
+	SaslMechanismScramSHA256        = "SCRAM-SHA-256"
+	SaslMechanismScramSHA512        = "SCRAM-SHA-512"
+	AWSSecretAccessKey              = "aws_secret_access_key" //nolint:gosec
+	AWSAccessKeyID                  = "aws_access_key_id"
+	AWSWebIdentityRoleKey           = "role_arn"
+	ClientCertKey                   = "tls.crt"
+	ClientPrivateKey                = "tls.key"
+	ClientUsername                  = "username"
+	ClientPassword                  = "password"
+	BearerTokenFileKey              = "token"
+	SplunkHECTokenKey               = "hecToken"
+	ElasticsearchAPIKey             = "apiKey"
+	GoogleApplicationCredentialsKey = "google-application-credentials.json"
+	InjectTrustedCABundleLabel      = "config.openshift.io/inject-trusted-cabundle"
+	TrustedCABundleMountFile        = "tls-ca-bundle.pem"
+	TrustedCABundleMountDir         = "/etc/pki/ca-trust/extracted/pem/"
+	TrustedCABundleHashName         = "logging.openshift.io/hash"
+	SecretHashPrefix                = "logging.openshift.io/"
+	KibanaTrustedCAName             = "kibana-trusted-ca-bundle"
 	// internal elasticsearch FQDN to prevent to connect to the global proxy
 	ElasticsearchFQDN          = "elasticsearch.openshift-logging.svc"
 	ElasticsearchName          = "elasticsearch"
 	ElasticsearchPort          = "9200"
 	FluentdName                = "fluentd"
 	KibanaName                 = "kibana"
+	VectorName                 = "vector"
 	KibanaProxyName            = "kibana-proxy"
 	CuratorName                = "curator"
 	LogfilesmetricexporterName = "logfilesmetricexporter"
@@ -36,6 +46,9 @@ const (
 	// Disable gosec linter, complains "possible hard-coded secret"
 	CollectorSecretsDir     = "/var/run/ocp-collector/secrets" //nolint:gosec
 	KibanaSessionSecretName = "kibana-session-secret"          //nolint:gosec
+	// projected service account token of the collector, e.g. for AWS STS web identity
+	CollectorServiceAccountTokenVolume = "bound-sa-token"
+	CollectorServiceAccountTokenDir    = "/var/run/ocp-collector/serviceaccount"
 
 	CollectorName             = "collector"
 	CollectorMetricSecretName = "collector-metrics"
@@ -46,6 +59,7 @@ const (
 	LegacySyslog        = "_LEGACY_SYSLOG"
 
 	FluentdImageEnvVar            = "FLUENTD_IMAGE"
+	VectorImageEnvVar             = "VECTOR_IMAGE"
 	LogfilesmetricImageEnvVar     = "LOGFILEMETRICEXPORTER_IMAGE"
 	CertEventName                 = "cluster-logging-certs-generate"
 	ClusterInfrastructureInstance = "cluster"
@@ -0,0 +1,27 @@
+package elements
+
+import (
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+)
+
+// DetectExceptions groups the lines of multiline exception stack traces into a single record
+type DetectExceptions struct {
+	generator.OutLabel
+	Languages generator.Element
+}
+
+func (d DetectExceptions) Name() string {
+	return "detectExceptions"
+}
+
+func (d DetectExceptions) Template() string {
+	return `{{define "` + d.Name() + `"  -}}
+@type detect_exceptions
+@label {{.OutLabel}}
+message log
+force_line_breaks true
+{{kv .Languages -}}
+multiline_flush_interval 0.2
+max_lines 1000
+{{end}}`
+}
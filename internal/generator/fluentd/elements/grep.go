@@ -0,0 +1,29 @@
+package elements
+
+type Grep struct {
+	Exclude bool
+	Key     string
+	Pattern string
+}
+
+func (g Grep) Name() string {
+	return "grepTemplate"
+}
+
+func (g Grep) Template() string {
+	return `{{define "` + g.Name() + `"  -}}
+@type grep
+{{if .Exclude -}}
+<exclude>
+{{- else -}}
+<regexp>
+{{- end}}
+  key {{.Key}}
+  pattern /{{.Pattern}}/
+{{if .Exclude -}}
+</exclude>
+{{- else -}}
+</regexp>
+{{- end}}
+{{end}}`
+}
@@ -6,6 +6,10 @@ type Record struct {
 }
 
 type RecordTransformer struct {
+	// RenewRecord replaces the record with the KeepKeys and Records instead of adding them
+	RenewRecord bool
+	// KeepKeys are the top level keys copied to a renewed record, absent keys are skipped
+	KeepKeys   []string
 	Records    []Record
 	RemoveKeys []string
 }
@@ -17,6 +21,12 @@ func (rm RecordTransformer) Name() string {
 func (rm RecordTransformer) Template() string {
 	return `{{define "` + rm.Name() + `"  -}}
 @type record_transformer
+{{if .RenewRecord -}}
+renew_record true
+{{end -}}
+{{if .KeepKeys -}}
+keep_keys {{comma_separated .KeepKeys}}
+{{end -}}
 {{if .Records -}}
 enable_ruby true
 <record>
@@ -0,0 +1,24 @@
+package elements
+
+// Throttle drops the records exceeding the limit of each group of records with the same values of the group keys
+type Throttle struct {
+	// GroupKey is a comma separated list of record keys
+	GroupKey      string
+	PeriodSeconds int64
+	Limit         int64
+}
+
+func (t Throttle) Name() string {
+	return "throttleTemplate"
+}
+
+func (t Throttle) Template() string {
+	return `{{define "` + t.Name() + `"  -}}
+@type throttle
+group_key {{.GroupKey}}
+group_bucket_period_s {{.PeriodSeconds}}
+group_bucket_limit {{.Limit}}
+group_drop_logs true
+group_warning_delay_s 10
+{{end}}`
+}
@@ -2,9 +2,11 @@ package helpers
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 
+	genhelper "github.com/openshift/cluster-logging-operator/internal/generator/helpers"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -28,6 +30,11 @@ func SourceTypeLabelName(name string) string {
 
 func LabelsKV(ls *metav1.LabelSelector) []string {
 	m, _ := metav1.LabelSelectorAsMap(ls)
+	return MapKV(m)
+}
+
+// MapKV returns the "<key>:<value>" strings of a map, sorted by key
+func MapKV(m map[string]string) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
 		keys = append(keys, k)
@@ -43,3 +50,122 @@ func LabelsKV(ls *metav1.LabelSelector) []string {
 func StoreID(prefix, name, suffix string) string {
 	return strings.ToLower(fmt.Sprintf("%v%v%v", prefix, Replacer.Replace(name), suffix))
 }
+
+// recordFieldRegex matches the references to log record fields in a template, e.g. {.kubernetes.namespace_name}
+var recordFieldRegex = regexp.MustCompile(`\{\.([^{}]+)\}`)
+
+// IsRecordTemplate returns true if the template references log record fields
+func IsRecordTemplate(template string) bool {
+	return recordFieldRegex.MatchString(template)
+}
+
+// plainTextRegex matches literal text that is safe to embed as is in a record_modifier value
+var plainTextRegex = regexp.MustCompile(`^[-_.:/@=+,a-zA-Z0-9]*$`)
+
+// RecordTemplate converts the log record field references of a template to embedded ruby,
+// e.g. "app-{.kubernetes.namespace_name}" becomes "app-${record.dig("kubernetes","namespace_name")}".
+// Literal text which is not plain is embedded as an escaped ruby string so it is never evaluated
+func RecordTemplate(template string) string {
+	return RecordTemplateWith(template, nil, nil)
+}
+
+// RecordTemplateWith converts a template like RecordTemplate, additionally replacing the matches of re
+// with the embedded ruby expression returned by expr
+func RecordTemplateWith(template string, re *regexp.Regexp, expr func(string) string) string {
+	b := &strings.Builder{}
+	literal := func(text string) {
+		if re != nil {
+			last := 0
+			for _, loc := range re.FindAllStringIndex(text, -1) {
+				embedText(b, text[last:loc[0]])
+				fmt.Fprintf(b, "${%s}", expr(text[loc[0]:loc[1]]))
+				last = loc[1]
+			}
+			text = text[last:]
+		}
+		embedText(b, text)
+	}
+	last := 0
+	for _, loc := range recordFieldRegex.FindAllStringSubmatchIndex(template, -1) {
+		literal(template[last:loc[0]])
+		fmt.Fprintf(b, "${%s}", RecordDig(template[loc[2]:loc[3]]))
+		last = loc[1]
+	}
+	literal(template[last:])
+	return b.String()
+}
+
+func embedText(b *strings.Builder, text string) {
+	if plainTextRegex.MatchString(text) {
+		b.WriteString(text)
+		return
+	}
+	fmt.Fprintf(b, "${%s}", RubyString(text))
+}
+
+// RubyString returns a double quoted ruby string literal of s. Any character which is not plain is
+// written as a hex escape so the literal never interpolates, e.g. a#{b} becomes "a\x23\x7bb\x7d"
+func RubyString(s string) string {
+	b := &strings.Builder{}
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if plainTextRegex.MatchString(s[i : i+1]) {
+			b.WriteByte(s[i])
+		} else {
+			fmt.Fprintf(b, `\x%02x`, s[i])
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// RecordDig returns the ruby expression digging a log record field out of the record, keeping label keys
+// whole, e.g. kubernetes.labels.app.kubernetes.io/name becomes record.dig("kubernetes","labels","app.kubernetes.io/name")
+func RecordDig(field string) string {
+	keys := []string{}
+	for _, k := range genhelper.FieldPath(field) {
+		keys = append(keys, RubyString(k))
+	}
+	return fmt.Sprintf("record.dig(%s)", strings.Join(keys, ","))
+}
+
+// LiteralText returns the literal text of a template, without its log record field references
+func LiteralText(template string) string {
+	return recordFieldRegex.ReplaceAllString(template, "")
+}
+
+// singleRecordFieldRegex matches a template that is a single reference to a log record field
+var singleRecordFieldRegex = regexp.MustCompile(`^\{\.([^{}]+)\}$`)
+
+// RecordField returns the dot delimited path of the log record field (e.g. kubernetes.pod_id) if the template
+// is a single reference to it (e.g. {.kubernetes.pod_id})
+func RecordField(template string) (string, bool) {
+	m := singleRecordFieldRegex.FindStringSubmatch(template)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// RecordFields returns the dot delimited paths of the log record fields referenced by a template
+func RecordFields(template string) []string {
+	fields := []string{}
+	seen := map[string]bool{}
+	for _, m := range recordFieldRegex.FindAllStringSubmatch(template, -1) {
+		if field := m[1]; !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// RecordAccessor returns the record accessor of a log record field, in bracket notation for a nested field
+// since label keys may contain dots, e.g. $['kubernetes']['labels']['app.kubernetes.io/name']
+func RecordAccessor(field string) string {
+	path := genhelper.FieldPath(field)
+	if len(path) == 1 {
+		return path[0]
+	}
+	return "$['" + strings.Join(path, "']['") + "']"
+}
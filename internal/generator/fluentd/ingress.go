@@ -1,10 +1,14 @@
 package fluentd
 
 import (
+	"fmt"
+	"strings"
+
 	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
 	. "github.com/openshift/cluster-logging-operator/internal/generator"
 	. "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/elements"
 	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/source"
 )
 
 func Concat(spec *logging.ClusterLogForwarderSpec, o Options) []Element {
@@ -12,23 +16,64 @@ func Concat(spec *logging.ClusterLogForwarderSpec, o Options) []Element {
 		Pipeline{
 			InLabel: helpers.LabelName("CONCAT"),
 			Desc:    "Concat log lines of container logs, and send to INGRESS pipeline",
-			SubElements: []Element{
-				ConfLiteral{
-					TemplateName: "concatLines",
-					TemplateStr:  ConcatLines,
-					OutLabel:     helpers.LabelName("INGRESS"),
+			SubElements: MergeElements(
+				[]Element{
+					ConfLiteral{
+						TemplateName: "concatLines",
+						TemplateStr:  ConcatLines,
+						OutLabel:     helpers.LabelName("INGRESS"),
+					},
 				},
-				Match{
-					MatchTags: "kubernetes.**",
-					MatchElement: Relabel{
-						OutLabel: helpers.LabelName("INGRESS"),
+				ExceptionDetectors(spec),
+				[]Element{
+					Match{
+						MatchTags: "kubernetes.**",
+						MatchElement: Relabel{
+							OutLabel: helpers.LabelName("INGRESS"),
+						},
 					},
 				},
-			},
+			),
 		},
 	}
 }
 
+// ExceptionDetectors group the stack traces of the container logs of the application inputs enabling exception
+// detection, and sends them to the INGRESS pipeline. Inputs are matched by namespace, inputs of all namespaces last
+func ExceptionDetectors(spec *logging.ClusterLogForwarderSpec) []Element {
+	namespaced := []Element{}
+	all := []Element{}
+	for _, input := range spec.Inputs {
+		app := input.Application
+		if app == nil || app.DetectExceptions == nil {
+			continue
+		}
+		var languages Element = Nil
+		if len(app.DetectExceptions.Languages) != 0 {
+			names := make([]string, len(app.DetectExceptions.Languages))
+			for i, l := range app.DetectExceptions.Languages {
+				names[i] = string(l)
+			}
+			languages = KV("languages", strings.Join(names, ","))
+		}
+		m := Match{
+			Desc:      fmt.Sprintf("Detect exceptions of input %q", input.Name),
+			MatchTags: source.ApplicationTags,
+			MatchElement: DetectExceptions{
+				OutLabel:  helpers.LabelName("INGRESS"),
+				Languages: languages,
+			},
+		}
+		if len(app.Namespaces) == 0 {
+			all = append(all, m)
+			continue
+		}
+		m.MatchTags = namespaceTags(app.Namespaces)
+		namespaced = append(namespaced, m)
+	}
+	return append(namespaced, all...)
+}
+
 func Ingress(spec *logging.ClusterLogForwarderSpec, o Options) []Element {
 	return []Element{
 		Pipeline{
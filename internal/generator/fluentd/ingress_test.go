@@ -0,0 +1,84 @@
+package fluentd
+
+import (
+	. "github.com/openshift/cluster-logging-operator/test/matchers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+)
+
+var _ = Describe("Generating the concat pipeline", func() {
+	var (
+		g generator.Generator
+	)
+	BeforeEach(func() {
+		g = generator.MakeGenerator()
+	})
+
+	It("should detect exceptions of the application inputs enabling detection", func() {
+		spec := &logging.ClusterLogForwarderSpec{
+			Inputs: []logging.InputSpec{
+				{
+					Name:        "all-apps",
+					Application: &logging.Application{DetectExceptions: &logging.DetectExceptions{}},
+				},
+				{
+					Name: "team-a",
+					Application: &logging.Application{
+						Namespaces: []string{"team-a", "team-a-*"},
+						DetectExceptions: &logging.DetectExceptions{
+							Languages: []logging.ExceptionLanguage{"java", "python"},
+						},
+					},
+				},
+				{
+					Name:        "team-b",
+					Application: &logging.Application{Namespaces: []string{"team-b"}},
+				},
+			},
+		}
+		got, err := g.GenerateConf(Concat(spec, nil)...)
+		Expect(err).To(BeNil())
+		Expect(got).To(EqualTrimLines(`
+# Concat log lines of container logs, and send to INGRESS pipeline
+<label @CONCAT>
+  <filter kubernetes.**>
+    @type concat
+    key log
+    partial_key logtag
+    partial_value P
+    separator ''
+  </filter>
+  
+  # Detect exceptions of input "team-a"
+  <match **_team-a_** **_team-a-*_**>
+    @type detect_exceptions
+    @label @INGRESS
+    message log
+    force_line_breaks true
+    languages java,python
+    multiline_flush_interval 0.2
+    max_lines 1000
+  </match>
+  
+  # Detect exceptions of input "all-apps"
+  <match kubernetes.**>
+    @type detect_exceptions
+    @label @INGRESS
+    message log
+    force_line_breaks true
+    multiline_flush_interval 0.2
+    max_lines 1000
+  </match>
+  
+  <match kubernetes.**>
+    @type relabel
+    @label @INGRESS
+  </match>
+</label>
+`))
+	})
+})
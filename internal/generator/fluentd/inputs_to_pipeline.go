@@ -8,6 +8,9 @@ import (
 	. "github.com/openshift/cluster-logging-operator/internal/generator"
 	. "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/elements"
 	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/source"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 type ApplicationToPipeline struct {
@@ -54,53 +57,145 @@ func (a ApplicationsToPipelines) Template() string {
 {{- end}}`
 }
 
+// SourceTags is a source of logs of an input type and the tags its logs are collected with
+type SourceTags struct {
+	Source string
+	Tags   string
+}
+
+// InputSourceTags lists the sources that can be selected by user defined inputs, by input type
+var InputSourceTags = map[string][]SourceTags{
+	logging.InputNameInfrastructure: {
+		{Source: logging.InfrastructureSourceContainer, Tags: source.InfraContainerTags},
+		{Source: logging.InfrastructureSourceNode, Tags: source.JournalTags},
+	},
+	logging.InputNameAudit: {
+		{Source: logging.AuditSourceAuditd, Tags: source.HostAuditTags},
+		{Source: logging.AuditSourceKube, Tags: source.K8sAuditTags},
+		{Source: logging.AuditSourceOpenShift, Tags: source.OpenshiftAuditTags},
+		{Source: logging.AuditSourceOVN, Tags: source.OVNAuditTags},
+	},
+}
+
+// inputSources returns the sources selected by a user defined input of the source type, or false
+// if the input does not select logs of the source type. An empty list selects all sources.
+func inputSources(sourceType string, input *logging.InputSpec) ([]string, bool) {
+	switch {
+	case sourceType == logging.InputNameInfrastructure && input.Infrastructure != nil:
+		return input.Infrastructure.Sources, true
+	case sourceType == logging.InputNameAudit && input.Audit != nil:
+		return input.Audit.Sources, true
+	}
+	return nil, false
+}
+
 func SourceTypeToPipeline(sourceType string, spec *logging.ClusterLogForwarderSpec, op Options) Element {
+	allSources := sets.NewString()
+	for _, st := range InputSourceTags[sourceType] {
+		allSources.Insert(st.Source)
+	}
 	srcTypePipeline := []string{}
+	pipelineSources := map[string]sets.String{}
+	addPipeline := func(name string, sources []string) {
+		if _, ok := pipelineSources[name]; !ok {
+			srcTypePipeline = append(srcTypePipeline, name)
+			pipelineSources[name] = sets.NewString()
+		}
+		if len(sources) == 0 {
+			sources = allSources.List()
+		}
+		pipelineSources[name].Insert(sources...)
+	}
+	userDefined := spec.InputMap()
 	for _, pipeline := range spec.Pipelines {
 		for _, inRef := range pipeline.InputRefs {
 			if inRef == sourceType {
-				srcTypePipeline = append(srcTypePipeline, pipeline.Name)
+				addPipeline(pipeline.Name, nil)
+			} else if input, ok := userDefined[inRef]; ok {
+				if sources, ok := inputSources(sourceType, input); ok {
+					addPipeline(pipeline.Name, sources)
+				}
 			}
 		}
 	}
 	if IsIncludeLegacyForwardConfig(op) && sourceType != logging.InputNameAudit {
-		srcTypePipeline = append(srcTypePipeline, LegacySecureforward)
+		addPipeline(LegacySecureforward, nil)
 	}
 	if IsIncludeLegacySyslogConfig(op) {
-		srcTypePipeline = append(srcTypePipeline, LegacySyslog)
+		addPipeline(LegacySyslog, nil)
 	}
-	switch len(srcTypePipeline) {
-	case 0:
+	if len(srcTypePipeline) == 0 {
 		return Nil
-	case 1:
-		return FromLabel{
-			Desc:    fmt.Sprintf("Sending %s source type to pipeline", sourceType),
-			InLabel: helpers.SourceTypeLabelName(sourceType),
-			SubElements: []Element{
-				AddLogSourceType(sourceType),
-				Match{
-					MatchTags: "**",
-					MatchElement: Relabel{
-						OutLabel: helpers.LabelName(srcTypePipeline[0]),
-					},
-				},
-			},
+	}
+	selectsAll := true
+	for _, sources := range pipelineSources {
+		selectsAll = selectsAll && sources.Equal(allSources)
+	}
+	if !selectsAll {
+		return SourcesToPipelines(sourceType, srcTypePipeline, pipelineSources)
+	}
+	desc := fmt.Sprintf("Copying %s source type to pipeline", sourceType)
+	if len(srcTypePipeline) == 1 {
+		desc = fmt.Sprintf("Sending %s source type to pipeline", sourceType)
+	}
+	return FromLabel{
+		Desc:    desc,
+		InLabel: helpers.SourceTypeLabelName(sourceType),
+		SubElements: []Element{
+			AddLogSourceType(sourceType),
+			MatchToPipelines("**", srcTypePipeline),
+		},
+	}
+}
+
+// SourcesToPipelines routes the logs of each source of the source type to the pipelines selecting the source
+func SourcesToPipelines(sourceType string, pipelines []string, pipelineSources map[string]sets.String) Element {
+	el := []Element{
+		AddLogSourceType(sourceType),
+	}
+	for _, st := range InputSourceTags[sourceType] {
+		selecting := []string{}
+		for _, p := range pipelines {
+			if pipelineSources[p].Has(st.Source) {
+				selecting = append(selecting, p)
+			}
 		}
-	default:
-		return FromLabel{
-			Desc:    fmt.Sprintf("Copying %s source type to pipeline", sourceType),
-			InLabel: helpers.SourceTypeLabelName(sourceType),
-			SubElements: []Element{
-				AddLogSourceType(sourceType),
-				Match{
-					MatchTags: "**",
-					MatchElement: Copy{
-						Stores: CopyToLabels(helpers.LabelNames(srcTypePipeline)),
-					},
-				},
+		if len(selecting) == 0 {
+			el = append(el, ConfLiteral{
+				Desc:         fmt.Sprintf("Discard %s logs not selected by any pipeline", st.Source),
+				Pattern:      st.Tags,
+				TemplateName: "discardMatched",
+				TemplateStr:  DiscardMatched,
+			})
+			continue
+		}
+		match := MatchToPipelines(st.Tags, selecting)
+		match.Desc = fmt.Sprintf("Sending %s logs to pipelines", st.Source)
+		el = append(el, match)
+	}
+	return FromLabel{
+		Desc:        fmt.Sprintf("Routing %s source type to pipelines by source", sourceType),
+		InLabel:     helpers.SourceTypeLabelName(sourceType),
+		SubElements: el,
+	}
+}
+
+// MatchToPipelines sends the logs matching the tags to the pipelines
+func MatchToPipelines(tags string, pipelines []string) Match {
+	if len(pipelines) == 1 {
+		return Match{
+			MatchTags: tags,
+			MatchElement: Relabel{
+				OutLabel: helpers.LabelName(pipelines[0]),
 			},
 		}
 	}
+	return Match{
+		MatchTags: tags,
+		MatchElement: Copy{
+			Stores: CopyToLabels(helpers.LabelNames(pipelines)),
+		},
+	}
 }
 
 func InputsToPipeline(spec *logging.ClusterLogForwarderSpec, op Options) []Element {
@@ -115,6 +210,7 @@ func AppToPipeline1(spec *logging.ClusterLogForwarderSpec, op Options) []Element
 	userDefined := spec.InputMap()
 	// routed by namespace, or labels
 	routes := []Element{}
+	namespaceFilters := []Element{}
 	unRoutedPipelines := []string{}
 	for _, pipeline := range spec.Pipelines {
 		for _, inRef := range pipeline.InputRefs {
@@ -122,19 +218,17 @@ func AppToPipeline1(spec *logging.ClusterLogForwarderSpec, op Options) []Element
 				// user defined input
 				if input.Application != nil {
 					app := input.Application
-					if len(app.Namespaces) != 0 || (app.Selector != nil && len(app.Selector.MatchLabels) != 0) {
-						rd := RouteData{}
-						if len(app.Namespaces) != 0 {
-							rd.Namespaces = KV("namespaces", strings.Join(app.Namespaces, ", "))
-						}
-						if app.Selector != nil && len(app.Selector.MatchLabels) != 0 {
-							rd.Labels = KV("labels", strings.Join(helpers.LabelsKV(app.Selector), ", "))
-						}
+					if hasNamespacePatterns(app) || app.RateLimit != nil {
+						// label_router only matches namespace names, filter namespaces by tag and limit the rate
+						// of the input before the pipeline
+						filterLabel := helpers.SourceTypeLabelName(fmt.Sprintf("%s_%s", input.Name, pipeline.Name))
+						routes = append(routes, Route{
+							RoutePipeline: ApplicationRoutePipeline(filterLabel, app),
+						})
+						namespaceFilters = append(namespaceFilters, NamespaceFilter(filterLabel, pipeline.Name, input))
+					} else if len(app.Namespaces) != 0 || len(app.ExcludeNamespaces) != 0 || hasSelector(app) {
 						routes = append(routes, Route{
-							RoutePipeline: RoutePipeline{
-								Pipeline:  helpers.LabelName(pipeline.Name),
-								RouteData: rd,
-							},
+							RoutePipeline: ApplicationRoutePipeline(helpers.LabelName(pipeline.Name), app),
 						})
 					} else {
 						unRoutedPipelines = append(unRoutedPipelines, pipeline.Name)
@@ -159,7 +253,7 @@ func AppToPipeline1(spec *logging.ClusterLogForwarderSpec, op Options) []Element
 	}
 	switch len(unRoutedPipelines) {
 	case 0:
-		return []Element{
+		return append([]Element{
 			FromLabel{
 				Desc:    "Routing Application to pipelines",
 				InLabel: helpers.SourceTypeLabelName(logging.InputNameApplication),
@@ -173,14 +267,14 @@ func AppToPipeline1(spec *logging.ClusterLogForwarderSpec, op Options) []Element
 					},
 				},
 			},
-		}
+		}, namespaceFilters...)
 	case 1:
 		routes = append(routes, Route{
 			RoutePipeline: RoutePipeline{
 				Pipeline: helpers.SourceTypeLabelName("APPLICATION_ALL"),
 			},
 		})
-		return []Element{
+		return append([]Element{
 			FromLabel{
 				Desc:    "Routing Application to pipelines",
 				InLabel: helpers.SourceTypeLabelName(logging.InputNameApplication),
@@ -206,14 +300,14 @@ func AppToPipeline1(spec *logging.ClusterLogForwarderSpec, op Options) []Element
 					},
 				},
 			},
-		}
+		}, namespaceFilters...)
 	default:
 		routes = append(routes, Route{
 			RoutePipeline: RoutePipeline{
 				Pipeline: helpers.SourceTypeLabelName("APPLICATION_ALL"),
 			},
 		})
-		return []Element{
+		return append([]Element{
 			FromLabel{
 				Desc:    "Routing Application to pipelines",
 				InLabel: helpers.SourceTypeLabelName(logging.InputNameApplication),
@@ -239,8 +333,204 @@ func AppToPipeline1(spec *logging.ClusterLogForwarderSpec, op Options) []Element
 					},
 				},
 			},
+		}, namespaceFilters...)
+	}
+}
+
+func hasSelector(app *logging.Application) bool {
+	return app.Selector != nil && (len(app.Selector.MatchLabels) != 0 || len(app.Selector.MatchExpressions) != 0)
+}
+
+// ApplicationRoutePipeline routes the logs from the namespaces and matching the label selector of an application
+// input to the pipeline. A match is generated for every combination of the values of `In` expressions, and a
+// negated match for every value of `NotIn` expressions. `Exists` and `DoesNotExist` are not supported by label_router.
+func ApplicationRoutePipeline(pipeline string, app *logging.Application) RoutePipeline {
+	rp := RoutePipeline{
+		Pipeline: pipeline,
+	}
+	labels := []map[string]string{{}}
+	if app.Selector != nil {
+		for k, v := range app.Selector.MatchLabels {
+			labels[0][k] = v
+		}
+		for _, req := range app.Selector.MatchExpressions {
+			switch req.Operator {
+			case metav1.LabelSelectorOpIn:
+				combined := []map[string]string{}
+				for _, l := range labels {
+					for _, v := range req.Values {
+						m := map[string]string{req.Key: v}
+						for k, v := range l {
+							m[k] = v
+						}
+						combined = append(combined, m)
+					}
+				}
+				labels = combined
+			case metav1.LabelSelectorOpNotIn:
+				for _, v := range req.Values {
+					rp.Excludes = append(rp.Excludes, RouteData{
+						Labels: KV("labels", fmt.Sprintf("%s:%s", req.Key, v)),
+						Negate: KV("negate", "true"),
+					})
+				}
+			}
+		}
+	}
+	// namespace patterns are matched by NamespaceFilter
+	namespaces := len(app.Namespaces) != 0 && !hasNamespacePatterns(app)
+	if len(app.ExcludeNamespaces) != 0 && !hasNamespacePatterns(app) {
+		rp.Excludes = append(rp.Excludes, RouteData{
+			Namespaces: KV("namespaces", strings.Join(app.ExcludeNamespaces, ", ")),
+			Negate:     KV("negate", "true"),
+		})
+	}
+	for i, l := range labels {
+		rd := RouteData{}
+		if namespaces {
+			rd.Namespaces = KV("namespaces", strings.Join(app.Namespaces, ", "))
+		}
+		if len(l) != 0 {
+			rd.Labels = KV("labels", strings.Join(helpers.MapKV(l), ", "))
+		}
+		if i == 0 {
+			rp.RouteData = rd
+		} else {
+			rp.Alternatives = append(rp.Alternatives, rd)
+		}
+	}
+	return rp
+}
+
+func hasNamespacePatterns(app *logging.Application) bool {
+	for _, ns := range append(app.Namespaces, app.ExcludeNamespaces...) {
+		if strings.Contains(ns, "*") {
+			return true
+		}
+	}
+	return false
+}
+
+// namespaceTags returns the tags of the container logs from the namespaces
+func namespaceTags(namespaces []string) string {
+	tags := make([]string, len(namespaces))
+	for i, ns := range namespaces {
+		tags[i] = fmt.Sprintf("**_%s_**", ns)
+	}
+	return strings.Join(tags, " ")
+}
+
+// NamespaceFilter sends the logs routed to the filter label to the pipeline if they are from the included
+// namespaces and not from the excluded namespaces of the application input, and within its rate limit
+func NamespaceFilter(filterLabel, pipeline string, input *logging.InputSpec) Element {
+	app := input.Application
+	if !hasNamespacePatterns(app) {
+		// namespaces are matched by label_router
+		return FromLabel{
+			Desc:        fmt.Sprintf("Rate limiting input %s for pipeline %s", input.Name, pipeline),
+			InLabel:     filterLabel,
+			SubElements: append(RateLimit("**", pipeline, input), MatchToPipelines("**", []string{pipeline})),
 		}
 	}
+	el := []Element{}
+	if len(app.ExcludeNamespaces) != 0 {
+		el = append(el, ConfLiteral{
+			Desc:         "Discard logs from excluded namespaces",
+			Pattern:      namespaceTags(app.ExcludeNamespaces),
+			TemplateName: "discardMatched",
+			TemplateStr:  DiscardMatched,
+		})
+	}
+	if len(app.Namespaces) == 0 {
+		el = append(el, RateLimit("**", pipeline, input)...)
+		return FromLabel{
+			Desc:        fmt.Sprintf("Filtering namespaces of input %s for pipeline %s", input.Name, pipeline),
+			InLabel:     filterLabel,
+			SubElements: append(el, MatchToPipelines("**", []string{pipeline})),
+		}
+	}
+	el = append(el, RateLimit(namespaceTags(app.Namespaces), pipeline, input)...)
+	el = append(el,
+		MatchToPipelines(namespaceTags(app.Namespaces), []string{pipeline}),
+		ConfLiteral{
+			Desc:         "Discard logs from other namespaces",
+			Pattern:      "**",
+			TemplateName: "discardMatched",
+			TemplateStr:  DiscardMatched,
+		},
+	)
+	return FromLabel{
+		Desc:        fmt.Sprintf("Filtering namespaces of input %s for pipeline %s", input.Name, pipeline),
+		InLabel:     filterLabel,
+		SubElements: el,
+	}
+}
+
+// RateLimit drops the logs matching the tags exceeding the rate limit of the application input, if any. The records
+// subject to the limit and accepted are counted per namespace or container if the policy counts dropped records
+func RateLimit(tags, pipeline string, input *logging.InputSpec) []Element {
+	rl := input.Application.RateLimit
+	if rl == nil {
+		return []Element{}
+	}
+	burst := rl.Burst
+	if burst < rl.RecordsPerSecond {
+		burst = rl.RecordsPerSecond
+	}
+	// the bucket of the throttle plugin holds the records of a period, refilled at the end of the period
+	period := (burst + rl.RecordsPerSecond - 1) / rl.RecordsPerSecond
+	groupKey := "kubernetes.namespace_name"
+	labels := []Record{
+		{Key: "input", Expression: input.Name},
+		{Key: "pipeline", Expression: pipeline},
+		{Key: "namespace", Expression: "$.kubernetes.namespace_name"},
+	}
+	if rl.Per == logging.RateLimitPerContainer {
+		groupKey = "kubernetes.namespace_name,kubernetes.pod_name,kubernetes.container_name"
+		labels = append(labels,
+			Record{Key: "pod", Expression: "$.kubernetes.pod_name"},
+			Record{Key: "container", Expression: "$.kubernetes.container_name"},
+		)
+	}
+	labels = append(labels, Record{Key: "hostname", Expression: "${hostname}"})
+	throttle := Filter{
+		Desc:      fmt.Sprintf("Limit the rate of input %s per %s", input.Name, rateLimitScope(rl)),
+		MatchTags: tags,
+		Element: Throttle{
+			GroupKey:      groupKey,
+			PeriodSeconds: period,
+			Limit:         rl.RecordsPerSecond * period,
+		},
+	}
+	if rl.Policy != logging.RateLimitPolicyDropAndCount {
+		return []Element{throttle}
+	}
+	return []Element{
+		Filter{
+			MatchTags: tags,
+			Element: PrometheusCounter{
+				MetricName: RateLimitInputRecordTotal,
+				Help:       "The total number of records subject to a rate limit",
+				Labels:     labels,
+			},
+		},
+		throttle,
+		Filter{
+			MatchTags: tags,
+			Element: PrometheusCounter{
+				MetricName: RateLimitOutputRecordTotal,
+				Help:       "The total number of records within a rate limit",
+				Labels:     labels,
+			},
+		},
+	}
+}
+
+func rateLimitScope(rl *logging.RateLimit) logging.RateLimitScope {
+	if rl.Per == "" {
+		return logging.RateLimitPerNamespace
+	}
+	return rl.Per
 }
 
 func AppToPipeline(spec *logging.ClusterLogForwarderSpec, op Options) []Element {
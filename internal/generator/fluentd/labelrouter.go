@@ -36,8 +36,12 @@ func (r Route) Template() string {
 }
 
 type RoutePipeline struct {
-	Pipeline  string
+	Pipeline string
+	// Excludes are negated matches, a record matching any of them is not sent to the pipeline
+	Excludes  []generator.Element
 	RouteData generator.Element
+	// Alternatives are matches sending a record to the pipeline if it does not match RouteData
+	Alternatives []generator.Element
 }
 
 func (p RoutePipeline) Name() string {
@@ -47,9 +51,19 @@ func (p RoutePipeline) Name() string {
 func (p RoutePipeline) Template() string {
 	return `{{define "` + p.Name() + `" -}}
 @label {{.Pipeline}}
+{{range .Excludes -}}
+<match>
+{{compose_one . | indent 2}}
+</match>
+{{end -}}
 <match>
 {{compose_one .RouteData | indent 2}}
 </match>
+{{range .Alternatives -}}
+<match>
+{{compose_one . | indent 2}}
+</match>
+{{end -}}
 {{end}}`
 }
 
@@ -57,6 +71,7 @@ type RouteData struct {
 	// Labels is an array of "<key>:<value>" strings
 	Labels     generator.Element
 	Namespaces generator.Element
+	Negate     generator.Element
 }
 
 func (rd RouteData) Name() string {
@@ -67,5 +82,6 @@ func (rd RouteData) Template() string {
 	return `{{define "` + rd.Name() + `" -}}
 {{kv .Namespaces -}}
 {{kv .Labels -}}
+{{kv .Negate -}}
 {{end}}`
 }
@@ -43,6 +43,7 @@ func Buffer(bufkeys []string, bufspec *logging.FluentdBufferSpec, bufpath string
 }
 
 func MakeBuffer(bufkeys []string, bufspec *logging.FluentdBufferSpec, bufpath string, os *logging.OutputSpec) BufferConfData {
+	bufspec = MergeBufferSpec(bufspec, os)
 	return BufferConfData{
 		BufferPath:       BufferPath(bufpath),
 		FlushMode:        Optional("flush_mode", FlushMode(bufspec)),
@@ -64,6 +65,50 @@ func MakeBuffer(bufkeys []string, bufspec *logging.FluentdBufferSpec, bufpath st
 	}
 }
 
+// MergeBufferSpec returns the buffer tuning of an output merged over the global buffer spec.
+// Parameters not set for the output are taken from the global spec.
+func MergeBufferSpec(bufspec *logging.FluentdBufferSpec, os *logging.OutputSpec) *logging.FluentdBufferSpec {
+	if os == nil || os.Buffer == nil {
+		return bufspec
+	}
+	merged := logging.FluentdBufferSpec{}
+	if bufspec != nil {
+		merged = *bufspec
+	}
+	ob := os.Buffer
+	if ob.ChunkLimitSize != "" {
+		merged.ChunkLimitSize = ob.ChunkLimitSize
+	}
+	if ob.TotalLimitSize != "" {
+		merged.TotalLimitSize = ob.TotalLimitSize
+	}
+	if ob.OverflowAction != "" {
+		merged.OverflowAction = ob.OverflowAction
+	}
+	if ob.FlushThreadCount > 0 {
+		merged.FlushThreadCount = ob.FlushThreadCount
+	}
+	if ob.FlushMode != "" {
+		merged.FlushMode = ob.FlushMode
+	}
+	if ob.FlushInterval != "" {
+		merged.FlushInterval = ob.FlushInterval
+	}
+	if ob.RetryWait != "" {
+		merged.RetryWait = ob.RetryWait
+	}
+	if ob.RetryType != "" {
+		merged.RetryType = ob.RetryType
+	}
+	if ob.RetryMaxInterval != "" {
+		merged.RetryMaxInterval = ob.RetryMaxInterval
+	}
+	if ob.RetryTimeout != "" {
+		merged.RetryTimeout = ob.RetryTimeout
+	}
+	return &merged
+}
+
 func BufferPath(bufpath string) string {
 	return fmt.Sprintf("/var/lib/fluentd/%s", bufpath)
 }
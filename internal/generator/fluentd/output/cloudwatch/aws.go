@@ -15,3 +15,23 @@ aws_key_id "#{open({{ .KeyIDPath }},'r') do |f|f.read.strip end}"
 aws_sec_key "#{open({{ .KeyPath }},'r') do |f|f.read.strip end}"
 {{end}}`
 }
+
+// AWSWebIdentity assumes a role using a web identity token (STS AssumeRoleWithWebIdentity)
+type AWSWebIdentity struct {
+	RoleARNPath string
+	TokenPath   string
+}
+
+func (a AWSWebIdentity) Name() string {
+	return "awsWebIdentityTemplate"
+}
+
+func (a AWSWebIdentity) Template() string {
+	return `{{define "` + a.Name() + `" -}}
+<web_identity_credentials>
+  role_arn "#{open({{ .RoleARNPath }},'r') do |f|f.read.strip end}"
+  web_identity_token_file {{ .TokenPath }}
+  role_session_name "#{ENV['NODE_NAME']}"
+</web_identity_credentials>
+{{end}}`
+}
@@ -2,12 +2,15 @@ package cloudwatch
 
 import (
 	"fmt"
+	"path"
 	"strings"
 
 	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
 	. "github.com/openshift/cluster-logging-operator/internal/generator"
 	. "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/elements"
 	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output"
 	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/security"
 	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/source"
 	genhelper "github.com/openshift/cluster-logging-operator/internal/generator/helpers"
@@ -15,8 +18,11 @@ import (
 )
 
 type CloudWatch struct {
-	Region         string
-	SecurityConfig Element
+	Region          string
+	Endpoint        Element
+	RetentionInDays Element
+	SecurityConfig  Element
+	BufferConfig    []Element
 }
 
 func (cw CloudWatch) Name() string {
@@ -28,15 +34,17 @@ func (cw CloudWatch) Template() string {
 @type cloudwatch_logs
 auto_create_stream true
 region {{.Region }}
+{{kv .Endpoint -}}
 log_group_name_key cw_group_name
 log_stream_name_key cw_stream_name
 remove_log_stream_name_key true
 remove_log_group_name_key true
-auto_create_stream true
 concurrency 2
+{{kv .RetentionInDays -}}
 {{compose_one .SecurityConfig}}
 include_time_key true
 log_rejected_request true
+{{compose .BufferConfig}}
 {{end}}`
 }
 
@@ -48,7 +56,7 @@ func Conf(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o logging.O
 			InLabel: helpers.LabelName(o.Name),
 			SubElements: []Element{
 				GroupNameStreamName(fmt.Sprintf("%s%s", logGroupPrefix, logGroupName),
-					"${tag}",
+					LogStreamName(o),
 					source.ApplicationTags),
 				GroupNameStreamName(fmt.Sprintf("%sinfrastructure", logGroupPrefix),
 					"${record['hostname']}.${tag}",
@@ -66,16 +74,40 @@ func OutputConf(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o log
 	if genhelper.IsDebugOutput(op) {
 		return genhelper.DebugOutput
 	}
+	// the output plugin creates missing log groups along with missing log streams
+	cw := CloudWatch{
+		Region:          o.Cloudwatch.Region,
+		Endpoint:        Nil,
+		RetentionInDays: Nil,
+		SecurityConfig:  SecurityConfig(o, secret),
+		BufferConfig:    output.Buffer(output.NOKEYS, bufspec, helpers.StoreID("", o.Name, ""), &o),
+	}
+	if o.URL != "" {
+		cw.Endpoint = KV("endpoint", o.URL)
+	}
+	if o.Cloudwatch.RetentionInDays != nil {
+		cw.RetentionInDays = KV("retention_in_days", fmt.Sprintf("%d", *o.Cloudwatch.RetentionInDays))
+	}
 	return Match{
-		MatchTags: "**",
-		MatchElement: CloudWatch{
-			Region:         o.Cloudwatch.Region,
-			SecurityConfig: SecurityConfig(o, secret),
-		},
+		MatchTags:    "**",
+		MatchElement: cw,
 	}
 }
 
+// SecurityConfig returns the AWS credentials of the output, a role assumed with a web identity token
+// when the secret has a role ARN, static access keys otherwise
 func SecurityConfig(o logging.OutputSpec, secret *corev1.Secret) Element {
+	if security.HasAWSWebIdentityRole(secret) {
+		// the token of the output secret takes precedence over the projected service account token
+		tokenPath := fmt.Sprintf("'%s'", path.Join(constants.CollectorServiceAccountTokenDir, constants.BearerTokenFileKey))
+		if security.HasBearerToken(secret) {
+			tokenPath = security.SecretPath(o.Secret.Name, constants.BearerTokenFileKey)
+		}
+		return AWSWebIdentity{
+			RoleARNPath: security.SecretPath(o.Secret.Name, constants.AWSWebIdentityRoleKey),
+			TokenPath:   tokenPath,
+		}
+	}
 	return AWSKey{
 		KeyIDPath: security.SecretPath(o.Secret.Name, "aws_access_key_id"),
 		KeyPath:   security.SecretPath(o.Secret.Name, "aws_secret_access_key"),
@@ -117,9 +149,34 @@ func LogGroupName(o logging.OutputSpec) string {
 			return "${record['kubernetes']['namespace_name']}"
 		case logging.LogGroupByNamespaceUUID:
 			return "${record['kubernetes']['namespace_id']}"
+		case logging.LogGroupByKey:
+			keys := []string{}
+			for _, k := range GroupByKeyPath(o.Cloudwatch.GroupByKey) {
+				keys = append(keys, fmt.Sprintf("%q", k))
+			}
+			return fmt.Sprintf("${record.dig(%s) || %q}", strings.Join(keys, ","), logging.InputNameApplication)
 		default:
 			return logging.InputNameApplication
 		}
 	}
 	return ""
 }
+
+// GroupByKeyPath splits the group by key into the keys of the log record field. The key of a label
+// is kept whole, e.g. kubernetes.labels.app.kubernetes.io/name is ["kubernetes", "labels", "app.kubernetes.io/name"]
+func GroupByKeyPath(key string) []string {
+	return genhelper.FieldPath(key)
+}
+
+// LogStreamName returns the stream name of application logs
+func LogStreamName(o logging.OutputSpec) string {
+	if o.Cloudwatch != nil {
+		switch o.Cloudwatch.StreamName {
+		case logging.LogStreamNameByPodName:
+			return "${record['kubernetes']['namespace_name']}_${record['kubernetes']['pod_name']}"
+		case logging.LogStreamNameByContainerName:
+			return "${record['kubernetes']['namespace_name']}_${record['kubernetes']['pod_name']}_${record['kubernetes']['container_name']}"
+		}
+	}
+	return "${tag}"
+}
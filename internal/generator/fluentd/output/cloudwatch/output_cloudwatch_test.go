@@ -9,6 +9,7 @@ import (
 
 	loggingv1 "github.com/openshift/cluster-logging-operator/apis/logging/v1"
 	"github.com/openshift/cluster-logging-operator/internal/generator"
+	. "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/elements"
 
 	. "github.com/openshift/cluster-logging-operator/test/matchers"
 )
@@ -21,8 +22,8 @@ var _ = Describe("Generating fluentd config", func() {
 			Name: "my-cloudwatch",
 			OutputTypeSpec: loggingv1.OutputTypeSpec{
 				Cloudwatch: &loggingv1.Cloudwatch{
-					Region:  "anumber1",
-					GroupBy: loggingv1.LogGroupByNamespaceName,
+					Region:         "anumber1",
+					GroupBy:        loggingv1.LogGroupByNamespaceName,
 				},
 			},
 			Secret: &loggingv1.OutputSecretSpec{
@@ -83,12 +84,26 @@ var _ = Describe("Generating fluentd config", func() {
     log_stream_name_key cw_stream_name
     remove_log_stream_name_key true
     remove_log_group_name_key true
-    auto_create_stream true
     concurrency 2
     aws_key_id "#{open('/var/run/ocp-collector/secrets/my-secret/aws_access_key_id','r') do |f|f.read.strip end}"
     aws_sec_key "#{open('/var/run/ocp-collector/secrets/my-secret/aws_secret_access_key','r') do |f|f.read.strip end}"
     include_time_key true
     log_rejected_request true
+    <buffer>
+      @type file
+      path '/var/lib/fluentd/my_cloudwatch'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
   </match>
 </label>
 `
@@ -137,12 +152,26 @@ var _ = Describe("Generating fluentd config", func() {
     log_stream_name_key cw_stream_name
     remove_log_stream_name_key true
     remove_log_group_name_key true
-    auto_create_stream true
     concurrency 2
     aws_key_id "#{open('/var/run/ocp-collector/secrets/my-secret/aws_access_key_id','r') do |f|f.read.strip end}"
     aws_sec_key "#{open('/var/run/ocp-collector/secrets/my-secret/aws_secret_access_key','r') do |f|f.read.strip end}"
     include_time_key true
     log_rejected_request true
+    <buffer>
+      @type file
+      path '/var/lib/fluentd/my_cloudwatch'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
   </match>
 </label>
 `
@@ -194,12 +223,26 @@ var _ = Describe("Generating fluentd config", func() {
     log_stream_name_key cw_stream_name
     remove_log_stream_name_key true
     remove_log_group_name_key true
-    auto_create_stream true
     concurrency 2
     aws_key_id "#{open('/var/run/ocp-collector/secrets/my-secret/aws_access_key_id','r') do |f|f.read.strip end}"
     aws_sec_key "#{open('/var/run/ocp-collector/secrets/my-secret/aws_secret_access_key','r') do |f|f.read.strip end}"
     include_time_key true
     log_rejected_request true
+    <buffer>
+      @type file
+      path '/var/lib/fluentd/my_cloudwatch'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
   </match>
 </label>
 `
@@ -210,6 +253,202 @@ var _ = Describe("Generating fluentd config", func() {
 				Expect(results).To(EqualTrimLines(expConf))
 			})
 		})
+		Context("grouped by key and streamed by container name", func() {
+			BeforeEach(func() {
+				output.Cloudwatch.GroupBy = loggingv1.LogGroupByKey
+				output.Cloudwatch.GroupByKey = "kubernetes.labels.app.kubernetes.io/name"
+				output.Cloudwatch.StreamName = loggingv1.LogStreamNameByContainerName
+				output.Cloudwatch.GroupPrefix = nil
+			})
+			AfterEach(func() {
+				output.Cloudwatch.GroupByKey = ""
+				output.Cloudwatch.StreamName = ""
+			})
+			It("should provide a valid configuration", func() {
+				expConf := `
+<filter kubernetes.**>
+  @type record_modifier
+  <record>
+    cw_group_name ${record.dig("kubernetes","labels","app.kubernetes.io/name") || "application"}
+    cw_stream_name ${record['kubernetes']['namespace_name']}_${record['kubernetes']['pod_name']}_${record['kubernetes']['container_name']}
+  </record>
+</filter>
+`
+				es := Conf(nil, secrets[output.Secret.Name], output, nil)
+				results, err := g.GenerateConf(es[0].(FromLabel).SubElements[0])
+				Expect(err).To(BeNil())
+				Expect(results).To(EqualTrimLines(expConf))
+			})
+		})
+		Context("streamed by pod name", func() {
+			BeforeEach(func() {
+				output.Cloudwatch.GroupBy = loggingv1.LogGroupByLogType
+				output.Cloudwatch.StreamName = loggingv1.LogStreamNameByPodName
+				output.Cloudwatch.GroupPrefix = nil
+			})
+			AfterEach(func() {
+				output.Cloudwatch.StreamName = ""
+			})
+			It("should only name the streams of application logs by pod", func() {
+				expConf := `
+<filter kubernetes.**>
+  @type record_modifier
+  <record>
+    cw_group_name application
+    cw_stream_name ${record['kubernetes']['namespace_name']}_${record['kubernetes']['pod_name']}
+  </record>
+</filter>
+
+<filter **_default_** **_kube-*_** **_openshift-*_** **_openshift_** journal.** system.var.log**>
+  @type record_modifier
+  <record>
+    cw_group_name infrastructure
+    cw_stream_name ${record['hostname']}.${tag}
+  </record>
+</filter>
+`
+				es := Conf(nil, secrets[output.Secret.Name], output, nil)
+				results, err := g.GenerateConf(es[0].(FromLabel).SubElements[:2]...)
+				Expect(err).To(BeNil())
+				Expect(results).To(EqualTrimLines(expConf))
+			})
+		})
+		Context("with a custom endpoint and retention", func() {
+			BeforeEach(func() {
+				retention := int32(30)
+				output.URL = "https://logs.us-gov-east-1.amazonaws.com"
+				output.Cloudwatch.RetentionInDays = &retention
+			})
+			AfterEach(func() {
+				output.URL = ""
+				output.Cloudwatch.RetentionInDays = nil
+			})
+			It("should set the endpoint and the retention of created groups", func() {
+				expConf := `
+<match **>
+  @type cloudwatch_logs
+  auto_create_stream true
+  region anumber1
+  endpoint https://logs.us-gov-east-1.amazonaws.com
+  log_group_name_key cw_group_name
+  log_stream_name_key cw_stream_name
+  remove_log_stream_name_key true
+  remove_log_group_name_key true
+  concurrency 2
+  retention_in_days 30
+  aws_key_id "#{open('/var/run/ocp-collector/secrets/my-secret/aws_access_key_id','r') do |f|f.read.strip end}"
+  aws_sec_key "#{open('/var/run/ocp-collector/secrets/my-secret/aws_secret_access_key','r') do |f|f.read.strip end}"
+  include_time_key true
+  log_rejected_request true
+  <buffer>
+    @type file
+    path '/var/lib/fluentd/my_cloudwatch'
+    flush_mode interval
+    flush_interval 1s
+    flush_thread_count 2
+    retry_type exponential_backoff
+    retry_wait 1s
+    retry_max_interval 60s
+    retry_timeout 60m
+    queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+    total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+    chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+    overflow_action block
+  </buffer>
+</match>
+`
+				results, err := g.GenerateConf(OutputConf(nil, secrets[output.Secret.Name], output, nil))
+				Expect(err).To(BeNil())
+				Expect(results).To(EqualTrimLines(expConf))
+			})
+		})
+		Context("with a buffer tuning", func() {
+			BeforeEach(func() {
+				output.Buffer = &loggingv1.FluentdBufferSpec{
+					ChunkLimitSize: "2m",
+					FlushInterval:  "10s",
+					RetryTimeout:   "1h",
+				}
+			})
+			AfterEach(func() {
+				output.Buffer = nil
+			})
+			It("should merge the buffer tuning of the output over the global buffer spec", func() {
+				expConf := `
+<match **>
+  @type cloudwatch_logs
+  auto_create_stream true
+  region anumber1
+  log_group_name_key cw_group_name
+  log_stream_name_key cw_stream_name
+  remove_log_stream_name_key true
+  remove_log_group_name_key true
+  concurrency 2
+  aws_key_id "#{open('/var/run/ocp-collector/secrets/my-secret/aws_access_key_id','r') do |f|f.read.strip end}"
+  aws_sec_key "#{open('/var/run/ocp-collector/secrets/my-secret/aws_secret_access_key','r') do |f|f.read.strip end}"
+  include_time_key true
+  log_rejected_request true
+  <buffer>
+    @type file
+    path '/var/lib/fluentd/my_cloudwatch'
+    flush_mode interval
+    flush_interval 10s
+    flush_thread_count 4
+    retry_type exponential_backoff
+    retry_wait 1s
+    retry_max_interval 60s
+    retry_timeout 1h
+    queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+    total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+    chunk_limit_size 2m
+    overflow_action block
+  </buffer>
+</match>
+`
+				bufspec := &loggingv1.FluentdBufferSpec{
+					FlushThreadCount: 4,
+					FlushInterval:    "5s",
+				}
+				results, err := g.GenerateConf(OutputConf(bufspec, secrets[output.Secret.Name], output, nil))
+				Expect(err).To(BeNil())
+				Expect(results).To(EqualTrimLines(expConf))
+			})
+		})
+		Context("with a role ARN", func() {
+			var secret *corev1.Secret
+			BeforeEach(func() {
+				secret = &corev1.Secret{
+					Data: map[string][]byte{
+						"role_arn": []byte("arn:aws:iam::123456789012:role/my-role"),
+					},
+				}
+			})
+			It("should assume the role with the projected service account token", func() {
+				expConf := `
+<web_identity_credentials>
+  role_arn "#{open('/var/run/ocp-collector/secrets/my-secret/role_arn','r') do |f|f.read.strip end}"
+  web_identity_token_file '/var/run/ocp-collector/serviceaccount/token'
+  role_session_name "#{ENV['NODE_NAME']}"
+</web_identity_credentials>
+`
+				results, err := g.GenerateConf(SecurityConfig(output, secret))
+				Expect(err).To(BeNil())
+				Expect(results).To(EqualTrimLines(expConf))
+			})
+			It("should assume the role with the token of the secret", func() {
+				secret.Data["token"] = []byte("token")
+				expConf := `
+<web_identity_credentials>
+  role_arn "#{open('/var/run/ocp-collector/secrets/my-secret/role_arn','r') do |f|f.read.strip end}"
+  web_identity_token_file '/var/run/ocp-collector/secrets/my-secret/token'
+  role_session_name "#{ENV['NODE_NAME']}"
+</web_identity_credentials>
+`
+				results, err := g.GenerateConf(SecurityConfig(output, secret))
+				Expect(err).To(BeNil())
+				Expect(results).To(EqualTrimLines(expConf))
+			})
+		})
 	})
 })
 
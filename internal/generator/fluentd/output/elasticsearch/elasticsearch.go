@@ -2,6 +2,7 @@ package elasticsearch
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
@@ -28,6 +29,7 @@ type Elasticsearch struct {
 	Host           string
 	Port           string
 	RetryTag       Element
+	DataStream     bool
 	SecurityConfig []Element
 	BufferConfig   []Element
 }
@@ -50,7 +52,11 @@ verify_es_version_at_startup false
 target_index_key viaq_index_name
 id_key viaq_msg_id
 remove_keys viaq_index_name
+{{if .DataStream -}}
+suppress_type_name true
+{{else -}}
 type_name _doc
+{{end -}}
 {{ kv .RetryTag -}}
 http_backend typhoeus
 write_operation create
@@ -113,6 +119,7 @@ func Output(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o logging
 		StoreID:        storeID,
 		Host:           u.Hostname(),
 		Port:           port,
+		DataStream:     o.Elasticsearch != nil && o.Elasticsearch.DataStream,
 		SecurityConfig: SecurityConfig(o, secret),
 		BufferConfig:   output.Buffer(output.NOKEYS, bufspec, storeID, &o),
 	}
@@ -125,9 +132,27 @@ func RetryOutput(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o lo
 	return es
 }
 
+// ChangeESIndex writes logs to the index named by the index template of the output, and structured application
+// logs to an index named after their structured type. The structured field is removed when no structured
+// type is configured
 func ChangeESIndex(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o logging.OutputSpec, op Options) []Element {
+	index := []Element{}
+	if o.Elasticsearch != nil && o.Elasticsearch.Index != "" {
+		index = append(index, Filter{
+			Desc:      "set index from the index template",
+			MatchTags: "**",
+			Element: RecordModifier{
+				Records: []Record{
+					{
+						Key:        "viaq_index_name",
+						Expression: IndexTemplate(o.Elasticsearch.Index),
+					},
+				},
+			},
+		})
+	}
 	if o.Elasticsearch != nil && (o.Elasticsearch.StructuredTypeKey != "" || o.Elasticsearch.StructuredTypeName != "") {
-		return []Element{
+		return append(index,
 			Filter{
 				MatchTags: "**",
 				Element: RecordModifier{
@@ -148,10 +173,10 @@ func ChangeESIndex(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o
 					RemoveKeys: []string{"typeFromKey", "hasStructuredTypeName"},
 				},
 			},
-		}
+		)
 	}
 
-	return []Element{
+	return append(index,
 		Filter{
 			Desc:      "remove structured field if present",
 			MatchTags: "**",
@@ -159,7 +184,18 @@ func ChangeESIndex(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o
 				RemoveKeys: []string{KeyStructured},
 			},
 		},
-	}
+	)
+}
+
+// dateFormatRegex matches strftime conversion specifications and the separators between them, e.g. %Y.%m.%d
+var dateFormatRegex = regexp.MustCompile(`%[a-zA-Z]([-._/:]*%[a-zA-Z])*`)
+
+// IndexTemplate converts an index name template to a record_modifier expression. Log record fields are
+// referenced as {.field.path}, strftime conversion specifications are formatted from the record timestamp
+func IndexTemplate(template string) string {
+	return helpers.RecordTemplateWith(template, dateFormatRegex, func(format string) string {
+		return fmt.Sprintf("(Time.parse(record['@timestamp']) rescue Time.now).utc.strftime('%s')", format)
+	})
 }
 
 func FlattenLabels(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o logging.OutputSpec, op Options) []Element {
@@ -186,6 +222,9 @@ func SecurityConfig(o logging.OutputSpec, secret *corev1.Secret) []Element {
 	conf := []Element{}
 	if o.Secret != nil {
 		conf = append(conf, TLS(url.IsTLSScheme(u.Scheme)))
+		if security.GetFromSecret(secret, constants.ElasticsearchAPIKey) != "" {
+			conf = append(conf, KV("api_key", fmt.Sprintf(`"#{File.read(%s).strip rescue nil}"`, security.SecretPath(o.Secret.Name, constants.ElasticsearchAPIKey))))
+		}
 		if security.HasUsernamePassword(secret) {
 			up := UserNamePass{
 				UsernamePath: security.SecretPath(o.Secret.Name, constants.ClientUsername),
@@ -383,11 +383,157 @@ var _ = Describe("Generate fluentd config", func() {
     </buffer>
   </match>
 </label>
+`,
+		}),
+		Entry("with index template, data stream and api key", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeElasticsearch,
+						Name: "es-1",
+						URL:  "https://es.svc.infra.cluster:9999",
+						Secret: &logging.OutputSecretSpec{
+							Name: "es-1",
+						},
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Elasticsearch: &logging.Elasticsearch{
+								Index:      "logs-{.log_type}-{.kubernetes.namespace_name}-%Y.%m.%d",
+								DataStream: true,
+							},
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"es-1": {
+					Data: map[string][]byte{
+						"apiKey": []byte("VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="),
+					},
+				},
+			},
+			ExpectedConf: `
+<label @ES_1>
+  #set index from the index template
+  <filter **>
+    @type record_modifier
+    <record>
+      viaq_index_name logs-${record.dig("log_type")}-${record.dig("kubernetes","namespace_name")}-${(Time.parse(record['@timestamp']) rescue Time.now).utc.strftime('%Y.%m.%d')}
+    </record>
+  </filter>
+  
+  #remove structured field if present
+  <filter **>
+    @type record_modifier
+    remove_keys structured
+  </filter>
+  
+  #flatten labels to prevent field explosion in ES
+  <filter **>
+    @type record_transformer
+    enable_ruby true
+    <record>
+      kubernetes ${!record['kubernetes'].nil? ? record['kubernetes'].merge({"flat_labels": (record['kubernetes']['labels']||{}).map{|k,v| "#{k}=#{v}"}}) : {} }
+    </record>
+    remove_keys $.kubernetes.labels
+  </filter>
+  
+  <match retry_es_1>
+    @type elasticsearch
+    @id retry_es_1
+    host es.svc.infra.cluster
+    port 9999
+    verify_es_version_at_startup false
+    scheme https
+    ssl_version TLSv1_2
+    api_key "#{File.read('/var/run/ocp-collector/secrets/es-1/apiKey').strip rescue nil}"
+    target_index_key viaq_index_name
+    id_key viaq_msg_id
+    remove_keys viaq_index_name
+    suppress_type_name true
+    http_backend typhoeus
+    write_operation create
+    reload_connections 'true'
+    # https://github.com/uken/fluent-plugin-elasticsearch#reload-after
+    reload_after '200'
+    # https://github.com/uken/fluent-plugin-elasticsearch#sniffer-class-name
+    sniffer_class_name 'Fluent::Plugin::ElasticsearchSimpleSniffer'
+    reload_on_failure false
+    # 2 ^ 31
+    request_timeout 2147483648
+    <buffer>
+      @type file
+      path '/var/lib/fluentd/retry_es_1'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
+  </match>
+  
+  <match **>
+    @type elasticsearch
+    @id es_1
+    host es.svc.infra.cluster
+    port 9999
+    verify_es_version_at_startup false
+    scheme https
+    ssl_version TLSv1_2
+    api_key "#{File.read('/var/run/ocp-collector/secrets/es-1/apiKey').strip rescue nil}"
+    target_index_key viaq_index_name
+    id_key viaq_msg_id
+    remove_keys viaq_index_name
+    suppress_type_name true
+    retry_tag retry_es_1
+    http_backend typhoeus
+    write_operation create
+    reload_connections 'true'
+    # https://github.com/uken/fluent-plugin-elasticsearch#reload-after
+    reload_after '200'
+    # https://github.com/uken/fluent-plugin-elasticsearch#sniffer-class-name
+    sniffer_class_name 'Fluent::Plugin::ElasticsearchSimpleSniffer'
+    reload_on_failure false
+    # 2 ^ 31
+    request_timeout 2147483648
+    <buffer>
+      @type file
+      path '/var/lib/fluentd/es_1'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
+  </match>
+</label>
 `,
 		}),
 	)
 })
 
+var _ = Describe("IndexTemplate", func() {
+	It("should format the date and reference the log record fields", func() {
+		Expect(IndexTemplate("app-{.kubernetes.labels.app.kubernetes.io/name}-%Y.%m")).To(Equal(
+			`app-${record.dig("kubernetes","labels","app.kubernetes.io/name")}-${(Time.parse(record['@timestamp']) rescue Time.now).utc.strftime('%Y.%m')}`))
+	})
+	It("should not evaluate ruby in the literal text", func() {
+		Expect(IndexTemplate("app-#{`id`}-${File.read('/etc/shadow')}-%Y")).To(Equal(
+			`${"app-\x23\x7b\x60id\x60\x7d-\x24\x7bFile.read\x28\x27/etc/shadow\x27\x29\x7d-"}${(Time.parse(record['@timestamp']) rescue Time.now).utc.strftime('%Y')}`))
+	})
+})
+
 func TestFluendConfGenerator(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Fluend Conf Generation")
@@ -0,0 +1,123 @@
+package googlecloudlogging
+
+import (
+	"strings"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	. "github.com/openshift/cluster-logging-operator/internal/generator"
+	. "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/elements"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output"
+	genhelper "github.com/openshift/cluster-logging-operator/internal/generator/helpers"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// logIDKey is the record key holding the evaluated log ID, the record tag is rewritten to the log ID
+	// because the output plugin publishes the records of a tag to the log named by the tag
+	logIDKey = "_gcl_log_id"
+
+	// logIDLabelSuffix is the suffix of the label of the records with the tag rewritten to the log ID
+	logIDLabelSuffix = "_LOG_ID"
+)
+
+type GoogleCloudLogging struct {
+	StoreID       string
+	ProjectID     string
+	LoggingAPIURL string
+	BufferConfig  []Element
+}
+
+func (g GoogleCloudLogging) Name() string {
+	return "googleCloudLoggingTemplate"
+}
+
+func (g GoogleCloudLogging) Template() string {
+	return `{{define "` + g.Name() + `" -}}
+@type google_cloud
+@id {{.StoreID}}
+use_metadata_service false
+project_id {{.ProjectID}}
+{{- if .LoggingAPIURL}}
+logging_api_url {{.LoggingAPIURL}}
+{{- end}}
+{{compose .BufferConfig}}
+{{end}}`
+}
+
+// RewriteLogID re-emits the records to the label of the output with the tag set to the log ID
+type RewriteLogID struct {
+	OutLabel string
+}
+
+func (r RewriteLogID) Name() string {
+	return "rewriteLogIDTemplate"
+}
+
+func (r RewriteLogID) Template() string {
+	return `{{define "` + r.Name() + `" -}}
+@type rewrite_tag_filter
+@label {{.OutLabel}}
+<rule>
+  key ` + logIDKey + `
+  pattern /^(.+)$/
+  tag $1
+</rule>
+{{end}}`
+}
+
+// Conf generates the label evaluating the log ID and severity of the records, and the label of the output
+// publishing the records. Only projects can be targeted with the fluentd collector, checked at input
+// sanitization. The service account credentials are read by the output plugin from the file named by the
+// GOOGLE_APPLICATION_CREDENTIALS environment variable of the collector
+func Conf(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o logging.OutputSpec, op Options) []Element {
+	logIDLabel := helpers.LabelName(o.Name) + logIDLabelSuffix
+	return []Element{
+		FromLabel{
+			InLabel: helpers.LabelName(o.Name),
+			SubElements: []Element{
+				Filter{
+					MatchTags: "**",
+					Element: RecordModifier{
+						Records: []Record{
+							{Key: logIDKey, Expression: helpers.RecordTemplate(o.GoogleCloudLogging.LogID)},
+							{Key: "severity", Expression: `${record["level"]}`},
+						},
+					},
+				},
+				Match{
+					MatchTags:    "**",
+					MatchElement: RewriteLogID{OutLabel: logIDLabel},
+				},
+			},
+		},
+		FromLabel{
+			InLabel: logIDLabel,
+			SubElements: []Element{
+				Filter{
+					MatchTags: "**",
+					Element: RecordModifier{
+						RemoveKeys: []string{logIDKey},
+					},
+				},
+				Output(bufspec, secret, o, op),
+			},
+		},
+	}
+}
+
+func Output(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o logging.OutputSpec, op Options) Element {
+	if genhelper.IsDebugOutput(op) {
+		return genhelper.DebugOutput
+	}
+	storeID := helpers.StoreID("", o.Name, "")
+	return Match{
+		MatchTags: "**",
+		MatchElement: GoogleCloudLogging{
+			StoreID:       strings.ToLower(helpers.Replacer.Replace(o.Name)),
+			ProjectID:     o.GoogleCloudLogging.ProjectID,
+			LoggingAPIURL: o.URL,
+			BufferConfig:  output.Buffer([]string{"tag"}, bufspec, storeID, &o),
+		},
+	}
+}
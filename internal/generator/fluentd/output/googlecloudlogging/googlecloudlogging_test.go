@@ -0,0 +1,102 @@
+package googlecloudlogging
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("fluentd conf generation", func() {
+	var f = func(clspec logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+		var bufspec *logging.FluentdBufferSpec = nil
+		if clspec.Forwarder != nil &&
+			clspec.Forwarder.Fluentd != nil &&
+			clspec.Forwarder.Fluentd.Buffer != nil {
+			bufspec = clspec.Forwarder.Fluentd.Buffer
+		}
+		return Conf(bufspec, secrets[clfspec.Outputs[0].Name], clfspec.Outputs[0], op)
+	}
+	DescribeTable("for google cloud logging output", generator.TestGenerateConfWith(f),
+		Entry("with a templated log ID and an endpoint URL override", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeGoogleCloudLogging,
+						Name: "gcl-receiver",
+						URL:  "http://fake-gcl.example.com:8080",
+						Secret: &logging.OutputSecretSpec{
+							Name: "gcl-secret",
+						},
+						OutputTypeSpec: logging.OutputTypeSpec{
+							GoogleCloudLogging: &logging.GoogleCloudLogging{
+								ProjectID: "my-project",
+								LogID:     "app-{.kubernetes.namespace_name}",
+							},
+						},
+					},
+				},
+			},
+			ExpectedConf: `
+<label @GCL_RECEIVER>
+  <filter **>
+    @type record_modifier
+    <record>
+      _gcl_log_id app-${record.dig("kubernetes","namespace_name")}
+      severity ${record["level"]}
+    </record>
+  </filter>
+  
+  <match **>
+    @type rewrite_tag_filter
+    @label @GCL_RECEIVER_LOG_ID
+    <rule>
+      key _gcl_log_id
+      pattern /^(.+)$/
+      tag $1
+    </rule>
+  </match>
+</label>
+
+<label @GCL_RECEIVER_LOG_ID>
+  <filter **>
+    @type record_modifier
+    remove_keys _gcl_log_id
+  </filter>
+  
+  <match **>
+    @type google_cloud
+    @id gcl_receiver
+    use_metadata_service false
+    project_id my-project
+    logging_api_url http://fake-gcl.example.com:8080
+    <buffer tag>
+      @type file
+      path '/var/lib/fluentd/gcl_receiver'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
+  </match>
+</label>
+`,
+		}),
+	)
+})
+
+func TestFluendConfGenerator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fluend Conf Generation")
+}
@@ -0,0 +1,18 @@
+package http
+
+import (
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/security"
+)
+
+type TLSKeyCert security.TLSCertKey
+
+func (kc TLSKeyCert) Name() string {
+	return "httpCertKeyTemplate"
+}
+
+func (kc TLSKeyCert) Template() string {
+	return `{{define "` + kc.Name() + `" -}}
+tls_client_cert_path {{.CertPath}}
+tls_private_key_path {{.KeyPath}}
+{{- end}}`
+}
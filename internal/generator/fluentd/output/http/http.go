@@ -0,0 +1,159 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	. "github.com/openshift/cluster-logging-operator/internal/generator"
+	. "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/elements"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/security"
+	genhelper "github.com/openshift/cluster-logging-operator/internal/generator/helpers"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+
+	CompressionGzip = "gzip"
+
+	// bearerTokenPlaceholder is replaced by the embedded ruby reading the bearer token from the secret
+	bearerTokenPlaceholder = "__BEARER_TOKEN__"
+)
+
+type Http struct {
+	StoreID        string
+	Endpoint       string
+	Method         string
+	ContentType    string
+	JSONArray      bool
+	Headers        Element
+	Compress       Element
+	SecurityConfig []Element
+	BufferConfig   []Element
+}
+
+func (h Http) Name() string {
+	return "httpTemplate"
+}
+
+func (h Http) Template() string {
+	return `{{define "` + h.Name() + `" -}}
+@type http
+@id {{.StoreID}}
+endpoint {{.Endpoint}}
+http_method {{.Method}}
+content_type {{.ContentType}}
+{{if .JSONArray -}}
+json_array true
+{{end -}}
+{{kv .Headers -}}
+{{kv .Compress -}}
+{{with $security := compose .SecurityConfig -}}
+{{$security}}
+{{end -}}
+<format>
+  @type json
+</format>
+{{compose .BufferConfig}}
+{{end}}`
+}
+
+func Conf(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o logging.OutputSpec, op Options) []Element {
+	return []Element{
+		FromLabel{
+			InLabel: helpers.LabelName(o.Name),
+			SubElements: []Element{
+				Output(bufspec, secret, o, op),
+			},
+		},
+	}
+}
+
+func Output(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o logging.OutputSpec, op Options) Element {
+	if genhelper.IsDebugOutput(op) {
+		return genhelper.DebugOutput
+	}
+	storeID := helpers.StoreID("", o.Name, "")
+	h := Http{
+		StoreID:        strings.ToLower(helpers.Replacer.Replace(o.Name)),
+		Endpoint:       o.URL,
+		Method:         "post",
+		ContentType:    "application/x-ndjson",
+		Headers:        Headers(o, secret),
+		Compress:       Nil,
+		SecurityConfig: SecurityConfig(o, secret),
+		BufferConfig:   output.Buffer(output.NOKEYS, bufspec, storeID, &o),
+	}
+	if o.Http != nil {
+		if o.Http.Method != "" {
+			h.Method = strings.ToLower(o.Http.Method)
+		}
+		if o.Http.Format == FormatJSON {
+			h.ContentType = "application/json"
+			h.JSONArray = true
+		}
+		if o.Http.Compression == CompressionGzip {
+			h.Compress = KV("compress", CompressionGzip)
+		}
+	}
+	return Match{
+		MatchTags:    "**",
+		MatchElement: h,
+	}
+}
+
+// Headers generates the HTTP headers of the requests, including the bearer token from the secret
+func Headers(o logging.OutputSpec, secret *corev1.Secret) Element {
+	headers := map[string]string{}
+	if o.Http != nil {
+		for k, v := range o.Http.Headers {
+			headers[k] = v
+		}
+	}
+	hasToken := o.Secret != nil && security.HasBearerToken(secret)
+	if hasToken {
+		headers["Authorization"] = "Bearer " + bearerTokenPlaceholder
+	}
+	if len(headers) == 0 {
+		return Nil
+	}
+	// ignoring error, a map of strings can always be marshalled
+	b, _ := json.Marshal(headers)
+	if !hasToken {
+		return KV("headers", string(b))
+	}
+	// embedded ruby is only evaluated in double quoted strings
+	quoted := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(string(b))
+	token := fmt.Sprintf("#{File.read(%s).strip rescue nil}", security.SecretPath(o.Secret.Name, constants.BearerTokenFileKey))
+	return KV("headers", `"`+strings.Replace(quoted, bearerTokenPlaceholder, token, 1)+`"`)
+}
+
+func SecurityConfig(o logging.OutputSpec, secret *corev1.Secret) []Element {
+	conf := []Element{}
+	if o.Secret != nil {
+		if security.HasUsernamePassword(secret) {
+			conf = append(conf, UserNamePass{
+				UsernamePath: security.SecretPath(o.Secret.Name, constants.ClientUsername),
+				PasswordPath: security.SecretPath(o.Secret.Name, constants.ClientPassword),
+			})
+		}
+		if security.HasTLSCertAndKey(secret) {
+			conf = append(conf, TLSKeyCert{
+				CertPath: security.SecretPath(o.Secret.Name, constants.ClientCertKey),
+				KeyPath:  security.SecretPath(o.Secret.Name, constants.ClientPrivateKey),
+			})
+		}
+		if security.HasCABundle(secret) {
+			conf = append(conf, CAFile{
+				CAFilePath: security.SecretPath(o.Secret.Name, constants.TrustedCABundleKey),
+			})
+		}
+	}
+	return conf
+}
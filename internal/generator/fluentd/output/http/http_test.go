@@ -0,0 +1,204 @@
+package http
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("fluentd conf generation", func() {
+	var f = func(clspec logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+		var bufspec *logging.FluentdBufferSpec = nil
+		if clspec.Forwarder != nil &&
+			clspec.Forwarder.Fluentd != nil &&
+			clspec.Forwarder.Fluentd.Buffer != nil {
+			bufspec = clspec.Forwarder.Fluentd.Buffer
+		}
+		return Conf(bufspec, secrets[clfspec.Outputs[0].Name], clfspec.Outputs[0], op)
+	}
+	DescribeTable("for http output", generator.TestGenerateConfWith(f),
+		Entry("with defaults", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeHttp,
+						Name: "http-receiver",
+						URL:  "http://collector.example.com:8080/logs",
+					},
+				},
+			},
+			ExpectedConf: `
+<label @HTTP_RECEIVER>
+  <match **>
+    @type http
+    @id http_receiver
+    endpoint http://collector.example.com:8080/logs
+    http_method post
+    content_type application/x-ndjson
+    <format>
+      @type json
+    </format>
+    <buffer>
+      @type file
+      path '/var/lib/fluentd/http_receiver'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
+  </match>
+</label>
+`,
+		}),
+		Entry("with method, headers, json format, gzip and basic auth", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeHttp,
+						Name: "http-receiver",
+						URL:  "https://collector.example.com/logs",
+						Secret: &logging.OutputSecretSpec{
+							Name: "http-secret",
+						},
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Http: &logging.Http{
+								Method: "PUT",
+								Headers: map[string]string{
+									"X-Cluster": "prod",
+								},
+								Format:      FormatJSON,
+								Compression: CompressionGzip,
+							},
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"http-receiver": {
+					Data: map[string][]byte{
+						"username":      []byte("user"),
+						"password":      []byte("pass"),
+						"ca-bundle.crt": []byte("junk"),
+					},
+				},
+			},
+			ExpectedConf: `
+<label @HTTP_RECEIVER>
+  <match **>
+    @type http
+    @id http_receiver
+    endpoint https://collector.example.com/logs
+    http_method put
+    content_type application/json
+    json_array true
+    headers {"X-Cluster":"prod"}
+    compress gzip
+    <auth>
+      method basic
+      username "#{File.read('/var/run/ocp-collector/secrets/http-secret/username') rescue nil}"
+      password "#{File.read('/var/run/ocp-collector/secrets/http-secret/password') rescue nil}"
+    </auth>
+    tls_ca_cert_path '/var/run/ocp-collector/secrets/http-secret/ca-bundle.crt'
+    <format>
+      @type json
+    </format>
+    <buffer>
+      @type file
+      path '/var/lib/fluentd/http_receiver'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
+  </match>
+</label>
+`,
+		}),
+		Entry("with bearer token and client certificate", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeHttp,
+						Name: "http-receiver",
+						URL:  "https://collector.example.com/logs",
+						Secret: &logging.OutputSecretSpec{
+							Name: "http-secret",
+						},
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Http: &logging.Http{
+								Headers: map[string]string{
+									"X-Cluster": "prod",
+								},
+							},
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"http-receiver": {
+					Data: map[string][]byte{
+						"token":   []byte("junk"),
+						"tls.key": []byte("junk"),
+						"tls.crt": []byte("junk"),
+					},
+				},
+			},
+			ExpectedConf: `
+<label @HTTP_RECEIVER>
+  <match **>
+    @type http
+    @id http_receiver
+    endpoint https://collector.example.com/logs
+    http_method post
+    content_type application/x-ndjson
+    headers "{\"Authorization\":\"Bearer #{File.read('/var/run/ocp-collector/secrets/http-secret/token').strip rescue nil}\",\"X-Cluster\":\"prod\"}"
+    tls_client_cert_path '/var/run/ocp-collector/secrets/http-secret/tls.crt'
+    tls_private_key_path '/var/run/ocp-collector/secrets/http-secret/tls.key'
+    <format>
+      @type json
+    </format>
+    <buffer>
+      @type file
+      path '/var/lib/fluentd/http_receiver'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
+  </match>
+</label>
+`,
+		}),
+	)
+})
+
+func TestFluendConfGenerator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fluend Conf Generation")
+}
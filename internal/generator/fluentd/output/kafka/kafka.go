@@ -1,6 +1,7 @@
 package kafka
 
 import (
+	"encoding/json"
 	"net/url"
 	"strings"
 
@@ -19,6 +20,8 @@ import (
 
 const (
 	defaultKafkaTopic = "topic"
+	topicKey          = "_kafka_topic"
+	messageKeyKey     = "_kafka_message_key"
 )
 
 type Kafka struct {
@@ -26,6 +29,11 @@ type Kafka struct {
 	StoreID        string
 	Brokers        string
 	Topics         string
+	TopicKey       string
+	MessageKey     string
+	Headers        Element
+	RecordHeaders  Element
+	Compression    Element
 	SecurityConfig []Element
 	BufferConfig   []Element
 }
@@ -40,10 +48,21 @@ func (k Kafka) Template() string {
 @id {{.StoreID}}
 brokers {{.Brokers}}
 default_topic {{.Topics}}
+{{- if .TopicKey}}
+topic_key {{.TopicKey}}
+exclude_topic_key true
+{{- end}}
 use_event_time true
-{{- with $x := compose .SecurityConfig }}
-{{$x}}
+{{- if .MessageKey}}
+message_key_key {{.MessageKey}}
+exclude_message_key true
 {{- end}}
+{{kv .Headers -}}
+{{kv .RecordHeaders -}}
+{{kv .Compression -}}
+{{with $x := compose .SecurityConfig -}}
+{{$x}}
+{{end -}}
 <format>
   @type json
 </format>
@@ -57,6 +76,7 @@ func Conf(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o logging.O
 		FromLabel{
 			InLabel: helpers.LabelName(o.Name),
 			SubElements: []Element{
+				FieldsFilter(o),
 				Output(bufspec, secret, o, op),
 			},
 		},
@@ -68,19 +88,97 @@ func Output(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o logging
 		return genhelper.DebugOutput
 	}
 	topics := Topics(o)
+	topicKeyKey := ""
+	bufKeys := []string{topics}
+	if helpers.IsRecordTemplate(topics) {
+		// evaluated by FieldsFilter, the topic of a chunk is read from its chunk key
+		topicKeyKey = topicKey
+		bufKeys = []string{topicKey}
+		topics = defaultKafkaTopic
+	}
+	messageKey := ""
+	if o.Kafka != nil && o.Kafka.Key != "" {
+		// evaluated by MessageKeyFilter
+		messageKey = messageKeyKey
+	}
+	headers, recordHeaders := Headers(o.Kafka)
 	storeID := helpers.StoreID("", o.Name, "")
 	return Match{
 		MatchTags: "**",
 		MatchElement: Kafka{
 			StoreID:        strings.ToLower(helpers.Replacer.Replace(o.Name)),
 			Topics:         topics,
+			TopicKey:       topicKeyKey,
+			MessageKey:     messageKey,
+			Headers:        headers,
+			RecordHeaders:  recordHeaders,
+			Compression:    Compression(o.Kafka),
 			Brokers:        Brokers(o),
 			SecurityConfig: SecurityConfig(o, secret),
-			BufferConfig:   output.Buffer([]string{topics}, bufspec, storeID, &o),
+			BufferConfig:   output.Buffer(bufKeys, bufspec, storeID, &o),
+		},
+	}
+}
+
+// FieldsFilter generates a record_modifier filter evaluating the templated topic and the message key of the
+// record. The kafka output plugin removes these fields from the messages it sends.
+func FieldsFilter(o logging.OutputSpec) Element {
+	rs := []Record{}
+	if topics := Topics(o); helpers.IsRecordTemplate(topics) {
+		rs = append(rs, Record{
+			Key:        topicKey,
+			Expression: helpers.RecordTemplate(topics),
+		})
+	}
+	if o.Kafka != nil && o.Kafka.Key != "" {
+		rs = append(rs, Record{
+			Key:        messageKeyKey,
+			Expression: helpers.RecordTemplate(o.Kafka.Key),
+		})
+	}
+	if len(rs) == 0 {
+		return Nil
+	}
+	return Filter{
+		MatchTags: "**",
+		Element: RecordModifier{
+			Records: rs,
 		},
 	}
 }
 
+// Headers returns the static headers and the headers read from log record fields. Header values
+// referencing anything but a single field are checked at input sanitization
+func Headers(k *logging.Kafka) (Element, Element) {
+	if k == nil || len(k.Headers) == 0 {
+		return Nil, Nil
+	}
+	static := map[string]string{}
+	fromRecord := map[string]string{}
+	for name, value := range k.Headers {
+		if field, ok := helpers.RecordField(value); ok {
+			fromRecord[name] = helpers.RecordAccessor(field)
+		} else {
+			static[name] = value
+		}
+	}
+	hash := func(key string, m map[string]string) Element {
+		if len(m) == 0 {
+			return Nil
+		}
+		b, _ := json.Marshal(m)
+		return KV(key, string(b))
+	}
+	return hash("headers", static), hash("headers_from_record", fromRecord)
+}
+
+func Compression(k *logging.Kafka) Element {
+	if k == nil || k.Compression == "" {
+		return Nil
+	}
+	return KV("compression_codec", k.Compression)
+}
+
 //Brokers returns the list of broker endpoints of a kafka cluster.
 //The list represents only the initial set used by the collector's kafka client for the
 //first connention only. The collector's kafka client fetches constantly an updated list
@@ -122,6 +220,7 @@ func Brokers(o logging.OutputSpec) string {
 //Topic returns the name of an existing kafka topic.
 //The kafka topic is either extracted from the kafka OutputSpec `Topic` field in a multiple broker
 //setup or as a fallback from the OutputSpec URL if provided as a host path. Defaults to `topic`.
+//The topic may reference log record fields, e.g. `app-{.kubernetes.namespace_name}`.
 func Topics(o logging.OutputSpec) string {
 	if o.Kafka != nil && o.Kafka.Topic != "" {
 		return o.Kafka.Topic
@@ -145,7 +244,14 @@ func SecurityConfig(o logging.OutputSpec, secret *corev1.Secret) []Element {
 				UsernamePath: security.SecretPath(o.Secret.Name, constants.ClientUsername),
 				PasswordPath: security.SecretPath(o.Secret.Name, constants.ClientPassword),
 			}
-			conf = append(conf, up)
+			if mechanism := SASLMechanism(secret); mechanism != constants.SaslMechanismPlain {
+				conf = append(conf, Scram{
+					UserNamePass: security.UserNamePass(up),
+					Mechanism:    scramMechanisms[mechanism],
+				})
+			} else {
+				conf = append(conf, up)
+			}
 		}
 		if security.HasTLSCertAndKey(secret) {
 			kc := TLSKeyCert{
@@ -172,3 +278,11 @@ func SecurityConfig(o logging.OutputSpec, secret *corev1.Secret) []Element {
 	}
 	return conf
 }
+
+// SASLMechanism returns the SASL mechanism of the output secret, `PLAIN` if unspecified
+func SASLMechanism(secret *corev1.Secret) string {
+	if mechanism := security.GetFromSecret(secret, constants.SaslMechanism); mechanism != "" {
+		return mechanism
+	}
+	return constants.SaslMechanismPlain
+}
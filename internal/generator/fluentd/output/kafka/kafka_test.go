@@ -177,6 +177,70 @@ var _ = Describe("Generate fluentd config", func() {
     </buffer>
   </match>
 </label>
+`,
+		}),
+		Entry("with message key, headers and compression", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeKafka,
+						Name: "kafka-receiver",
+						URL:  "tls://broker1-kafka.svc.messaging.cluster.local:9092/topic",
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Kafka: &logging.Kafka{
+								Key: "{.kubernetes.pod_id}",
+								Headers: map[string]string{
+									"cluster":   "east",
+									"namespace": "{.kubernetes.namespace_name}",
+									"app":       "{.kubernetes.labels.app.kubernetes.io/name}",
+								},
+								Compression: "lz4",
+							},
+						},
+					},
+				},
+			},
+			Secrets: security.NoSecrets,
+			ExpectedConf: `
+<label @KAFKA_RECEIVER>
+  <filter **>
+    @type record_modifier
+    <record>
+      _kafka_message_key ${record.dig("kubernetes","pod_id")}
+    </record>
+  </filter>
+  
+  <match **>
+    @type kafka2
+    @id kafka_receiver
+    brokers broker1-kafka.svc.messaging.cluster.local:9092
+    default_topic topic
+    use_event_time true
+    message_key_key _kafka_message_key
+    exclude_message_key true
+    headers {"cluster":"east"}
+    headers_from_record {"app":"$['kubernetes']['labels']['app.kubernetes.io/name']","namespace":"$['kubernetes']['namespace_name']"}
+    compression_codec lz4
+    <format>
+      @type json
+    </format>
+    <buffer topic>
+      @type file
+      path '/var/lib/fluentd/kafka_receiver'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
+  </match>
+</label>
 `,
 		}),
 	)
@@ -135,6 +135,127 @@ var _ = Describe("Generating external kafka server output store config block", f
 		})
 	})
 
+	Context("for kafka output with SCRAM authentication", func() {
+		kafkaConf := `
+<label @KAFKA_RECEIVER>
+  <match **>
+    @type kafka2
+    @id kafka_receiver
+    brokers broker1-kafka.svc.messaging.cluster.local:9092
+    default_topic topic
+    use_event_time true
+    username "#{File.exists?('/var/run/ocp-collector/secrets/some-secret/username') ? open('/var/run/ocp-collector/secrets/some-secret/username','r') do |f|f.read end : ''}"
+    password "#{File.exists?('/var/run/ocp-collector/secrets/some-secret/password') ? open('/var/run/ocp-collector/secrets/some-secret/password','r') do |f|f.read end : ''}"
+    scram_mechanism sha512
+    sasl_over_ssl true
+    <format>
+      @type json
+    </format>
+    <buffer topic>
+      @type file
+      path '/var/lib/fluentd/kafka_receiver'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
+  </match>
+</label>
+`
+
+		It("should use the SASL mechanism of the secret", func() {
+			outputs = []v1.OutputSpec{
+				{
+					Type: v1.OutputTypeKafka,
+					Name: "kafka-receiver",
+					URL:  "tls://broker1-kafka.svc.messaging.cluster.local:9092/topic",
+					Secret: &v1.OutputSecretSpec{
+						Name: "some-secret",
+					},
+				},
+			}
+			secret := &corev1.Secret{
+				Data: map[string][]byte{
+					"username":       []byte("testuser"),
+					"password":       []byte("testpass"),
+					"sasl_mechanism": []byte("SCRAM-SHA-512"),
+					"sasl_over_ssl":  nil,
+				},
+			}
+
+			results, err := g.GenerateConf(kafka.Conf(nil, secret, outputs[0], nil)...)
+			Expect(err).To(BeNil())
+			Expect(results).To(EqualTrimLines(kafkaConf))
+		})
+	})
+
+	Context("for kafka output with a topic referencing record fields", func() {
+		kafkaConf := `
+<label @KAFKA_RECEIVER>
+  <filter **>
+    @type record_modifier
+    <record>
+      _kafka_topic ${record.dig("log_type")}-${record.dig("kubernetes","labels","app.kubernetes.io/name")}
+    </record>
+  </filter>
+
+  <match **>
+    @type kafka2
+    @id kafka_receiver
+    brokers broker1-kafka.svc.messaging.cluster.local:9092
+    default_topic topic
+    topic_key _kafka_topic
+    exclude_topic_key true
+    use_event_time true
+    <format>
+      @type json
+    </format>
+    <buffer _kafka_topic>
+      @type file
+      path '/var/lib/fluentd/kafka_receiver'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
+  </match>
+</label>
+`
+
+		It("should resolve the topic from the buffer chunk key", func() {
+			outputs = []v1.OutputSpec{
+				{
+					Type: v1.OutputTypeKafka,
+					Name: "kafka-receiver",
+					URL:  "tls://broker1-kafka.svc.messaging.cluster.local:9092",
+					OutputTypeSpec: v1.OutputTypeSpec{
+						Kafka: &v1.Kafka{
+							Topic: "{.log_type}-{.kubernetes.labels.app.kubernetes.io/name}",
+						},
+					},
+				},
+			}
+
+			results, err := g.GenerateConf(kafka.Conf(nil, nil, outputs[0], nil)...)
+			Expect(err).To(BeNil())
+			Expect(results).To(EqualTrimLines(kafkaConf))
+		})
+	})
+
 	Context("for kafka output with multiple brokers", func() {
 		kafkaConf := `
 <label @KAFKA_RECEIVER>
@@ -0,0 +1,29 @@
+package kafka
+
+import (
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/security"
+)
+
+var scramMechanisms = map[string]string{
+	constants.SaslMechanismScramSHA256: "sha256",
+	constants.SaslMechanismScramSHA512: "sha512",
+}
+
+type Scram struct {
+	security.UserNamePass
+	Mechanism string
+}
+
+func (s Scram) Name() string {
+	return "kafkaScramTemplate"
+}
+
+func (s Scram) Template() string {
+	return `{{define "` + s.Name() + `" -}}
+username "#{File.exists?({{.UsernamePath}}) ? open({{.UsernamePath}},'r') do |f|f.read end : ''}"
+password "#{File.exists?({{.PasswordPath}}) ? open({{.PasswordPath}},'r') do |f|f.read end : ''}"
+scram_mechanism {{.Mechanism}}
+{{- end}}
+`
+}
@@ -2,6 +2,7 @@ package loki
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/openshift/cluster-logging-operator/internal/constants"
@@ -22,6 +23,7 @@ import (
 const (
 	lokiLabelKubernetesHost = "kubernetes.host"
 	lokiLabelTag            = "tag"
+	lokiPushPath            = "/loki/api/v1/push"
 )
 
 var (
@@ -85,15 +87,12 @@ func Output(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o logging
 	if genhelper.IsDebugOutput(op) {
 		return genhelper.DebugOutput
 	}
-	// url is parasable, checked at input sanitization
-	u, _ := urlhelper.Parse(o.URL)
-	urlBase := fmt.Sprintf("%v://%v", u.Scheme, u.Host)
 	storeID := helpers.StoreID("", o.Name, "")
 	return Match{
 		MatchTags: "**",
 		MatchElement: Loki{
 			StoreID:        strings.ToLower(helpers.Replacer.Replace(o.Name)),
-			URLBase:        urlBase,
+			URLBase:        URLBase(o),
 			Tenant:         Tenant(o.Loki),
 			LokiLabel:      LokiLabel(o.Loki),
 			SecurityConfig: SecurityConfig(o, secret),
@@ -105,6 +104,9 @@ func Output(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o logging
 func SecurityConfig(o logging.OutputSpec, secret *corev1.Secret) []Element {
 	conf := []Element{}
 	if o.Secret != nil {
+		if security.HasBearerToken(secret) {
+			conf = append(conf, KV("bearer_token_file", security.SecretPath(o.Secret.Name, constants.BearerTokenFileKey)))
+		}
 		if security.HasUsernamePassword(secret) {
 			up := UserNamePass{
 				UsernamePath: security.SecretPath(o.Secret.Name, constants.ClientUsername),
@@ -141,32 +143,53 @@ func lokiLabelKeys(l *logging.Loki) []string {
 	return keys.List()
 }
 
-// LokiLabelFilter generates record_modifier filter lines to copy Loki label fields.
-// The Loki output plugin will remove these fields after creating Loki labels.
-func LokiLabelFilter(l *logging.Loki) Element {
-	rs := []Record{}
+// lokiLabels returns the Loki labels of the label keys followed by the labels built from templates, with
+// the record_modifier expression of their value. A label built from a template replaces the label of a
+// label key with the same name
+func lokiLabels(l *logging.Loki) []Record {
+	labels := []Record{}
+	index := map[string]int{}
+	add := func(name, expression string) {
+		if i, found := index[name]; found {
+			labels[i].Expression = expression
+			return
+		}
+		index[name] = len(labels)
+		labels = append(labels, Record{Key: name, Expression: expression})
+	}
 	for _, k := range lokiLabelKeys(l) {
-		tempName := strings.Replace(k, ".", "_", -1)
-		recordKeys := strings.Replace(k, ".", `","`, -1)
-		var r Record
+		name := strings.Replace(k, ".", "_", -1)
 		switch k {
 		case lokiLabelTag:
-			r = Record{
-				Key:        "_tag",
-				Expression: "${tag}",
-			}
+			add(name, "${tag}")
 		case lokiLabelKubernetesHost:
-			r = Record{
-				Key:        fmt.Sprintf("_%v", tempName),
-				Expression: "\"#{ENV['NODE_NAME']}\"",
-			}
+			add(name, "\"#{ENV['NODE_NAME']}\"")
 		default:
-			r = Record{
-				Key:        fmt.Sprintf("_%v", tempName),
-				Expression: fmt.Sprintf("${record.dig(\"%v\")}", recordKeys),
-			}
+			add(name, fmt.Sprintf("${%s}", helpers.RecordDig(k)))
+		}
+	}
+	if l != nil {
+		names := make([]string, 0, len(l.Labels))
+		for name := range l.Labels {
+			names = append(names, name)
 		}
-		rs = append(rs, r)
+		sort.Strings(names)
+		for _, name := range names {
+			add(name, helpers.RecordTemplate(l.Labels[name]))
+		}
+	}
+	return labels
+}
+
+// LokiLabelFilter generates record_modifier filter lines to copy Loki label fields.
+// The Loki output plugin will remove these fields after creating Loki labels.
+func LokiLabelFilter(l *logging.Loki) Element {
+	rs := []Record{}
+	for _, label := range lokiLabels(l) {
+		rs = append(rs, Record{
+			Key:        fmt.Sprintf("_%v", label.Key),
+			Expression: label.Expression,
+		})
 	}
 	if len(rs) == 0 {
 		return Nil
@@ -183,13 +206,40 @@ func LokiLabelFilter(l *logging.Loki) Element {
 // This consumes the fields generated by LokiLabelFilter.
 func LokiLabel(l *logging.Loki) []string {
 	labels := []string{}
-	for _, k := range lokiLabelKeys(l) {
-		tempName := strings.Replace(k, ".", "_", -1)
-		labels = append(labels, fmt.Sprintf("%v _%v", tempName, tempName))
+	for _, label := range lokiLabels(l) {
+		labels = append(labels, fmt.Sprintf("%v _%v", label.Key, label.Key))
 	}
 	return labels
 }
 
+// URLBase returns the URL the Loki push API path is appended to, keeping the path of the output URL
+// as prefix for Loki behind a reverse proxy. A push API path in the output URL is removed
+func URLBase(o logging.OutputSpec) string {
+	// url is parasable, checked at input sanitization
+	u, _ := urlhelper.Parse(o.URL)
+	path := strings.TrimSuffix(strings.TrimRight(u.Path, "/"), lokiPushPath)
+	return fmt.Sprintf("%v://%v%v", u.Scheme, u.Host, path)
+}
+
+// RecordFields returns the dot delimited paths of the log record fields of the Loki labels and tenant
+func RecordFields(l *logging.Loki) []string {
+	fields := []string{}
+	for _, k := range lokiLabelKeys(l) {
+		if k != lokiLabelTag && k != lokiLabelKubernetesHost {
+			fields = append(fields, k)
+		}
+	}
+	if l != nil {
+		for _, template := range l.Labels {
+			fields = append(fields, helpers.RecordFields(template)...)
+		}
+		if l.TenantKey != "" {
+			fields = append(fields, l.TenantKey)
+		}
+	}
+	return fields
+}
+
 // LokiTenantKeys returns the components of the loki tenant key.
 func LokiTenantKeys(l *logging.Loki) []string {
 	if l != nil && l.TenantKey != "" {
@@ -163,6 +163,118 @@ func TestLokiOutput(t *testing.T) {
 		require.Equal(t, test.TrimLines(config.String()), test.TrimLines(results), results)
 	})
 
+	testCase("label templates", func(t *testing.T) {
+		outputs := []v1.OutputSpec{{
+			Name: "loki-receiver",
+			Type: v1.OutputTypeLoki,
+			URL:  "https://logs-us-west1.grafana.net",
+			OutputTypeSpec: v1.OutputTypeSpec{Loki: &v1.Loki{
+				Labels: map[string]string{
+					"cluster":        "east",
+					"app":            "{.kubernetes.labels.app.kubernetes.io/name}",
+					"kubernetes_pod": "pod-{.kubernetes.pod_name}",
+					"log_type":       "{.log_type}-logs",
+				},
+			}},
+		}}
+		es := Conf(nil, secrets["loki-receiver"], outputs[0], generator.NoOptions)
+		results, err := g.GenerateConf(es...)
+		require.NoError(t, err)
+		config.content = `url https://logs-us-west1.grafana.net`
+		// NOTE: the log_type label of the default label keys is replaced by its template
+		config.filter = `
+      _kubernetes_container_name ${record.dig("kubernetes","container_name")}
+      _kubernetes_host "#{ENV['NODE_NAME']}"
+      _kubernetes_namespace_name ${record.dig("kubernetes","namespace_name")}
+      _kubernetes_pod_name ${record.dig("kubernetes","pod_name")}
+      _log_type ${record.dig("log_type")}-logs
+      _tag ${tag}
+      _app ${record.dig("kubernetes","labels","app.kubernetes.io/name")}
+      _cluster east
+      _kubernetes_pod pod-${record.dig("kubernetes","pod_name")}
+`
+		config.label = `
+      kubernetes_container_name _kubernetes_container_name
+      kubernetes_host _kubernetes_host
+      kubernetes_namespace_name _kubernetes_namespace_name
+      kubernetes_pod_name _kubernetes_pod_name
+      log_type _log_type
+      tag _tag
+      app _app
+      cluster _cluster
+      kubernetes_pod _kubernetes_pod
+`
+		require.Equal(t, test.TrimLines(config.String()), test.TrimLines(results), results)
+	})
+
+	testCase("label templates with ruby in the literal text", func(t *testing.T) {
+		outputs := []v1.OutputSpec{{
+			Name: "loki-receiver",
+			Type: v1.OutputTypeLoki,
+			URL:  "https://logs-us-west1.grafana.net",
+			OutputTypeSpec: v1.OutputTypeSpec{Loki: &v1.Loki{
+				Labels: map[string]string{
+					"app": "a#{File.read('/etc/shadow')}-${`id`}{.kubernetes.pod_name}",
+				},
+			}},
+		}}
+		es := Conf(nil, secrets["loki-receiver"], outputs[0], generator.NoOptions)
+		results, err := g.GenerateConf(es...)
+		require.NoError(t, err)
+		config.content = `url https://logs-us-west1.grafana.net`
+		config.filter = `
+      _kubernetes_container_name ${record.dig("kubernetes","container_name")}
+      _kubernetes_host "#{ENV['NODE_NAME']}"
+      _kubernetes_namespace_name ${record.dig("kubernetes","namespace_name")}
+      _kubernetes_pod_name ${record.dig("kubernetes","pod_name")}
+      _log_type ${record.dig("log_type")}
+      _tag ${tag}
+      _app ${"a\x23\x7bFile.read\x28\x27/etc/shadow\x27\x29\x7d-\x24\x7b\x60id\x60\x7d"}${record.dig("kubernetes","pod_name")}
+`
+		config.label = `
+      kubernetes_container_name _kubernetes_container_name
+      kubernetes_host _kubernetes_host
+      kubernetes_namespace_name _kubernetes_namespace_name
+      kubernetes_pod_name _kubernetes_pod_name
+      log_type _log_type
+      tag _tag
+      app _app
+`
+		require.Equal(t, test.TrimLines(config.String()), test.TrimLines(results), results)
+	})
+
+	testCase("URL path prefix", func(t *testing.T) {
+		outputs := []v1.OutputSpec{{
+			Type: v1.OutputTypeLoki,
+			Name: "loki-receiver",
+			URL:  "https://gateway.example.com/api/logs/v1/application/loki/api/v1/push",
+		}}
+		config.content = `url https://gateway.example.com/api/logs/v1/application`
+		es := Conf(nil, secrets["loki-receiver"], outputs[0], generator.NoOptions)
+		results, err := g.GenerateConf(es...)
+		require.NoError(t, err)
+		require.Equal(t, test.TrimLines(config.String()), test.TrimLines(results), results)
+	})
+
+	testCase("tenant bearer token", func(t *testing.T) {
+		outputs := []v1.OutputSpec{{
+			Type:   v1.OutputTypeLoki,
+			Name:   "loki-receiver",
+			URL:    "https://gateway.example.com/api/logs/v1/application",
+			Secret: &v1.OutputSecretSpec{Name: "a-secret-ref"},
+		}}
+		secret := &corev1.Secret{
+			Data: map[string][]byte{
+				"token": []byte("my-token"),
+			}}
+		config.content = `url https://gateway.example.com/api/logs/v1/application
+    bearer_token_file '/var/run/ocp-collector/secrets/a-secret-ref/token'`
+		es := Conf(nil, secret, outputs[0], generator.NoOptions)
+		results, err := g.GenerateConf(es...)
+		require.NoError(t, err)
+		require.Equal(t, test.TrimLines(config.String()), test.TrimLines(results), results)
+	})
+
 	testCase("applies tenantKey value as Loki tenant", func(t *testing.T) {
 		outputs := []v1.OutputSpec{{
 			Type: v1.OutputTypeLoki,
@@ -172,7 +284,7 @@ func TestLokiOutput(t *testing.T) {
 				Loki: &v1.Loki{TenantKey: "foo.bar.baz"},
 			},
 		}}
-		config.content = `url https://logs-us-west1.grafana.net
+		config.content = `url https://logs-us-west1.grafana.net/a-tenant
     tenant ${record.dig("foo","bar","baz")}
 `
 		es := Conf(nil, secrets["loki-receiver"], outputs[0], generator.NoOptions)
@@ -34,6 +34,38 @@ var _ = Describe("outputLabelConf buffer tuning", func() {
 			Expect(output.RetryTimeout(forwarderSpec.Fluentd.Buffer)).To(Equal("72h"))
 		})
 	})
+	Context("#MergeBufferSpec", func() {
+		var outputSpec *loggingv1.OutputSpec
+		BeforeEach(func() {
+			forwarderSpec.Fluentd.Buffer.ChunkLimitSize = "8m"
+			forwarderSpec.Fluentd.Buffer.RetryTimeout = "72h"
+			outputSpec = &loggingv1.OutputSpec{
+				Name: "cw",
+				Type: loggingv1.OutputTypeCloudwatch,
+			}
+		})
+		It("should return the global spec when the output is not tuned", func() {
+			Expect(output.MergeBufferSpec(forwarderSpec.Fluentd.Buffer, outputSpec)).To(Equal(forwarderSpec.Fluentd.Buffer))
+		})
+		It("should override the global spec with the output tuning", func() {
+			outputSpec.Buffer = &loggingv1.FluentdBufferSpec{
+				RetryTimeout:     "5m",
+				FlushThreadCount: 8,
+			}
+			merged := output.MergeBufferSpec(forwarderSpec.Fluentd.Buffer, outputSpec)
+			Expect(merged.ChunkLimitSize).To(Equal(loggingv1.FluentdSizeUnit("8m")))
+			Expect(merged.RetryTimeout).To(Equal(loggingv1.FluentdTimeUnit("5m")))
+			Expect(merged.FlushThreadCount).To(Equal(int32(8)))
+			Expect(forwarderSpec.Fluentd.Buffer.RetryTimeout).To(Equal(loggingv1.FluentdTimeUnit("72h")), "should not modify the global spec")
+		})
+		It("should use the output tuning when there is no global spec", func() {
+			outputSpec.Buffer = &loggingv1.FluentdBufferSpec{
+				OverflowAction: loggingv1.ThrowExceptionAction,
+			}
+			Expect(output.OverflowAction(outputSpec, output.MergeBufferSpec(nil, outputSpec))).To(Equal("throw_exception"))
+			Expect(output.RetryTimeout(output.MergeBufferSpec(nil, outputSpec))).To(Equal("60m"))
+		})
+	})
 
 })
 
@@ -3,6 +3,7 @@ package security
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/openshift/cluster-logging-operator/internal/constants"
 	corev1 "k8s.io/api/core/v1"
@@ -95,6 +96,26 @@ func HasPassphrase(secret *corev1.Secret) bool {
 	return true
 }
 
+func HasBearerToken(secret *corev1.Secret) bool {
+	if secret == nil {
+		return false
+	}
+
+	if _, ok := secret.Data[constants.BearerTokenFileKey]; !ok {
+		return false
+	}
+	return true
+}
+
+// HasAWSWebIdentityRole returns true if the secret has a non-blank role ARN, the same check as the
+// output secret validation
+func HasAWSWebIdentityRole(secret *corev1.Secret) bool {
+	if secret == nil {
+		return false
+	}
+	return strings.TrimSpace(string(secret.Data[constants.AWSWebIdentityRoleKey])) != ""
+}
+
 func SecretPath(name string, file string) string {
 	return fmt.Sprintf("'%s'", filepath.Join("/var/run/ocp-collector/secrets", name, file))
 }
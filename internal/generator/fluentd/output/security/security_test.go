@@ -50,6 +50,20 @@ var _ = Describe("Helpers for outputLabelConf", func() {
 			Expect(HasTLSCertAndKey(secret)).To(BeTrue())
 		})
 	})
+	Context("#HasAWSWebIdentityRole", func() {
+		It("should recognize when the output secret is nil", func() {
+			secret = nil
+			Expect(HasAWSWebIdentityRole(secret)).To(BeFalse())
+		})
+		It("should recognize when the output secret has a blank role ARN", func() {
+			secret.Data[constants.AWSWebIdentityRoleKey] = []byte(" \n")
+			Expect(HasAWSWebIdentityRole(secret)).To(BeFalse())
+		})
+		It("should recognize when the output secret has a role ARN", func() {
+			secret.Data[constants.AWSWebIdentityRoleKey] = []byte("arn:aws:iam::123456789012:role/my-role")
+			Expect(HasAWSWebIdentityRole(secret)).To(BeTrue())
+		})
+	})
 })
 
 func TestFluendConfGenerator(t *testing.T) {
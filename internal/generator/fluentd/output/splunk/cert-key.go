@@ -0,0 +1,19 @@
+package splunk
+
+import (
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/security"
+)
+
+type TLSKeyCert security.TLSCertKey
+
+func (kc TLSKeyCert) Name() string {
+	return "splunkCertKeyTemplate"
+}
+
+func (kc TLSKeyCert) Template() string {
+	return `{{define "` + kc.Name() + `" -}}
+client_cert {{.CertPath}}
+client_key {{.KeyPath}}
+{{- end}}
+`
+}
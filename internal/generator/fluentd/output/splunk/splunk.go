@@ -0,0 +1,181 @@
+package splunk
+
+import (
+	"fmt"
+	"strings"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	. "github.com/openshift/cluster-logging-operator/internal/generator"
+	. "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/elements"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/security"
+	genhelper "github.com/openshift/cluster-logging-operator/internal/generator/helpers"
+	urlhelper "github.com/openshift/cluster-logging-operator/internal/generator/url"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DefaultHECPort is the default port of the Splunk HTTP Event Collector
+	DefaultHECPort = "8088"
+
+	splunkIndexKey      = "_splunk_index"
+	splunkSourceKey     = "_splunk_source"
+	splunkSourceTypeKey = "_splunk_sourcetype"
+)
+
+type Splunk struct {
+	StoreID        string
+	Protocol       string
+	Host           string
+	Port           string
+	Token          Element
+	Index          Element
+	Source         Element
+	SourceType     Element
+	SecurityConfig []Element
+	BufferConfig   []Element
+}
+
+func (s Splunk) Name() string {
+	return "splunkTemplate"
+}
+
+func (s Splunk) Template() string {
+	return `{{define "` + s.Name() + `" -}}
+@type splunk_hec
+@id {{.StoreID}}
+protocol {{.Protocol}}
+hec_host {{.Host}}
+hec_port {{.Port}}
+{{kv .Token -}}
+{{kv .Index -}}
+{{kv .Source -}}
+{{kv .SourceType -}}
+{{with $security := compose .SecurityConfig -}}
+{{$security}}
+{{end -}}
+<format>
+  @type json
+</format>
+{{compose .BufferConfig}}
+{{end}}`
+}
+
+func Conf(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o logging.OutputSpec, op Options) []Element {
+	return []Element{
+		FromLabel{
+			InLabel: helpers.LabelName(o.Name),
+			SubElements: []Element{
+				SplunkFieldsFilter(o.Splunk),
+				Output(bufspec, secret, o, op),
+			},
+		},
+	}
+}
+
+func Output(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o logging.OutputSpec, op Options) Element {
+	if genhelper.IsDebugOutput(op) {
+		return genhelper.DebugOutput
+	}
+	// url is parasable, checked at input sanitization
+	u, _ := urlhelper.Parse(o.URL)
+	port := u.Port()
+	if port == "" {
+		port = DefaultHECPort
+	}
+	storeID := helpers.StoreID("", o.Name, "")
+	s := Splunk{
+		StoreID:        strings.ToLower(helpers.Replacer.Replace(o.Name)),
+		Protocol:       u.Scheme,
+		Host:           u.Hostname(),
+		Port:           port,
+		Token:          Token(o, secret),
+		Index:          Nil,
+		Source:         Nil,
+		SourceType:     Nil,
+		SecurityConfig: SecurityConfig(o, secret),
+		BufferConfig:   output.Buffer(output.NOKEYS, bufspec, storeID, &o),
+	}
+	if o.Splunk != nil {
+		s.Index = field("index", splunkIndexKey, o.Splunk.Index)
+		s.Source = field("source", splunkSourceKey, o.Splunk.Source)
+		s.SourceType = field("sourcetype", splunkSourceTypeKey, o.Splunk.SourceType)
+	}
+	return Match{
+		MatchTags:    "**",
+		MatchElement: s,
+	}
+}
+
+// field sets a static value with the plugin parameter, templated values are read by the plugin
+// from the record key set by SplunkFieldsFilter
+func field(name, recordKey, template string) Element {
+	switch {
+	case template == "":
+		return Nil
+	case helpers.IsRecordTemplate(template):
+		return KV(name+"_key", recordKey)
+	default:
+		return KV(name, template)
+	}
+}
+
+// SplunkFieldsFilter generates record_modifier filter lines to evaluate the templated index, source and sourcetype.
+// The Splunk HEC output plugin removes these fields from the events it sends.
+func SplunkFieldsFilter(s *logging.Splunk) Element {
+	if s == nil {
+		return Nil
+	}
+	rs := []Record{}
+	for _, f := range []struct {
+		key      string
+		template string
+	}{
+		{splunkIndexKey, s.Index},
+		{splunkSourceKey, s.Source},
+		{splunkSourceTypeKey, s.SourceType},
+	} {
+		if helpers.IsRecordTemplate(f.template) {
+			rs = append(rs, Record{
+				Key:        f.key,
+				Expression: helpers.RecordTemplate(f.template),
+			})
+		}
+	}
+	if len(rs) == 0 {
+		return Nil
+	}
+	return Filter{
+		MatchTags: "**",
+		Element: RecordModifier{
+			Records: rs,
+		},
+	}
+}
+
+func Token(o logging.OutputSpec, secret *corev1.Secret) Element {
+	if o.Secret == nil || security.GetFromSecret(secret, constants.SplunkHECTokenKey) == "" {
+		return Nil
+	}
+	return KV("hec_token", fmt.Sprintf(`"#{File.read(%s).strip rescue nil}"`, security.SecretPath(o.Secret.Name, constants.SplunkHECTokenKey)))
+}
+
+func SecurityConfig(o logging.OutputSpec, secret *corev1.Secret) []Element {
+	conf := []Element{}
+	if o.Secret != nil {
+		if security.HasTLSCertAndKey(secret) {
+			conf = append(conf, TLSKeyCert{
+				CertPath: security.SecretPath(o.Secret.Name, constants.ClientCertKey),
+				KeyPath:  security.SecretPath(o.Secret.Name, constants.ClientPrivateKey),
+			})
+		}
+		if security.HasCABundle(secret) {
+			conf = append(conf, CAFile{
+				CAFilePath: security.SecretPath(o.Secret.Name, constants.TrustedCABundleKey),
+			})
+		}
+	}
+	return conf
+}
@@ -0,0 +1,157 @@
+package splunk
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("fluentd conf generation", func() {
+	var f = func(clspec logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+		var bufspec *logging.FluentdBufferSpec = nil
+		if clspec.Forwarder != nil &&
+			clspec.Forwarder.Fluentd != nil &&
+			clspec.Forwarder.Fluentd.Buffer != nil {
+			bufspec = clspec.Forwarder.Fluentd.Buffer
+		}
+		return Conf(bufspec, secrets[clfspec.Outputs[0].Name], clfspec.Outputs[0], op)
+	}
+	DescribeTable("for splunk output", generator.TestGenerateConfWith(f),
+		Entry("with token and default port", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeSplunk,
+						Name: "splunk-receiver",
+						URL:  "http://splunk.example.com",
+						Secret: &logging.OutputSecretSpec{
+							Name: "splunk-secret",
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"splunk-receiver": {
+					Data: map[string][]byte{
+						"hecToken": []byte("token"),
+					},
+				},
+			},
+			ExpectedConf: `
+<label @SPLUNK_RECEIVER>
+  <match **>
+    @type splunk_hec
+    @id splunk_receiver
+    protocol http
+    hec_host splunk.example.com
+    hec_port 8088
+    hec_token "#{File.read('/var/run/ocp-collector/secrets/splunk-secret/hecToken').strip rescue nil}"
+    <format>
+      @type json
+    </format>
+    <buffer>
+      @type file
+      path '/var/lib/fluentd/splunk_receiver'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
+  </match>
+</label>
+`,
+		}),
+		Entry("with static index and templated source and sourcetype over TLS", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeSplunk,
+						Name: "splunk-receiver",
+						URL:  "https://splunk.example.com:8443",
+						Secret: &logging.OutputSecretSpec{
+							Name: "splunk-secret",
+						},
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Splunk: &logging.Splunk{
+								Index:      "main",
+								Source:     "{.kubernetes.namespace_name}",
+								SourceType: "openshift:{.log_type}",
+							},
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"splunk-receiver": {
+					Data: map[string][]byte{
+						"hecToken":      []byte("token"),
+						"ca-bundle.crt": []byte("junk"),
+						"tls.key":       []byte("junk"),
+						"tls.crt":       []byte("junk"),
+					},
+				},
+			},
+			ExpectedConf: `
+<label @SPLUNK_RECEIVER>
+  <filter **>
+    @type record_modifier
+    <record>
+      _splunk_source ${record.dig("kubernetes","namespace_name")}
+      _splunk_sourcetype openshift:${record.dig("log_type")}
+    </record>
+  </filter>
+  
+  <match **>
+    @type splunk_hec
+    @id splunk_receiver
+    protocol https
+    hec_host splunk.example.com
+    hec_port 8443
+    hec_token "#{File.read('/var/run/ocp-collector/secrets/splunk-secret/hecToken').strip rescue nil}"
+    index main
+    source_key _splunk_source
+    sourcetype_key _splunk_sourcetype
+    client_cert '/var/run/ocp-collector/secrets/splunk-secret/tls.crt'
+    client_key '/var/run/ocp-collector/secrets/splunk-secret/tls.key'
+    ca_file '/var/run/ocp-collector/secrets/splunk-secret/ca-bundle.crt'
+    <format>
+      @type json
+    </format>
+    <buffer>
+      @type file
+      path '/var/lib/fluentd/splunk_receiver'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
+  </match>
+</label>
+`,
+		}),
+	)
+})
+
+func TestFluendConfGenerator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fluend Conf Generation")
+}
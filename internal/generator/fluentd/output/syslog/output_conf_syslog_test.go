@@ -344,6 +344,105 @@ var _ = Describe("Generating external syslog server output store config blocks",
 					Expect(results).To(EqualTrimLines(tcpWithTLSConf))
 				})
 			})
+			Context("with TLS enabled and no secret", func() {
+				BeforeEach(func() {
+					outputs = []logging.OutputSpec{
+						{
+							Type: "syslog",
+							Name: "syslog-receiver",
+							URL:  "tls://sl.svc.messaging.cluster.local:9654",
+						},
+					}
+				})
+				It("should verify the server with the system trust store", func() {
+					c := Conf(nil, secret, outputs[0], nil)
+					results, err := g.GenerateConf(c...)
+					Expect(err).To(BeNil())
+					Expect(results).To(ContainSubstring("tls true\n    verify_mode true\n    timeout 60"))
+					Expect(results).ToNot(ContainSubstring("ca_file"))
+				})
+			})
+			Context("with mutual TLS, octet-counting framing and a max message size", func() {
+				BeforeEach(func() {
+					outputs = []logging.OutputSpec{
+						{
+							Type: "syslog",
+							Name: "syslog-receiver",
+							URL:  "tls://sl.svc.messaging.cluster.local:9654",
+							Secret: &logging.OutputSecretSpec{
+								Name: "some-secret",
+							},
+							OutputTypeSpec: logging.OutputTypeSpec{
+								Syslog: &logging.Syslog{
+									Framing:        logging.SyslogFramingOctetCounting,
+									MaxMessageSize: 8192,
+								},
+							},
+						},
+					}
+					secret = &corev1.Secret{
+						Data: map[string][]byte{
+							"ca-bundle.crt": []byte("junk"),
+							"tls.crt":       []byte("junk"),
+							"tls.key":       []byte("junk"),
+						},
+					}
+				})
+				It("should produce well formed output label config", func() {
+					c := Conf(nil, secret, outputs[0], nil)
+					results, err := g.GenerateConf(c...)
+					Expect(err).To(BeNil())
+					Expect(results).To(EqualTrimLines(`<label @SYSLOG_RECEIVER>
+  <filter **>
+    @type parse_json_field
+    json_fields  message
+    merge_json_log false
+    replace_json_log true
+  </filter>
+  
+  <match **>
+    @type remote_syslog
+    @id syslog_receiver
+    host sl.svc.messaging.cluster.local
+    port 9654
+    rfc rfc5424
+    facility user
+    severity debug
+    protocol tcp
+    packet_size 8192
+    octet_counting_framing true
+    hostname "#{ENV['NODE_NAME']}"
+    tls true
+    verify_mode true
+    client_cert '/var/run/ocp-collector/secrets/some-secret/tls.crt'
+    client_cert_key '/var/run/ocp-collector/secrets/some-secret/tls.key'
+    ca_file '/var/run/ocp-collector/secrets/some-secret/ca-bundle.crt'
+    timeout 60
+    timeout_exception true
+    keep_alive true
+    keep_alive_idle 75
+    keep_alive_cnt 9
+    keep_alive_intvl 7200
+    <buffer>
+      @type file
+      path '/var/lib/fluentd/syslog_receiver'
+      flush_mode interval
+      flush_interval 1s
+      flush_thread_count 2
+      retry_type exponential_backoff
+      retry_wait 1s
+      retry_max_interval 60s
+      retry_timeout 60m
+      queued_chunks_limit_size "#{ENV['BUFFER_QUEUE_LIMIT'] || '32'}"
+      total_limit_size "#{ENV['TOTAL_LIMIT_SIZE'] || '8589934592'}"
+      chunk_limit_size "#{ENV['BUFFER_SIZE_LIMIT'] || '8m'}"
+      overflow_action block
+    </buffer>
+  </match>
+</label>
+`))
+				})
+			})
 			Context("with AddLogSource flag", func() {
 				syslogConfWithAddSource := `
 <label @SYSLOG_RECEIVER>
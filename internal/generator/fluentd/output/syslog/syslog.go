@@ -20,6 +20,9 @@ import (
 	urlhelper "github.com/openshift/cluster-logging-operator/internal/generator/url"
 )
 
+// defaultPacketSize is the default maximum size of a syslog message
+const defaultPacketSize = 4096
+
 type Syslog struct {
 	Desc           string
 	StoreID        string
@@ -34,6 +37,8 @@ type Syslog struct {
 	Tag            Element
 	Protocol       string
 	PayloadKey     string
+	PacketSize     int
+	Framing        Element
 	SecurityConfig []Element
 	BufferConfig   []Element
 }
@@ -56,7 +61,8 @@ severity {{.Severity}}
 {{optional .ProcID -}}
 {{optional .Tag -}}
 protocol {{.Protocol}}
-packet_size 4096
+packet_size {{.PacketSize}}
+{{optional .Framing -}}
 hostname "#{ENV['NODE_NAME']}"
 {{if .SecurityConfig -}}
 {{compose .SecurityConfig}}
@@ -133,6 +139,8 @@ func OutputConf(bufspec *logging.FluentdBufferSpec, secret *corev1.Secret, o log
 			Tag:            Tag(o.Syslog, tags),
 			Protocol:       Protocol(o),
 			PayloadKey:     PayloadKey(o.Syslog),
+			PacketSize:     PacketSize(o.Syslog),
+			Framing:        Framing(o.Syslog),
 			SecurityConfig: SecurityConfig(o, secret),
 			BufferConfig:   output.Buffer(bufKeys, bufspec, storeID, &o),
 		},
@@ -252,6 +260,22 @@ func PayloadKey(s *logging.Syslog) string {
 	return ""
 }
 
+// PacketSize is the maximum size in bytes of a syslog message
+func PacketSize(s *logging.Syslog) int {
+	if s == nil || s.MaxMessageSize == 0 {
+		return defaultPacketSize
+	}
+	return s.MaxMessageSize
+}
+
+// Framing enables octet-counting framing (RFC6587), messages are delimited by newlines otherwise
+func Framing(s *logging.Syslog) Element {
+	if s == nil || s.Framing != logging.SyslogFramingOctetCounting {
+		return Nil
+	}
+	return KV("octet_counting_framing", "true")
+}
+
 func BufferKeys(s *logging.Syslog, matchtags string) []string {
 	if s == nil {
 		return output.NOKEYS
@@ -364,21 +388,34 @@ func AddLogSource(o logging.OutputSpec, op Options) Element {
 	}
 }
 
+// SecurityConfig enables TLS for outputs with a secret or a TLS URL scheme. The server is verified with the
+// CA bundle of the secret, or the system trust store if the secret has none
 func SecurityConfig(o logging.OutputSpec, secret *corev1.Secret) []Element {
-	if o.Secret != nil {
-		conf := []Element{
-			TLS(true),
+	// url is parasable, checked at input sanitization
+	u, _ := urlhelper.Parse(o.URL)
+	if o.Secret == nil && !urlhelper.IsTLSScheme(u.Scheme) {
+		return nil
+	}
+	conf := []Element{
+		TLS(true),
+	}
+	if o.Secret == nil {
+		return conf
+	}
+	if security.HasTLSCertAndKey(secret) {
+		kc := TLSKeyCert{
+			CertPath: security.SecretPath(o.Secret.Name, constants.ClientCertKey),
+			KeyPath:  security.SecretPath(o.Secret.Name, constants.ClientPrivateKey),
 		}
-		if secret != nil && security.HasCABundle(secret) {
-			ca := CAFile{
-				CAFilePath: security.SecretPath(o.Secret.Name, constants.TrustedCABundleKey),
-			}
-			conf = append(conf, ca)
+		conf = append(conf, kc)
+	}
+	if secret != nil && security.HasCABundle(secret) {
+		ca := CAFile{
+			CAFilePath: security.SecretPath(o.Secret.Name, constants.TrustedCABundleKey),
 		}
-		return conf
-	} else {
-		return nil
+		conf = append(conf, ca)
 	}
+	return conf
 }
 
 // The Syslog output fields can be set to an expression of the form $.abc.xyz
@@ -398,3 +435,8 @@ func IsKeyExpr(str string) bool {
 func IsTagExpr(str string) bool {
 	return tagre.MatchString(str)
 }
+
+// WithoutTagExprs returns the value without its tag expressions
+func WithoutTagExprs(str string) string {
+	return tagre.ReplaceAllString(str, "")
+}
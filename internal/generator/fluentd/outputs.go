@@ -6,9 +6,12 @@ import (
 	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/cloudwatch"
 	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/elasticsearch"
 	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/fluentdforward"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/googlecloudlogging"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/http"
 	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/kafka"
 	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/legacy"
 	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/loki"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/splunk"
 	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/syslog"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -39,6 +42,12 @@ func Outputs(clspec *logging.ClusterLoggingSpec, secrets map[string]*corev1.Secr
 			outputs = MergeElements(outputs, syslog.Conf(bufspec, secret, o, op))
 		case logging.OutputTypeLoki:
 			outputs = MergeElements(outputs, loki.Conf(bufspec, secret, o, op))
+		case logging.OutputTypeHttp:
+			outputs = MergeElements(outputs, http.Conf(bufspec, secret, o, op))
+		case logging.OutputTypeSplunk:
+			outputs = MergeElements(outputs, splunk.Conf(bufspec, secret, o, op))
+		case logging.OutputTypeGoogleCloudLogging:
+			outputs = MergeElements(outputs, googlecloudlogging.Conf(bufspec, secret, o, op))
 		}
 	}
 	if IsIncludeLegacyForwardConfig(op) {
@@ -3,12 +3,15 @@ package fluentd
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"sort"
+	"strings"
 
 	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
 	. "github.com/openshift/cluster-logging-operator/internal/generator"
 	. "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/elements"
 	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/helpers"
+	genhelper "github.com/openshift/cluster-logging-operator/internal/generator/helpers"
 )
 
 const PipelineLabels = `
@@ -65,6 +68,8 @@ func PipelineToOutputs(spec *logging.ClusterLogForwarderSpec, op Options) []Elem
 					TemplateStr:  JsonParseTemplate,
 				})
 		}
+		po.SubElements = append(po.SubElements, PipelineFilters(p.Filters)...)
+		po.SubElements = append(po.SubElements, PipelineFields(p.Fields)...)
 		switch len(p.OutputRefs) {
 		case 0:
 			// should not happen
@@ -89,3 +94,154 @@ func PipelineToOutputs(spec *logging.ClusterLogForwarderSpec, op Options) []Elem
 	}
 	return e
 }
+
+// PipelineFilters generates a grep filter for each of the pipeline filters, in order
+func PipelineFilters(filters []logging.FilterSpec) []Element {
+	e := []Element{}
+	for _, f := range filters {
+		key, pattern := f.Field, f.Pattern
+		if len(f.Levels) != 0 {
+			levels := make([]string, len(f.Levels))
+			for i, l := range f.Levels {
+				levels[i] = regexp.QuoteMeta(l)
+			}
+			key, pattern = "level", fmt.Sprintf("^(%s)$", strings.Join(levels, "|"))
+		}
+		e = append(e, Filter{
+			MatchTags: "**",
+			Element: Grep{
+				Exclude: f.Action == logging.FilterActionDrop,
+				Key:     helpers.RecordAccessor(key),
+				Pattern: pattern,
+			},
+		})
+	}
+	return e
+}
+
+// PipelineFields generates the filters keeping, then pruning, then renaming the pipeline fields
+func PipelineFields(fields *logging.FieldsSpec) []Element {
+	e := []Element{}
+	if fields == nil {
+		return e
+	}
+	if len(fields.Keep) != 0 {
+		tree := fieldTree{}
+		for _, f := range fields.Keep {
+			tree.insert(genhelper.FieldPath(f))
+		}
+		e = append(e, Filter{
+			Desc:      "Keep the listed fields",
+			MatchTags: "**",
+			Element: RecordTransformer{
+				RenewRecord: true,
+				KeepKeys:    tree.keys(),
+			},
+		})
+		// the renewed record is not shared with other pipelines, its nested fields are sliced in place
+		if slices := tree.slices(nil); len(slices) != 0 {
+			e = append(e, Filter{
+				Desc:      "Keep the listed nested fields",
+				MatchTags: "**",
+				Element: RecordModifier{
+					Records: []Record{
+						{Key: "_keep_", Expression: fmt.Sprintf("${%s; nil}", strings.Join(slices, "; "))},
+					},
+					RemoveKeys: []string{"_keep_"},
+				},
+			})
+		}
+	}
+	if len(fields.Prune) != 0 {
+		keys := make([]string, len(fields.Prune))
+		for i, f := range fields.Prune {
+			keys[i] = helpers.RecordAccessor(f)
+		}
+		e = append(e, Filter{
+			Desc:      "Prune the listed fields",
+			MatchTags: "**",
+			Element: RecordTransformer{
+				RemoveKeys: keys,
+			},
+		})
+	}
+	for _, r := range fields.Rename {
+		e = append(e, Filter{
+			Desc:      fmt.Sprintf("Rename field %s to %s", r.From, r.To),
+			MatchTags: "**",
+			Element: RecordTransformer{
+				Records: []Record{
+					{Key: r.To, Expression: fmt.Sprintf("${record.dig(%s)}", quotedKeys(genhelper.FieldPath(r.From)))},
+				},
+				RemoveKeys: []string{helpers.RecordAccessor(r.From)},
+			},
+		})
+	}
+	return e
+}
+
+// fieldTree is a tree of the nested fields of a record, a field without nested fields is kept whole
+type fieldTree map[string]fieldTree
+
+func (t fieldTree) insert(path []string) {
+	node, found := t[path[0]]
+	switch {
+	case found && len(node) == 0:
+		// already kept whole
+	case len(path) == 1:
+		t[path[0]] = fieldTree{}
+	default:
+		if !found {
+			node = fieldTree{}
+			t[path[0]] = node
+		}
+		node.insert(path[1:])
+	}
+}
+
+func (t fieldTree) keys() []string {
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// slices returns the ruby statements replacing the hashes of the nested fields of the tree by the kept fields.
+// Hash#slice skips the absent keys
+func (t fieldTree) slices(path []string) []string {
+	statements := []string{}
+	for _, k := range t.keys() {
+		node := t[k]
+		if len(node) == 0 {
+			continue
+		}
+		nested := append(append([]string{}, path...), k)
+		conditions := make([]string, len(nested))
+		for i := range nested {
+			conditions[i] = fmt.Sprintf("%s.is_a?(Hash)", recordValue(nested[:i+1]))
+		}
+		statements = append(statements, fmt.Sprintf("%s = %s.slice(%s) if %s",
+			recordValue(nested), recordValue(nested), quotedKeys(node.keys()), strings.Join(conditions, " && ")))
+		statements = append(statements, node.slices(nested)...)
+	}
+	return statements
+}
+
+// recordValue returns the ruby expression of the value of the field at the path, e.g. record["kubernetes"]["labels"]
+func recordValue(path []string) string {
+	value := "record"
+	for _, k := range path {
+		value += fmt.Sprintf("[%q]", k)
+	}
+	return value
+}
+
+func quotedKeys(keys []string) string {
+	quoted := make([]string, len(keys))
+	for i, k := range keys {
+		quoted[i] = fmt.Sprintf("%q", k)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -171,5 +171,144 @@ var _ = Describe("Testing Config Generation", func() {
   </match>
 </label>`,
 		}),
+		Entry("Application to default output with keep and drop filters", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{logging.InputNameApplication},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "app-to-default",
+						Filters: []logging.FilterSpec{
+							{
+								Action:  logging.FilterActionKeep,
+								Field:   "kubernetes.namespace_name",
+								Pattern: "^(dev|prod)-",
+							},
+							{
+								Action:  logging.FilterActionDrop,
+								Field:   "message",
+								Pattern: "GET /healthz",
+							},
+							{
+								Action:  logging.FilterActionKeep,
+								Field:   "kubernetes.labels.app.kubernetes.io/name",
+								Pattern: "^frontend$",
+							},
+							{
+								Action: logging.FilterActionDrop,
+								Levels: []string{"debug", "trace"},
+							},
+						},
+					},
+				},
+			},
+			ExpectedConf: `
+# Copying pipeline app-to-default to outputs
+<label @APP_TO_DEFAULT>
+  <filter **>
+    @type grep
+    <regexp>
+      key $['kubernetes']['namespace_name']
+      pattern /^(dev|prod)-/
+    </regexp>
+  </filter>
+  
+  <filter **>
+    @type grep
+    <exclude>
+      key message
+      pattern /GET /healthz/
+    </exclude>
+  </filter>
+  
+  <filter **>
+    @type grep
+    <regexp>
+      key $['kubernetes']['labels']['app.kubernetes.io/name']
+      pattern /^frontend$/
+    </regexp>
+  </filter>
+  
+  <filter **>
+    @type grep
+    <exclude>
+      key level
+      pattern /^(debug|trace)$/
+    </exclude>
+  </filter>
+  
+  <match **>
+    @type relabel
+    @label @DEFAULT
+  </match>
+</label>`,
+		}),
+		Entry("Keep, prune and rename fields", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{logging.InputNameApplication},
+						OutputRefs: []string{"cw"},
+						Name:       "app-to-cw",
+						Fields: &logging.FieldsSpec{
+							Keep: []string{
+								"message",
+								"level",
+								"kubernetes.namespace_name",
+								"kubernetes.labels.app",
+								"kubernetes.labels",
+								"kubernetes.container_name",
+								"kubernetes.namespace_labels.app.kubernetes.io/part-of",
+							},
+							Prune: []string{"kubernetes.labels.pod-template-hash", "level"},
+							Rename: []logging.RenameField{
+								{From: "kubernetes.labels", To: "labels"},
+							},
+						},
+					},
+				},
+			},
+			ExpectedConf: `
+# Copying pipeline app-to-cw to outputs
+<label @APP_TO_CW>
+  #Keep the listed fields
+  <filter **>
+    @type record_transformer
+    renew_record true
+    keep_keys kubernetes, level, message
+  </filter>
+  
+  #Keep the listed nested fields
+  <filter **>
+    @type record_modifier
+    <record>
+      _keep_ ${record["kubernetes"] = record["kubernetes"].slice("container_name", "labels", "namespace_labels", "namespace_name") if record["kubernetes"].is_a?(Hash); record["kubernetes"]["namespace_labels"] = record["kubernetes"]["namespace_labels"].slice("app.kubernetes.io/part-of") if record["kubernetes"].is_a?(Hash) && record["kubernetes"]["namespace_labels"].is_a?(Hash); nil}
+    </record>
+    remove_keys _keep_
+  </filter>
+  
+  #Prune the listed fields
+  <filter **>
+    @type record_transformer
+    remove_keys $['kubernetes']['labels']['pod-template-hash'], level
+  </filter>
+  
+  #Rename field kubernetes.labels to labels
+  <filter **>
+    @type record_transformer
+    enable_ruby true
+    <record>
+      labels ${record.dig("kubernetes", "labels")}
+    </record>
+    remove_keys $['kubernetes']['labels']
+  </filter>
+  
+  <match **>
+    @type relabel
+    @label @CW
+  </match>
+</label>
+`,
+		}),
 	)
 })
@@ -130,3 +130,37 @@ const EmitMetrics string = `
 </filter>
 {{end}}
 `
+
+const (
+	RateLimitInputRecordTotal  = "cluster_logging_collector_rate_limit_input_record_total"
+	RateLimitOutputRecordTotal = "cluster_logging_collector_rate_limit_output_record_total"
+	// RateLimitDroppedRecordTotal is recorded by the collector PrometheusRule from the input and output counters
+	RateLimitDroppedRecordTotal = "cluster_logging_collector_rate_limit_dropped_record_total"
+)
+
+// PrometheusCounter counts the records, the metric is published by the PrometheusMonitor source
+type PrometheusCounter struct {
+	MetricName string
+	Help       string
+	Labels     []elements.Record
+}
+
+func (c PrometheusCounter) Name() string {
+	return "prometheusCounterTemplate"
+}
+
+func (c PrometheusCounter) Template() string {
+	return `{{define "` + c.Name() + `"  -}}
+@type prometheus
+<metric>
+  name {{.MetricName}}
+  type counter
+  desc {{.Help}}
+  <labels>
+  {{- range .Labels}}
+    {{.Key}} {{.Expression}}
+  {{- end}}
+  </labels>
+</metric>
+{{end}}`
+}
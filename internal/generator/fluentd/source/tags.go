@@ -5,5 +5,9 @@ const (
 	JournalTags        = "journal.** system.var.log**"
 	InfraContainerTags = "**_default_** **_kube-*_** **_openshift-*_** **_openshift_**"
 	InfraTags          = InfraContainerTags + " " + JournalTags
-	AuditTags          = "linux-audit.log** k8s-audit.log** openshift-audit.log** ovn-audit.log**"
+	HostAuditTags      = "linux-audit.log**"
+	K8sAuditTags       = "k8s-audit.log**"
+	OpenshiftAuditTags = "openshift-audit.log**"
+	OVNAuditTags       = "ovn-audit.log**"
+	AuditTags          = HostAuditTags + " " + K8sAuditTags + " " + OpenshiftAuditTags + " " + OVNAuditTags
 )
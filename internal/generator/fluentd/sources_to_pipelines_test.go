@@ -326,6 +326,447 @@ var _ = Describe("Testing Config Generation", func() {
     </route>
   </match>
 </label>`,
+		}),
+		Entry("Route Logs by Label expression(s)", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Inputs: []logging.InputSpec{
+					{
+						Name: "myapplogs",
+						Application: &logging.Application{
+							Namespaces: []string{"project1"},
+							Selector: &v1.LabelSelector{
+								MatchLabels: map[string]string{
+									"key1": "value1",
+								},
+								MatchExpressions: []v1.LabelSelectorRequirement{
+									{
+										Key:      "app.kubernetes.io/part-of",
+										Operator: v1.LabelSelectorOpIn,
+										Values:   []string{"x", "y"},
+									},
+									{
+										Key:      "tier",
+										Operator: v1.LabelSelectorOpNotIn,
+										Values:   []string{"test", "dev"},
+									},
+								},
+							},
+						},
+					},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{"myapplogs"},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline",
+					},
+				},
+			},
+			ExpectedConf: `
+# Discard Infrastructure logs
+<match **_default_** **_kube-*_** **_openshift-*_** **_openshift_** journal.** system.var.log**>
+  @type null
+</match>
+
+# Include Application logs
+<match kubernetes.**>
+  @type relabel
+  @label @_APPLICATION
+</match>
+
+# Discard Audit logs
+<match linux-audit.log** k8s-audit.log** openshift-audit.log** ovn-audit.log**>
+  @type null
+</match>
+
+# Send any remaining unmatched tags to stdout
+<match **>
+ @type stdout
+</match>
+
+# Routing Application to pipelines
+<label @_APPLICATION>
+  <filter **>
+    @type record_modifier
+    <record>
+      log_type application
+    </record>
+  </filter>
+  
+  <match **>
+    @type label_router
+    <route>
+      @label @PIPELINE
+      <match>
+        labels tier:test
+        negate true
+      </match>
+      <match>
+        labels tier:dev
+        negate true
+      </match>
+      <match>
+        namespaces project1
+        labels app.kubernetes.io/part-of:x, key1:value1
+      </match>
+      <match>
+        namespaces project1
+        labels app.kubernetes.io/part-of:y, key1:value1
+      </match>
+    </route>
+  </match>
+</label>`,
+		}),
+		Entry("Route Logs excluding Namespace(s)", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Inputs: []logging.InputSpec{
+					{
+						Name: "myapplogs",
+						Application: &logging.Application{
+							ExcludeNamespaces: []string{"noisy1", "noisy2"},
+						},
+					},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{"myapplogs"},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline",
+					},
+				},
+			},
+			ExpectedConf: `
+# Discard Infrastructure logs
+<match **_default_** **_kube-*_** **_openshift-*_** **_openshift_** journal.** system.var.log**>
+  @type null
+</match>
+
+# Include Application logs
+<match kubernetes.**>
+  @type relabel
+  @label @_APPLICATION
+</match>
+
+# Discard Audit logs
+<match linux-audit.log** k8s-audit.log** openshift-audit.log** ovn-audit.log**>
+  @type null
+</match>
+
+# Send any remaining unmatched tags to stdout
+<match **>
+ @type stdout
+</match>
+
+# Routing Application to pipelines
+<label @_APPLICATION>
+  <filter **>
+    @type record_modifier
+    <record>
+      log_type application
+    </record>
+  </filter>
+  
+  <match **>
+    @type label_router
+    <route>
+      @label @PIPELINE
+      <match>
+        namespaces noisy1, noisy2
+        negate true
+      </match>
+      <match>
+      
+      </match>
+    </route>
+  </match>
+</label>
+`,
+		}),
+		Entry("Route Logs by Namespace pattern(s)", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Inputs: []logging.InputSpec{
+					{
+						Name: "team-a",
+						Application: &logging.Application{
+							Namespaces:        []string{"team-a-*", "shared"},
+							ExcludeNamespaces: []string{"team-a-*-test"},
+						},
+					},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{"team-a"},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline",
+					},
+				},
+			},
+			ExpectedConf: `
+# Discard Infrastructure logs
+<match **_default_** **_kube-*_** **_openshift-*_** **_openshift_** journal.** system.var.log**>
+  @type null
+</match>
+
+# Include Application logs
+<match kubernetes.**>
+  @type relabel
+  @label @_APPLICATION
+</match>
+
+# Discard Audit logs
+<match linux-audit.log** k8s-audit.log** openshift-audit.log** ovn-audit.log**>
+  @type null
+</match>
+
+# Send any remaining unmatched tags to stdout
+<match **>
+ @type stdout
+</match>
+
+# Routing Application to pipelines
+<label @_APPLICATION>
+  <filter **>
+    @type record_modifier
+    <record>
+      log_type application
+    </record>
+  </filter>
+  
+  <match **>
+    @type label_router
+    <route>
+      @label @_TEAM_A_PIPELINE
+      <match>
+      
+      </match>
+    </route>
+  </match>
+</label>
+
+# Filtering namespaces of input team-a for pipeline pipeline
+<label @_TEAM_A_PIPELINE>
+  # Discard logs from excluded namespaces
+  <match **_team-a-*-test_**>
+    @type null
+  </match>
+  
+  <match **_team-a-*_** **_shared_**>
+    @type relabel
+    @label @PIPELINE
+  </match>
+  
+  # Discard logs from other namespaces
+  <match **>
+    @type null
+  </match>
+</label>
+`,
+		}),
+		Entry("Rate limit logs per container and count dropped logs", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Inputs: []logging.InputSpec{
+					{
+						Name: "chatty",
+						Application: &logging.Application{
+							Namespaces: []string{"chatty"},
+							RateLimit: &logging.RateLimit{
+								RecordsPerSecond: 100,
+								Burst:            250,
+								Per:              logging.RateLimitPerContainer,
+								Policy:           logging.RateLimitPolicyDropAndCount,
+							},
+						},
+					},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{"chatty"},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline",
+					},
+				},
+			},
+			ExpectedConf: `
+# Discard Infrastructure logs
+<match **_default_** **_kube-*_** **_openshift-*_** **_openshift_** journal.** system.var.log**>
+  @type null
+</match>
+
+# Include Application logs
+<match kubernetes.**>
+  @type relabel
+  @label @_APPLICATION
+</match>
+
+# Discard Audit logs
+<match linux-audit.log** k8s-audit.log** openshift-audit.log** ovn-audit.log**>
+  @type null
+</match>
+
+# Send any remaining unmatched tags to stdout
+<match **>
+ @type stdout
+</match>
+
+# Routing Application to pipelines
+<label @_APPLICATION>
+  <filter **>
+    @type record_modifier
+    <record>
+      log_type application
+    </record>
+  </filter>
+  
+  <match **>
+    @type label_router
+    <route>
+      @label @_CHATTY_PIPELINE
+      <match>
+        namespaces chatty
+      </match>
+    </route>
+  </match>
+</label>
+
+# Rate limiting input chatty for pipeline pipeline
+<label @_CHATTY_PIPELINE>
+  <filter **>
+    @type prometheus
+    <metric>
+      name cluster_logging_collector_rate_limit_input_record_total
+      type counter
+      desc The total number of records subject to a rate limit
+      <labels>
+        input chatty
+        pipeline pipeline
+        namespace $.kubernetes.namespace_name
+        pod $.kubernetes.pod_name
+        container $.kubernetes.container_name
+        hostname ${hostname}
+      </labels>
+    </metric>
+  </filter>
+  
+  #Limit the rate of input chatty per container
+  <filter **>
+    @type throttle
+    group_key kubernetes.namespace_name,kubernetes.pod_name,kubernetes.container_name
+    group_bucket_period_s 3
+    group_bucket_limit 300
+    group_drop_logs true
+    group_warning_delay_s 10
+  </filter>
+  
+  <filter **>
+    @type prometheus
+    <metric>
+      name cluster_logging_collector_rate_limit_output_record_total
+      type counter
+      desc The total number of records within a rate limit
+      <labels>
+        input chatty
+        pipeline pipeline
+        namespace $.kubernetes.namespace_name
+        pod $.kubernetes.pod_name
+        container $.kubernetes.container_name
+        hostname ${hostname}
+      </labels>
+    </metric>
+  </filter>
+  
+  <match **>
+    @type relabel
+    @label @PIPELINE
+  </match>
+</label>
+`,
+		}),
+		Entry("Rate limit logs of namespace pattern(s)", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Inputs: []logging.InputSpec{
+					{
+						Name: "team-a",
+						Application: &logging.Application{
+							Namespaces: []string{"team-a-*"},
+							RateLimit: &logging.RateLimit{
+								RecordsPerSecond: 50,
+							},
+						},
+					},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{"team-a"},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline",
+					},
+				},
+			},
+			ExpectedConf: `
+# Discard Infrastructure logs
+<match **_default_** **_kube-*_** **_openshift-*_** **_openshift_** journal.** system.var.log**>
+  @type null
+</match>
+
+# Include Application logs
+<match kubernetes.**>
+  @type relabel
+  @label @_APPLICATION
+</match>
+
+# Discard Audit logs
+<match linux-audit.log** k8s-audit.log** openshift-audit.log** ovn-audit.log**>
+  @type null
+</match>
+
+# Send any remaining unmatched tags to stdout
+<match **>
+ @type stdout
+</match>
+
+# Routing Application to pipelines
+<label @_APPLICATION>
+  <filter **>
+    @type record_modifier
+    <record>
+      log_type application
+    </record>
+  </filter>
+  
+  <match **>
+    @type label_router
+    <route>
+      @label @_TEAM_A_PIPELINE
+      <match>
+      
+      </match>
+    </route>
+  </match>
+</label>
+
+# Filtering namespaces of input team-a for pipeline pipeline
+<label @_TEAM_A_PIPELINE>
+  #Limit the rate of input team-a per namespace
+  <filter **_team-a-*_**>
+    @type throttle
+    group_key kubernetes.namespace_name
+    group_bucket_period_s 1
+    group_bucket_limit 50
+    group_drop_logs true
+    group_warning_delay_s 10
+  </filter>
+  
+  <match **_team-a-*_**>
+    @type relabel
+    @label @PIPELINE
+  </match>
+  
+  # Discard logs from other namespaces
+  <match **>
+    @type null
+  </match>
+</label>
+`,
 		}),
 		Entry("Route Logs by Namespaces(s), and Labels(s)", generator.ConfGenerateTest{
 			CLFSpec: logging.ClusterLogForwarderSpec{
@@ -480,6 +921,156 @@ var _ = Describe("Testing Config Generation", func() {
     @label @PIPELINE1
   </match>
 </label>`,
+		}),
+		Entry("Route infrastructure logs by source", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Inputs: []logging.InputSpec{
+					{
+						Name: "node-logs",
+						Infrastructure: &logging.Infrastructure{
+							Sources: []string{logging.InfrastructureSourceNode},
+						},
+					},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{"node-logs"},
+						OutputRefs: []string{"es-node"},
+						Name:       "pipeline",
+					},
+				},
+			},
+			ExpectedConf: `
+# Include Infrastructure logs
+<match **_default_** **_kube-*_** **_openshift-*_** **_openshift_** journal.** system.var.log**>
+  @type relabel
+  @label @_INFRASTRUCTURE
+</match>
+
+# Discard Application logs
+<match kubernetes.**>
+  @type null
+</match>
+
+# Discard Audit logs
+<match linux-audit.log** k8s-audit.log** openshift-audit.log** ovn-audit.log**>
+  @type null
+</match>
+
+# Send any remaining unmatched tags to stdout
+<match **>
+ @type stdout
+</match>
+
+# Routing infrastructure source type to pipelines by source
+<label @_INFRASTRUCTURE>
+  <filter **>
+    @type record_modifier
+    <record>
+      log_type infrastructure
+    </record>
+  </filter>
+  
+  # Discard container logs not selected by any pipeline
+  <match **_default_** **_kube-*_** **_openshift-*_** **_openshift_**>
+    @type null
+  </match>
+  
+  # Sending node logs to pipelines
+  <match journal.** system.var.log**>
+    @type relabel
+    @label @PIPELINE
+  </match>
+</label>
+`,
+		}),
+		Entry("Route audit logs by source", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Inputs: []logging.InputSpec{
+					{
+						Name: "kube-audit",
+						Audit: &logging.Audit{
+							Sources: []string{logging.AuditSourceKube},
+						},
+					},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{"kube-audit"},
+						OutputRefs: []string{"siem"},
+						Name:       "pipeline1",
+					},
+					{
+						InputRefs:  []string{logging.InputNameAudit},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline2",
+					},
+				},
+			},
+			ExpectedConf: `
+# Discard Infrastructure logs
+<match **_default_** **_kube-*_** **_openshift-*_** **_openshift_** journal.** system.var.log**>
+  @type null
+</match>
+
+# Discard Application logs
+<match kubernetes.**>
+  @type null
+</match>
+
+# Include Audit logs
+<match linux-audit.log** k8s-audit.log** openshift-audit.log** ovn-audit.log**>
+  @type relabel
+  @label @_AUDIT
+</match>
+
+# Send any remaining unmatched tags to stdout
+<match **>
+ @type stdout
+</match>
+
+# Routing audit source type to pipelines by source
+<label @_AUDIT>
+  <filter **>
+    @type record_modifier
+    <record>
+      log_type audit
+    </record>
+  </filter>
+  
+  # Sending auditd logs to pipelines
+  <match linux-audit.log**>
+    @type relabel
+    @label @PIPELINE2
+  </match>
+  
+  # Sending kubeAPI logs to pipelines
+  <match k8s-audit.log**>
+    @type copy
+    <store>
+      @type relabel
+      @label @PIPELINE1
+    </store>
+    
+    <store>
+      @type relabel
+      @label @PIPELINE2
+    </store>
+  </match>
+  
+  # Sending openshiftAPI logs to pipelines
+  <match openshift-audit.log**>
+    @type relabel
+    @label @PIPELINE2
+  </match>
+  
+  # Sending ovn logs to pipelines
+  <match ovn-audit.log**>
+    @type relabel
+    @label @PIPELINE2
+  </match>
+</label>
+`,
 		}),
 		Entry("Legacy Forwarding", generator.ConfGenerateTest{
 			CLFSpec: logging.ClusterLogForwarderSpec{},
@@ -0,0 +1,18 @@
+package helpers
+
+import "strings"
+
+// mapFieldPrefixes are the paths of log record fields with keys which may contain dots
+var mapFieldPrefixes = []string{"kubernetes.labels.", "kubernetes.namespace_labels."}
+
+// FieldPath splits the dot delimited path of a log record field into its keys. The key of a label
+// is kept whole, e.g. kubernetes.labels.app.kubernetes.io/name is ["kubernetes", "labels", "app.kubernetes.io/name"]
+func FieldPath(field string) []string {
+	field = strings.TrimPrefix(field, ".")
+	for _, prefix := range mapFieldPrefixes {
+		if strings.HasPrefix(field, prefix) && len(field) > len(prefix) {
+			return append(strings.Split(strings.TrimSuffix(prefix, "."), "."), strings.TrimPrefix(field, prefix))
+		}
+	}
+	return strings.Split(field, ".")
+}
@@ -0,0 +1,145 @@
+package vector
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Vector Config Generation", func() {
+	var f = func(clspec logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+		return generator.MergeSections(Conf(&clspec, secrets, &clfspec, op))
+	}
+	DescribeTable("Generate full vector.toml", generator.TestGenerateConfWith(f),
+		Entry("with application logs to the default log store", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Name: logging.OutputNameDefault,
+						Type: logging.OutputTypeElasticsearch,
+						URL:  constants.LogStoreURL,
+						Secret: &logging.OutputSecretSpec{
+							Name: constants.CollectorSecretName,
+						},
+					},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{logging.InputNameApplication},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline",
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				logging.OutputNameDefault: {
+					Data: map[string][]byte{
+						"tls.key":       []byte("junk"),
+						"tls.crt":       []byte("junk"),
+						"ca-bundle.crt": []byte("junk"),
+					},
+				},
+			},
+			ExpectedConf: `
+# Logs from containers (including openshift containers)
+[sources.container_logs]
+  type = "kubernetes_logs"
+  auto_partial_merge = true
+  exclude_paths_glob_patterns = ["/var/log/containers/vector-*_openshift-logging_*.log", "/var/log/containers/elasticsearch-*_openshift-logging_*.log", "/var/log/containers/kibana-*_openshift-logging_*.log"]
+
+# Normalize container logs to the ViaQ data model
+[transforms.normalize_container_logs]
+  type = "remap"
+  inputs = ["container_logs"]
+  source = '''
+  .kubernetes.namespace_name = del(.kubernetes.pod_namespace)
+  .kubernetes.pod_id = del(.kubernetes.pod_uid)
+  .kubernetes.labels = del(.kubernetes.pod_labels)
+  .kubernetes.host = del(.kubernetes.pod_node_name)
+  .hostname = .kubernetes.host
+  ."@timestamp" = del(.timestamp)
+  del(.source_type)
+  if !exists(.level) {
+    .level = "unknown"
+    if match!(.message, r'Warning|WARN|^W[0-9]+|level=warn|Value:warn|"level":"warn"') {
+      .level = "warn"
+    } else if match!(.message, r'Info|INFO|^I[0-9]+|level=info|Value:info|"level":"info"') {
+      .level = "info"
+    } else if match!(.message, r'Error|ERROR|^E[0-9]+|level=error|Value:error|"level":"error"') {
+      .level = "error"
+    } else if match!(.message, r'Debug|DEBUG|^D[0-9]+|level=debug|Value:debug|"level":"debug"') {
+      .level = "debug"
+    }
+  }
+  .pipeline_metadata.collector.name = "vector"
+  .pipeline_metadata.collector.received_at = now()
+'''
+
+# Split container logs into application and infrastructure logs
+[transforms.route_container_logs]
+  type = "route"
+  inputs = ["normalize_container_logs"]
+  route.app = '!(starts_with!(.kubernetes.namespace_name, "kube-") || starts_with!(.kubernetes.namespace_name, "openshift-") || .kubernetes.namespace_name == "default" || .kubernetes.namespace_name == "openshift")'
+  route.infra = 'starts_with!(.kubernetes.namespace_name, "kube-") || starts_with!(.kubernetes.namespace_name, "openshift-") || .kubernetes.namespace_name == "default" || .kubernetes.namespace_name == "openshift"'
+
+# Set log_type to application
+[transforms.application]
+  type = "remap"
+  inputs = ["route_container_logs.app"]
+  source = '''
+  .log_type = "application"
+'''
+
+# Pipeline "pipeline"
+[transforms.pipeline_pipeline]
+  type = "remap"
+  inputs = ["application"]
+  source = '''
+  .
+'''
+
+# Ship logs to specific outputs
+# Set Elasticsearch index for output "default"
+[transforms.output_default_add_es_index]
+  type = "remap"
+  inputs = ["pipeline_pipeline"]
+  source = '''
+  index = "default"
+  if (.log_type == "application") {
+    index = "app"
+  }
+  if (.log_type == "infrastructure") {
+    index = "infra"
+  }
+  if (.log_type == "audit") {
+    index = "audit"
+  }
+  .write_index = index + "-write"
+  .viaq_msg_id = encode_base64(uuid_v4())
+  del(.structured)
+  if exists(.kubernetes.labels) {
+    .kubernetes.flat_labels = split(encode_key_value!(.kubernetes.labels, field_delimiter: ","), ",")
+    del(.kubernetes.labels)
+  }
+'''
+
+[sinks.output_default]
+  type = "elasticsearch"
+  inputs = ["output_default_add_es_index"]
+  endpoint = "https://elasticsearch.openshift-logging.svc:9200"
+  index = "{{ write_index }}"
+  request.timeout_secs = 2147483648
+  bulk_action = "create"
+  id_key = "viaq_msg_id"
+
+[sinks.output_default.tls]
+  key_file = '/var/run/ocp-collector/secrets/collector/tls.key'
+  crt_file = '/var/run/ocp-collector/secrets/collector/tls.crt'
+  ca_file = '/var/run/ocp-collector/secrets/collector/ca-bundle.crt'
+`,
+		}),
+	)
+})
@@ -13,5 +13,17 @@ func Conf(clspec *logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret,
 			Sources(clfspec, op),
 			"Set of all input sources",
 		},
+		{
+			NormalizeLogs(clfspec, op),
+			"Normalize logs to the ViaQ data model and set the log type",
+		},
+		{
+			InputsToPipeline(clfspec, op),
+			"Inputs go to pipelines",
+		},
+		{
+			Outputs(clspec, secrets, clfspec, op),
+			"Outputs",
+		},
 	}
 }
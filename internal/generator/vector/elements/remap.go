@@ -0,0 +1,27 @@
+package elements
+
+// Remap is a vector 'remap' transform, modifying events with a VRL program
+type Remap struct {
+	Desc        string
+	ComponentID string
+	Inputs      string
+	VRL         string
+}
+
+func (r Remap) Name() string {
+	return "remapTemplate"
+}
+
+func (r Remap) Template() string {
+	return `{{define "` + r.Name() + `" -}}
+{{if .Desc -}}
+# {{.Desc}}
+{{end -}}
+[transforms.{{.ComponentID}}]
+  type = "remap"
+  inputs = {{.Inputs}}
+  source = '''
+{{indent 2 .VRL}}
+'''
+{{end}}`
+}
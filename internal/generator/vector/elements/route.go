@@ -0,0 +1,39 @@
+package elements
+
+import "strings"
+
+// Route is a vector 'route' transform, splitting a stream of events into named routes by VRL conditions.
+// Routes are addressed by downstream components as <ComponentID>.<route>
+type Route struct {
+	Desc        string
+	ComponentID string
+	Inputs      string
+	Routes      map[string]string
+}
+
+func (r Route) Name() string {
+	return "routeTemplate"
+}
+
+func (r Route) Template() string {
+	return `{{define "` + r.Name() + `" -}}
+{{if .Desc -}}
+# {{.Desc}}
+{{end -}}
+[transforms.{{.ComponentID}}]
+  type = "route"
+  inputs = {{.Inputs}}
+{{- range $route, $condition := .Routes}}
+  route.{{$route}} = {{$.Literal $condition}}
+{{- end}}
+{{end}}`
+}
+
+// Literal quotes a condition as a TOML literal string, conditions containing single quotes (e.g. VRL regex
+// literals) use a multi-line literal string
+func (r Route) Literal(condition string) string {
+	if strings.Contains(condition, "'") {
+		return "'''" + condition + "'''"
+	}
+	return "'" + condition + "'"
+}
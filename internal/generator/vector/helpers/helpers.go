@@ -0,0 +1,63 @@
+package helpers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	genhelper "github.com/openshift/cluster-logging-operator/internal/generator/helpers"
+)
+
+var (
+	invalidComponentIDChars = regexp.MustCompile(`[^a-z0-9_]`)
+	plainPathSegment        = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+)
+
+// FormatComponentID converts a user supplied name (input, pipeline, output) into a valid vector component ID
+func FormatComponentID(name string) string {
+	return invalidComponentIDChars.ReplaceAllString(strings.ToLower(name), "_")
+}
+
+// MakeInputs formats a list of component IDs as a vector 'inputs' array
+func MakeInputs(in ...string) string {
+	quoted := make([]string, len(in))
+	for i, s := range in {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("[%s]", strings.Join(quoted, ", "))
+}
+
+// VRLPath converts a dot delimited field path (e.g. kubernetes.labels.app) into a VRL path expression,
+// quoting path segments which contain characters not allowed in a bare VRL path segment. The key of a
+// label is kept whole
+func VRLPath(path string) string {
+	segments := genhelper.FieldPath(path)
+	for i, s := range segments {
+		segments[i] = PathSegment(s)
+	}
+	return "." + strings.Join(segments, ".")
+}
+
+// PathSegment returns a single VRL path segment, quoted when it is not a bare word (e.g. a label key like app.kubernetes.io/name)
+func PathSegment(s string) string {
+	if plainPathSegment.MatchString(s) {
+		return s
+	}
+	return fmt.Sprintf("%q", s)
+}
+
+// TemplatePath converts a dot delimited field path into a vector template string (e.g. {{ kubernetes.namespace_name }})
+func TemplatePath(path string) string {
+	return fmt.Sprintf("{{ %s }}", strings.TrimPrefix(path, "."))
+}
+
+// recordFieldRef matches the references to log record fields in an output template, e.g. {.kubernetes.namespace_name}
+var recordFieldRef = regexp.MustCompile(`\{\.([^{}]+)\}`)
+
+// Template converts the log record field references of an output template (e.g. app-{.kubernetes.namespace_name})
+// into a vector template string (e.g. app-{{ kubernetes.namespace_name }})
+func Template(template string) string {
+	return recordFieldRef.ReplaceAllStringFunc(template, func(ref string) string {
+		return TemplatePath(recordFieldRef.FindStringSubmatch(ref)[1])
+	})
+}
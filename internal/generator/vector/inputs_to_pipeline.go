@@ -0,0 +1,301 @@
+package vector
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	. "github.com/openshift/cluster-logging-operator/internal/generator/vector/elements"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/helpers"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	RouteApplicationLogsID    = "route_application_logs"
+	RouteInfrastructureLogsID = "route_infrastructure_logs"
+	RouteAuditLogsID          = "route_audit_logs"
+	pipelinePrefix            = "pipeline_"
+)
+
+// SourceConditions are the VRL conditions matching the logs of each source that can be selected by
+// user defined inputs, by input type
+var SourceConditions = map[string]map[string]string{
+	logging.InputNameInfrastructure: {
+		logging.InfrastructureSourceContainer: "exists(.kubernetes)",
+		logging.InfrastructureSourceNode:      "!exists(.kubernetes)",
+	},
+	logging.InputNameAudit: {
+		logging.AuditSourceAuditd:    `.file == "/var/log/audit/audit.log"`,
+		logging.AuditSourceKube:      `.file == "/var/log/kube-apiserver/audit.log"`,
+		logging.AuditSourceOpenShift: `includes(["/var/log/oauth-apiserver/audit.log", "/var/log/openshift-apiserver/audit.log"], .file)`,
+		logging.AuditSourceOVN:       `.file == "/var/log/ovn/acl-audit-log.log"`,
+	},
+}
+
+// sourceTypeRoute is the ID of the transform emitting all logs of a source type and of the route splitting
+// them by source
+type sourceTypeRoute struct {
+	InputID string
+	RouteID string
+}
+
+var sourceTypeRoutes = map[string]sourceTypeRoute{
+	logging.InputNameInfrastructure: {InputID: InfrastructureID, RouteID: RouteInfrastructureLogsID},
+	logging.InputNameAudit:          {InputID: AuditID, RouteID: RouteAuditLogsID},
+}
+
+const ParseJSONVRL = `
+parsed, err = parse_json(.message)
+if err == null {
+  .structured = parsed
+}
+`
+
+// PipelineID returns the component ID of the transform for a pipeline
+func PipelineID(name string) string {
+	return pipelinePrefix + helpers.FormatComponentID(name)
+}
+
+func InputsToPipeline(spec *logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+	return generator.MergeElements(
+		RouteApplicationLogs(spec, op),
+		RouteSourceLogs(logging.InputNameInfrastructure, spec, op),
+		RouteSourceLogs(logging.InputNameAudit, spec, op),
+		Pipelines(spec, op),
+	)
+}
+
+// RouteApplicationLogs splits application logs into a route for every user defined input selecting
+// application logs by namespace or labels
+func RouteApplicationLogs(spec *logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+	routes := map[string]string{}
+	userDefined := spec.InputMap()
+	for _, pipeline := range spec.Pipelines {
+		for _, inRef := range pipeline.InputRefs {
+			if input, ok := userDefined[inRef]; ok && isRoutedApplication(input) {
+				routes[helpers.FormatComponentID(input.Name)] = ApplicationCondition(input.Application)
+			}
+		}
+	}
+	if len(routes) == 0 {
+		return []generator.Element{}
+	}
+	return []generator.Element{
+		Route{
+			Desc:        "Route application logs to user defined inputs",
+			ComponentID: RouteApplicationLogsID,
+			Inputs:      helpers.MakeInputs(ApplicationID),
+			Routes:      routes,
+		},
+	}
+}
+
+// RouteSourceLogs splits the logs of a source type into a route for every source, if a user defined input
+// selects some of the sources of the source type
+func RouteSourceLogs(sourceType string, spec *logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+	userDefined := spec.InputMap()
+	for _, pipeline := range spec.Pipelines {
+		for _, inRef := range pipeline.InputRefs {
+			if input, ok := userDefined[inRef]; ok && len(selectedSources(sourceType, input)) != 0 {
+				routes := map[string]string{}
+				for src, condition := range SourceConditions[sourceType] {
+					routes[helpers.FormatComponentID(src)] = condition
+				}
+				return []generator.Element{
+					Route{
+						Desc:        fmt.Sprintf("Route %s logs by source", sourceType),
+						ComponentID: sourceTypeRoutes[sourceType].RouteID,
+						Inputs:      helpers.MakeInputs(sourceTypeRoutes[sourceType].InputID),
+						Routes:      routes,
+					},
+				}
+			}
+		}
+	}
+	return []generator.Element{}
+}
+
+// selectedSources returns the sources of the source type selected by a user defined input, or nil if
+// the input selects all or none of them
+func selectedSources(sourceType string, input *logging.InputSpec) []string {
+	var sources []string
+	switch {
+	case sourceType == logging.InputNameInfrastructure && input.Infrastructure != nil:
+		sources = input.Infrastructure.Sources
+	case sourceType == logging.InputNameAudit && input.Audit != nil:
+		sources = input.Audit.Sources
+	}
+	if sets.NewString(sources...).Len() == len(SourceConditions[sourceType]) {
+		return nil
+	}
+	return sources
+}
+
+// sourceTypeInputs returns the IDs of the components emitting the logs of the source type selected by
+// a user defined input
+func sourceTypeInputs(sourceType string, input *logging.InputSpec) []string {
+	sources := selectedSources(sourceType, input)
+	if len(sources) == 0 {
+		return []string{sourceTypeRoutes[sourceType].InputID}
+	}
+	ids := make([]string, len(sources))
+	for i, src := range sources {
+		ids[i] = sourceTypeRoutes[sourceType].RouteID + "." + helpers.FormatComponentID(src)
+	}
+	return ids
+}
+
+// ApplicationCondition is the VRL condition matching the namespaces and labels of an application input.
+// A record matches if it is from any of the namespaces and has all the labels
+func ApplicationCondition(app *logging.Application) string {
+	conditions := []string{}
+	if len(app.Namespaces) != 0 {
+		conditions = append(conditions, NamespaceCondition(app.Namespaces))
+	}
+	if len(app.ExcludeNamespaces) != 0 {
+		conditions = append(conditions, "!"+NamespaceCondition(app.ExcludeNamespaces))
+	}
+	if app.Selector != nil {
+		keys := make([]string, 0, len(app.Selector.MatchLabels))
+		for k := range app.Selector.MatchLabels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			conditions = append(conditions, fmt.Sprintf("%s == %q", ".kubernetes.labels."+helpers.PathSegment(k), app.Selector.MatchLabels[k]))
+		}
+		for _, req := range app.Selector.MatchExpressions {
+			label := ".kubernetes.labels." + helpers.PathSegment(req.Key)
+			values := make([]string, len(req.Values))
+			for i, v := range req.Values {
+				values[i] = fmt.Sprintf("%q", v)
+			}
+			switch req.Operator {
+			case metav1.LabelSelectorOpIn:
+				conditions = append(conditions, fmt.Sprintf("includes([%s], %s)", strings.Join(values, ", "), label))
+			case metav1.LabelSelectorOpNotIn:
+				conditions = append(conditions, fmt.Sprintf("!includes([%s], %s)", strings.Join(values, ", "), label))
+			case metav1.LabelSelectorOpExists:
+				conditions = append(conditions, fmt.Sprintf("exists(%s)", label))
+			case metav1.LabelSelectorOpDoesNotExist:
+				conditions = append(conditions, fmt.Sprintf("!exists(%s)", label))
+			}
+		}
+	}
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	for i, c := range conditions {
+		conditions[i] = "(" + c + ")"
+	}
+	return strings.Join(conditions, " && ")
+}
+
+// NamespaceCondition is the VRL condition matching records from any of the namespaces.
+// Namespaces containing '*' are glob patterns matched by a regex
+func NamespaceCondition(namespaces []string) string {
+	patterns := false
+	quoted := make([]string, len(namespaces))
+	for i, ns := range namespaces {
+		patterns = patterns || strings.Contains(ns, "*")
+		quoted[i] = fmt.Sprintf("%q", ns)
+	}
+	if !patterns {
+		return fmt.Sprintf("includes([%s], .kubernetes.namespace_name)", strings.Join(quoted, ", "))
+	}
+	regexes := make([]string, len(namespaces))
+	for i, ns := range namespaces {
+		regexes[i] = strings.ReplaceAll(regexp.QuoteMeta(ns), `\*`, ".*")
+	}
+	return fmt.Sprintf("match(string!(.kubernetes.namespace_name), r'^(%s)$')", strings.Join(regexes, "|"))
+}
+
+func isRoutedApplication(input *logging.InputSpec) bool {
+	app := input.Application
+	return app != nil && (len(app.Namespaces) != 0 || len(app.ExcludeNamespaces) != 0 || (app.Selector != nil && (len(app.Selector.MatchLabels) != 0 || len(app.Selector.MatchExpressions) != 0)))
+}
+
+// PipelineInputs returns the IDs of the components emitting the logs selected by the inputRefs of a pipeline
+func PipelineInputs(spec *logging.ClusterLogForwarderSpec, p logging.PipelineSpec) []string {
+	inputs := sets.NewString()
+	userDefined := spec.InputMap()
+	for _, inRef := range p.InputRefs {
+		if input, ok := userDefined[inRef]; ok {
+			if isRoutedApplication(input) {
+				inputs.Insert(RouteApplicationLogsID + "." + helpers.FormatComponentID(input.Name))
+			} else if input.Application != nil {
+				inputs.Insert(ApplicationID)
+			}
+			if input.Infrastructure != nil {
+				inputs.Insert(sourceTypeInputs(logging.InputNameInfrastructure, input)...)
+			}
+			if input.Audit != nil {
+				inputs.Insert(sourceTypeInputs(logging.InputNameAudit, input)...)
+			}
+			continue
+		}
+		switch inRef {
+		case logging.InputNameApplication:
+			inputs.Insert(ApplicationID)
+		case logging.InputNameInfrastructure:
+			inputs.Insert(InfrastructureID)
+		case logging.InputNameAudit:
+			inputs.Insert(AuditID)
+		}
+	}
+	return inputs.List()
+}
+
+// FieldsVRL returns the VRL statements keeping, then pruning, then renaming the pipeline fields
+func FieldsVRL(fields *logging.FieldsSpec) []string {
+	vrl := []string{}
+	if fields == nil {
+		return vrl
+	}
+	if len(fields.Keep) != 0 {
+		vrl = append(vrl, "kept = .", ". = {}")
+		for _, f := range fields.Keep {
+			path := helpers.VRLPath(f)
+			vrl = append(vrl, fmt.Sprintf("if !is_null(kept%s) { %s = kept%s }", path, path, path))
+		}
+	}
+	for _, f := range fields.Prune {
+		vrl = append(vrl, fmt.Sprintf("del(%s)", helpers.VRLPath(f)))
+	}
+	for _, r := range fields.Rename {
+		vrl = append(vrl, fmt.Sprintf("%s = del(%s)", helpers.VRLPath(r.To), helpers.VRLPath(r.From)))
+	}
+	return vrl
+}
+
+// Pipelines generates a transform for every pipeline, joining the pipeline inputs and applying the pipeline
+// labels, JSON parsing and field changes
+func Pipelines(spec *logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+	el := []generator.Element{}
+	for _, p := range spec.Pipelines {
+		vrl := []string{}
+		if len(p.Labels) != 0 {
+			labels, _ := json.Marshal(p.Labels)
+			vrl = append(vrl, fmt.Sprintf(".openshift.labels = %s", labels))
+		}
+		if p.Parse == "json" {
+			vrl = append(vrl, strings.TrimSpace(ParseJSONVRL))
+		}
+		vrl = append(vrl, FieldsVRL(p.Fields)...)
+		if len(vrl) == 0 {
+			vrl = append(vrl, ".")
+		}
+		el = append(el, Remap{
+			Desc:        fmt.Sprintf("Pipeline %q", p.Name),
+			ComponentID: PipelineID(p.Name),
+			Inputs:      helpers.MakeInputs(PipelineInputs(spec, p)...),
+			VRL:         strings.Join(vrl, "\n"),
+		})
+	}
+	return el
+}
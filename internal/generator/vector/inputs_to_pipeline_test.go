@@ -0,0 +1,293 @@
+package vector
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Vector Config Generation", func() {
+	var f = func(clspec logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+		return InputsToPipeline(&clfspec, op)
+	}
+	DescribeTable("Inputs to pipelines", generator.TestGenerateConfWith(f),
+		Entry("with reserved inputs", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs: []string{
+							logging.InputNameApplication,
+							logging.InputNameInfrastructure,
+						},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline",
+					},
+					{
+						InputRefs:  []string{logging.InputNameAudit},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "audit-pipeline",
+					},
+				},
+			},
+			ExpectedConf: `
+# Pipeline "pipeline"
+[transforms.pipeline_pipeline]
+  type = "remap"
+  inputs = ["application", "infrastructure"]
+  source = '''
+  .
+'''
+
+# Pipeline "audit-pipeline"
+[transforms.pipeline_audit_pipeline]
+  type = "remap"
+  inputs = ["audit"]
+  source = '''
+  .
+'''
+`,
+		}),
+		Entry("with application inputs selecting namespaces and labels", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Inputs: []logging.InputSpec{
+					{
+						Name: "myapp",
+						Application: &logging.Application{
+							Namespaces: []string{"project1", "project2"},
+						},
+					},
+					{
+						Name: "my-labelled-app",
+						Application: &logging.Application{
+							Namespaces: []string{"project3"},
+							Selector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									"app.kubernetes.io/name": "frontend",
+									"environment":            "production",
+								},
+							},
+						},
+					},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{"myapp", logging.InputNameInfrastructure},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline1",
+						Labels: map[string]string{
+							"key1": "value1",
+						},
+					},
+					{
+						InputRefs:  []string{"my-labelled-app"},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline2",
+						Parse:      "json",
+					},
+				},
+			},
+			ExpectedConf: `
+# Route application logs to user defined inputs
+[transforms.route_application_logs]
+  type = "route"
+  inputs = ["application"]
+  route.my_labelled_app = '(includes(["project3"], .kubernetes.namespace_name)) && (.kubernetes.labels."app.kubernetes.io/name" == "frontend") && (.kubernetes.labels.environment == "production")'
+  route.myapp = 'includes(["project1", "project2"], .kubernetes.namespace_name)'
+
+# Pipeline "pipeline1"
+[transforms.pipeline_pipeline1]
+  type = "remap"
+  inputs = ["infrastructure", "route_application_logs.myapp"]
+  source = '''
+  .openshift.labels = {"key1":"value1"}
+'''
+
+# Pipeline "pipeline2"
+[transforms.pipeline_pipeline2]
+  type = "remap"
+  inputs = ["route_application_logs.my_labelled_app"]
+  source = '''
+  parsed, err = parse_json(.message)
+  if err == null {
+    .structured = parsed
+  }
+'''
+`,
+		}),
+		Entry("with application inputs selecting label expressions", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Inputs: []logging.InputSpec{
+					{
+						Name: "myapp",
+						Application: &logging.Application{
+							Selector: &metav1.LabelSelector{
+								MatchExpressions: []metav1.LabelSelectorRequirement{
+									{Key: "app.kubernetes.io/part-of", Operator: metav1.LabelSelectorOpIn, Values: []string{"x", "y"}},
+									{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"test"}},
+									{Key: "team", Operator: metav1.LabelSelectorOpExists},
+									{Key: "canary", Operator: metav1.LabelSelectorOpDoesNotExist},
+								},
+							},
+						},
+					},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{"myapp"},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline",
+					},
+				},
+			},
+			ExpectedConf: `
+# Route application logs to user defined inputs
+[transforms.route_application_logs]
+  type = "route"
+  inputs = ["application"]
+  route.myapp = '(includes(["x", "y"], .kubernetes.labels."app.kubernetes.io/part-of")) && (!includes(["test"], .kubernetes.labels.tier)) && (exists(.kubernetes.labels.team)) && (!exists(.kubernetes.labels.canary))'
+
+# Pipeline "pipeline"
+[transforms.pipeline_pipeline]
+  type = "remap"
+  inputs = ["route_application_logs.myapp"]
+  source = '''
+  .
+'''
+`,
+		}),
+		Entry("with application inputs selecting namespace patterns", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Inputs: []logging.InputSpec{
+					{
+						Name: "team-a",
+						Application: &logging.Application{
+							Namespaces:        []string{"team-a-*", "shared"},
+							ExcludeNamespaces: []string{"noisy"},
+						},
+					},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{"team-a"},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline",
+					},
+				},
+			},
+			ExpectedConf: `
+# Route application logs to user defined inputs
+[transforms.route_application_logs]
+  type = "route"
+  inputs = ["application"]
+  route.team_a = '''(match(string!(.kubernetes.namespace_name), r'^(team-a-.*|shared)$')) && (!includes(["noisy"], .kubernetes.namespace_name))'''
+
+# Pipeline "pipeline"
+[transforms.pipeline_pipeline]
+  type = "remap"
+  inputs = ["route_application_logs.team_a"]
+  source = '''
+  .
+'''
+`,
+		}),
+		Entry("with infrastructure and audit inputs selecting sources", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Inputs: []logging.InputSpec{
+					{
+						Name:           "node-logs",
+						Infrastructure: &logging.Infrastructure{Sources: []string{logging.InfrastructureSourceNode}},
+					},
+					{
+						Name:  "api-audit",
+						Audit: &logging.Audit{Sources: []string{logging.AuditSourceKube, logging.AuditSourceOpenShift}},
+					},
+					{
+						Name:  "all-audit",
+						Audit: &logging.Audit{},
+					},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{"node-logs", "api-audit"},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline1",
+					},
+					{
+						InputRefs:  []string{"all-audit"},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline2",
+					},
+				},
+			},
+			ExpectedConf: `
+# Route infrastructure logs by source
+[transforms.route_infrastructure_logs]
+  type = "route"
+  inputs = ["infrastructure"]
+  route.container = 'exists(.kubernetes)'
+  route.node = '!exists(.kubernetes)'
+
+# Route audit logs by source
+[transforms.route_audit_logs]
+  type = "route"
+  inputs = ["audit"]
+  route.auditd = '.file == "/var/log/audit/audit.log"'
+  route.kubeapi = '.file == "/var/log/kube-apiserver/audit.log"'
+  route.openshiftapi = 'includes(["/var/log/oauth-apiserver/audit.log", "/var/log/openshift-apiserver/audit.log"], .file)'
+  route.ovn = '.file == "/var/log/ovn/acl-audit-log.log"'
+
+# Pipeline "pipeline1"
+[transforms.pipeline_pipeline1]
+  type = "remap"
+  inputs = ["route_audit_logs.kubeapi", "route_audit_logs.openshiftapi", "route_infrastructure_logs.node"]
+  source = '''
+  .
+'''
+
+# Pipeline "pipeline2"
+[transforms.pipeline_pipeline2]
+  type = "remap"
+  inputs = ["audit"]
+  source = '''
+  .
+'''
+`,
+		}),
+		Entry("with pipeline fields", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Pipelines: []logging.PipelineSpec{
+					{
+						InputRefs:  []string{logging.InputNameApplication},
+						OutputRefs: []string{logging.OutputNameDefault},
+						Name:       "pipeline",
+						Fields: &logging.FieldsSpec{
+							Keep:  []string{"message", "kubernetes.namespace_name", "kubernetes.labels"},
+							Prune: []string{"kubernetes.labels.pod-template-hash"},
+							Rename: []logging.RenameField{
+								{From: "kubernetes.labels", To: "labels"},
+							},
+						},
+					},
+				},
+			},
+			ExpectedConf: `
+# Pipeline "pipeline"
+[transforms.pipeline_pipeline]
+  type = "remap"
+  inputs = ["application"]
+  source = '''
+  kept = .
+  . = {}
+  if !is_null(kept.message) { .message = kept.message }
+  if !is_null(kept.kubernetes.namespace_name) { .kubernetes.namespace_name = kept.kubernetes.namespace_name }
+  if !is_null(kept.kubernetes.labels) { .kubernetes.labels = kept.kubernetes.labels }
+  del(.kubernetes.labels."pod-template-hash")
+  .labels = del(.kubernetes.labels)
+'''
+`,
+		}),
+	)
+})
@@ -0,0 +1,164 @@
+package vector
+
+import (
+	"strings"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	. "github.com/openshift/cluster-logging-operator/internal/generator/vector/elements"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/helpers"
+)
+
+const (
+	NormalizeContainerLogsID = "normalize_container_logs"
+	NormalizeJournalLogsID   = "normalize_journal_logs"
+	NormalizeAuditLogsID     = "normalize_audit_logs"
+	RouteContainerLogsID     = "route_container_logs"
+
+	// ApplicationID, InfrastructureID and AuditID are the transforms emitting all logs of the reserved input types
+	ApplicationID    = "application"
+	InfrastructureID = "infrastructure"
+	AuditID          = "audit"
+
+	routeApplication    = "app"
+	routeInfrastructure = "infra"
+
+	// InfraNamespacesCondition matches container logs from the namespaces considered infrastructure
+	InfraNamespacesCondition = `starts_with!(.kubernetes.namespace_name, "kube-") || starts_with!(.kubernetes.namespace_name, "openshift-") || .kubernetes.namespace_name == "default" || .kubernetes.namespace_name == "openshift"`
+)
+
+const NormalizeContainerLogsVRL = `
+.kubernetes.namespace_name = del(.kubernetes.pod_namespace)
+.kubernetes.pod_id = del(.kubernetes.pod_uid)
+.kubernetes.labels = del(.kubernetes.pod_labels)
+.kubernetes.host = del(.kubernetes.pod_node_name)
+.hostname = .kubernetes.host
+."@timestamp" = del(.timestamp)
+del(.source_type)
+if !exists(.level) {
+  .level = "unknown"
+  if match!(.message, r'Warning|WARN|^W[0-9]+|level=warn|Value:warn|"level":"warn"') {
+    .level = "warn"
+  } else if match!(.message, r'Info|INFO|^I[0-9]+|level=info|Value:info|"level":"info"') {
+    .level = "info"
+  } else if match!(.message, r'Error|ERROR|^E[0-9]+|level=error|Value:error|"level":"error"') {
+    .level = "error"
+  } else if match!(.message, r'Debug|DEBUG|^D[0-9]+|level=debug|Value:debug|"level":"debug"') {
+    .level = "debug"
+  }
+}
+.pipeline_metadata.collector.name = "vector"
+.pipeline_metadata.collector.received_at = now()
+`
+
+const NormalizeJournalLogsVRL = `
+if .PRIORITY == "7" {
+  abort
+}
+.hostname = del(.host)
+."@timestamp" = del(.timestamp)
+del(.source_type)
+.level = "unknown"
+if .PRIORITY == "0" {
+  .level = "emerg"
+} else if .PRIORITY == "1" {
+  .level = "alert"
+} else if .PRIORITY == "2" {
+  .level = "crit"
+} else if .PRIORITY == "3" {
+  .level = "err"
+} else if .PRIORITY == "4" {
+  .level = "warning"
+} else if .PRIORITY == "5" {
+  .level = "notice"
+} else if .PRIORITY == "6" {
+  .level = "info"
+}
+.pipeline_metadata.collector.name = "vector"
+.pipeline_metadata.collector.received_at = now()
+`
+
+const NormalizeAuditLogsVRL = `
+.hostname = get_env_var("VECTOR_SELF_NODE_NAME") ?? ""
+."@timestamp" = del(.timestamp)
+del(.source_type)
+if contains(string!(.file), "apiserver") {
+  parsed, err = parse_json(.message)
+  if err == null {
+    . = merge(., object!(parsed))
+  }
+}
+if .file == "/var/log/ovn/acl-audit-log.log" {
+  parts = split(string!(.message), "|")
+  .level = downcase(to_string(parts[3]) ?? "unknown")
+}
+.pipeline_metadata.collector.name = "vector"
+.pipeline_metadata.collector.received_at = now()
+`
+
+// NormalizeLogs converts the records read by each source to the ViaQ data model, and splits container logs
+// into application and infrastructure logs
+func NormalizeLogs(spec *logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+	el := []generator.Element{}
+	types := generator.GatherSources(spec, op)
+	if types.Has(logging.InputNameApplication) || types.Has(logging.InputNameInfrastructure) {
+		el = append(el,
+			Remap{
+				Desc:        "Normalize container logs to the ViaQ data model",
+				ComponentID: NormalizeContainerLogsID,
+				Inputs:      helpers.MakeInputs(ContainerLogsID),
+				VRL:         strings.TrimSpace(NormalizeContainerLogsVRL),
+			},
+			Route{
+				Desc:        "Split container logs into application and infrastructure logs",
+				ComponentID: RouteContainerLogsID,
+				Inputs:      helpers.MakeInputs(NormalizeContainerLogsID),
+				Routes: map[string]string{
+					routeApplication:    "!(" + InfraNamespacesCondition + ")",
+					routeInfrastructure: InfraNamespacesCondition,
+				},
+			},
+		)
+	}
+	if types.Has(logging.InputNameInfrastructure) {
+		el = append(el, Remap{
+			Desc:        "Normalize journal logs to the ViaQ data model, dropping debug messages",
+			ComponentID: NormalizeJournalLogsID,
+			Inputs:      helpers.MakeInputs(JournalLogsID),
+			VRL:         strings.TrimSpace(NormalizeJournalLogsVRL),
+		})
+	}
+	if types.Has(logging.InputNameAudit) {
+		el = append(el, Remap{
+			Desc:        "Normalize audit logs to the ViaQ data model",
+			ComponentID: NormalizeAuditLogsID,
+			Inputs:      helpers.MakeInputs(HostAuditLogsID, K8sAuditLogsID, OpenshiftAuditLogsID, OVNAuditLogsID),
+			VRL:         strings.TrimSpace(NormalizeAuditLogsVRL),
+		})
+	}
+	if types.Has(logging.InputNameApplication) {
+		el = append(el, Remap{
+			Desc:        "Set log_type to application",
+			ComponentID: ApplicationID,
+			Inputs:      helpers.MakeInputs(RouteContainerLogsID + "." + routeApplication),
+			VRL:         `.log_type = "application"`,
+		})
+	}
+	if types.Has(logging.InputNameInfrastructure) {
+		el = append(el, Remap{
+			Desc:        "Set log_type to infrastructure",
+			ComponentID: InfrastructureID,
+			Inputs:      helpers.MakeInputs(RouteContainerLogsID+"."+routeInfrastructure, NormalizeJournalLogsID),
+			VRL:         `.log_type = "infrastructure"`,
+		})
+	}
+	if types.Has(logging.InputNameAudit) {
+		el = append(el, Remap{
+			Desc:        "Set log_type to audit",
+			ComponentID: AuditID,
+			Inputs:      helpers.MakeInputs(NormalizeAuditLogsID),
+			VRL:         `.log_type = "audit"`,
+		})
+	}
+	return el
+}
@@ -0,0 +1,140 @@
+package cloudwatch
+
+import (
+	"fmt"
+	"strings"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	. "github.com/openshift/cluster-logging-operator/internal/generator"
+	fluentdcloudwatch "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/cloudwatch"
+	. "github.com/openshift/cluster-logging-operator/internal/generator/vector/elements"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type CloudWatch struct {
+	ComponentID        string
+	Inputs             string
+	Region             string
+	Endpoint           string
+	CreateMissingGroup bool
+	AccessKeyID        string
+	SecretAccessKey    string
+}
+
+func (cw CloudWatch) Name() string {
+	return "cloudwatchTemplate"
+}
+
+func (cw CloudWatch) Template() string {
+	return `{{define "` + cw.Name() + `" -}}
+[sinks.{{.ComponentID}}]
+  type = "aws_cloudwatch_logs"
+  inputs = {{.Inputs}}
+  region = {{.Region}}
+{{- if .Endpoint}}
+  endpoint = {{.Endpoint}}
+{{- end}}
+  group_name = "{{"{{ group_name }}"}}"
+  stream_name = "{{"{{ stream_name }}"}}"
+  create_missing_group = {{.CreateMissingGroup}}
+  encoding.codec = "json"
+  encoding.except_fields = ["group_name", "stream_name"]
+  auth.access_key_id = {{.AccessKeyID}}
+  auth.secret_access_key = {{.SecretAccessKey}}
+{{end}}`
+}
+
+// GroupNameStreamNameVRL sets the group and stream of each record. Application logs are streamed by tag unless
+// configured otherwise, infrastructure and audit logs by host and tag
+const GroupNameStreamNameVRL = `
+tag = "journal.system"
+if exists(.file) {
+  tag = replace!(.file, "/", ".")
+  if exists(.kubernetes) {
+    tag = "kubernetes" + tag
+  }
+}
+if (.log_type == "application") {
+  .group_name = %s
+  .stream_name = %s
+} else if (.log_type == "audit") {
+  .group_name = %q
+  .stream_name = join!([.hostname, tag], ".")
+} else {
+  .group_name = %q
+  .stream_name = join!([.hostname, tag], ".")
+}
+`
+
+func Conf(o logging.OutputSpec, inputs []string, secret *corev1.Secret, op Options) []Element {
+	id := output.SinkID(o.Name)
+	groupID := id + "_group_and_stream"
+	prefix := fluentdcloudwatch.LogGroupPrefix(o)
+	accessKeyID, secretAccessKey := `""`, `""`
+	if o.Secret != nil {
+		accessKeyID = output.SecretString(o, constants.AWSAccessKeyID)
+		secretAccessKey = output.SecretString(o, constants.AWSSecretAccessKey)
+	}
+	endpoint := ""
+	if o.URL != "" {
+		endpoint = output.Quote(o.URL)
+	}
+	return []Element{
+		Remap{
+			Desc:        fmt.Sprintf("Set Cloudwatch group and stream names for output %q", o.Name),
+			ComponentID: groupID,
+			Inputs:      helpers.MakeInputs(inputs...),
+			VRL:         strings.TrimSpace(fmt.Sprintf(GroupNameStreamNameVRL, LogGroupName(o), LogStreamName(o), prefix+"audit", prefix+"infrastructure")),
+		},
+		CloudWatch{
+			ComponentID:        id,
+			Inputs:             helpers.MakeInputs(groupID),
+			Region:             output.Quote(o.Cloudwatch.Region),
+			Endpoint:           endpoint,
+			CreateMissingGroup: o.Cloudwatch.CreateLogGroup != nil && *o.Cloudwatch.CreateLogGroup,
+			AccessKeyID:        accessKeyID,
+			SecretAccessKey:    secretAccessKey,
+		},
+	}
+}
+
+// LogGroupName is the VRL expression for the group name of application logs
+func LogGroupName(o logging.OutputSpec) string {
+	prefix := fluentdcloudwatch.LogGroupPrefix(o)
+	field := ""
+	switch o.Cloudwatch.GroupBy {
+	case logging.LogGroupByNamespaceName:
+		field = ".kubernetes.namespace_name"
+	case logging.LogGroupByNamespaceUUID:
+		field = ".kubernetes.namespace_id"
+	case logging.LogGroupByKey:
+		segments := []string{}
+		for _, k := range fluentdcloudwatch.GroupByKeyPath(o.Cloudwatch.GroupByKey) {
+			segments = append(segments, helpers.PathSegment(k))
+		}
+		field = "." + strings.Join(segments, ".")
+	default:
+		return fmt.Sprintf("%q", prefix+logging.InputNameApplication)
+	}
+	// fall back to the application group when the record has no such field
+	if prefix == "" {
+		return fmt.Sprintf("string(%s) ?? %q", field, logging.InputNameApplication)
+	}
+	return fmt.Sprintf("(%q + string(%s)) ?? %q", prefix, field, prefix+logging.InputNameApplication)
+}
+
+// LogStreamName is the VRL expression for the stream name of application logs, falling back to the tag
+// when the record has no such fields
+func LogStreamName(o logging.OutputSpec) string {
+	switch o.Cloudwatch.StreamName {
+	case logging.LogStreamNameByPodName:
+		return `join([.kubernetes.namespace_name, .kubernetes.pod_name], "_") ?? tag`
+	case logging.LogStreamNameByContainerName:
+		return `join([.kubernetes.namespace_name, .kubernetes.pod_name, .kubernetes.container_name], "_") ?? tag`
+	default:
+		return "tag"
+	}
+}
@@ -0,0 +1,199 @@
+package elasticsearch
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	. "github.com/openshift/cluster-logging-operator/internal/generator"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/security"
+	"github.com/openshift/cluster-logging-operator/internal/generator/url"
+	. "github.com/openshift/cluster-logging-operator/internal/generator/vector/elements"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	defaultElasticsearchPort = "9200"
+)
+
+type Elasticsearch struct {
+	ComponentID string
+	Inputs      string
+	Endpoint    string
+	DataStream  bool
+	APIKey      string
+}
+
+func (e Elasticsearch) Name() string {
+	return "elasticsearchTemplate"
+}
+
+func (e Elasticsearch) Template() string {
+	return `{{define "` + e.Name() + `" -}}
+[sinks.{{.ComponentID}}]
+  type = "elasticsearch"
+  inputs = {{.Inputs}}
+  endpoint = {{.Endpoint}}
+{{- if .DataStream}}
+  mode = "data_stream"
+  data_stream.auto_routing = true
+  data_stream.sync_fields = true
+{{- else}}
+  index = "{{"{{ write_index }}"}}"
+{{- end}}
+  request.timeout_secs = 2147483648
+{{- if not .DataStream}}
+  bulk_action = "create"
+{{- end}}
+  id_key = "viaq_msg_id"
+{{- if .DataStream}}
+  suppress_type_name = true
+{{- end}}
+{{- if .APIKey}}
+  request.headers.Authorization = {{.APIKey}}
+{{- end}}
+{{end}}`
+}
+
+// AddESIndexVRL computes the index each record is written to from its log type
+const AddESIndexVRL = `
+index = "default"
+if (.log_type == "application") {
+  index = "app"
+}
+if (.log_type == "infrastructure") {
+  index = "infra"
+}
+if (.log_type == "audit") {
+  index = "audit"
+}
+.write_index = index + "-write"
+.viaq_msg_id = encode_base64(uuid_v4())
+`
+
+// DataStreamVRL routes each record to the data stream named by its index, the name of a data stream
+// matching the built-in index templates is <type>-<dataset>-<namespace>
+const DataStreamVRL = `
+parts = split(string!(.write_index), "-", limit: 3)
+.data_stream.type = parts[0]
+.data_stream.dataset = parts[1]
+.data_stream.namespace = parts[2]
+del(.write_index)
+`
+
+// FlattenLabelsVRL flattens the kubernetes labels to prevent field explosion in ES
+const FlattenLabelsVRL = `
+if exists(.kubernetes.labels) {
+  .kubernetes.flat_labels = split(encode_key_value!(.kubernetes.labels, field_delimiter: ","), ",")
+  del(.kubernetes.labels)
+}
+`
+
+func Conf(o logging.OutputSpec, inputs []string, secret *corev1.Secret, op Options) []Element {
+	id := output.SinkID(o.Name)
+	indexID := id + "_add_es_index"
+	return []Element{
+		Remap{
+			Desc:        fmt.Sprintf("Set Elasticsearch index for output %q", o.Name),
+			ComponentID: indexID,
+			Inputs:      helpers.MakeInputs(inputs...),
+			VRL:         strings.Join(IndexVRLs(o), "\n"),
+		},
+		Output(o, indexID, secret),
+		output.TLS(id, o, secret, false),
+		output.Auth(id, o, secret),
+	}
+}
+
+// IndexVRLs returns the VRL statements setting the index, or the data stream, each record is written to
+func IndexVRLs(o logging.OutputSpec) []string {
+	vrl := []string{strings.TrimSpace(AddESIndexVRL), ChangeESIndex(o)}
+	if o.Elasticsearch != nil && o.Elasticsearch.DataStream {
+		vrl = append(vrl, strings.TrimSpace(DataStreamVRL))
+	}
+	return append(vrl, strings.TrimSpace(FlattenLabelsVRL))
+}
+
+func Output(o logging.OutputSpec, input string, secret *corev1.Secret) Elasticsearch {
+	// URL is parasable, checked at input sanitization
+	u, _ := url.Parse(o.URL)
+	port := u.Port()
+	if port == "" {
+		port = defaultElasticsearchPort
+	}
+	es := Elasticsearch{
+		ComponentID: output.SinkID(o.Name),
+		Inputs:      helpers.MakeInputs(input),
+		Endpoint:    output.Quote(fmt.Sprintf("%s://%s:%s", u.Scheme, u.Hostname(), port)),
+		DataStream:  o.Elasticsearch != nil && o.Elasticsearch.DataStream,
+	}
+	if key := security.GetFromSecret(secret, constants.ElasticsearchAPIKey); o.Secret != nil && key != "" {
+		es.APIKey = output.SecretStringWithPrefix(o, constants.ElasticsearchAPIKey, "ApiKey ")
+	}
+	return es
+}
+
+// ChangeESIndex writes logs to the index named by the index template of the output, and structured application
+// logs to an index named after their structured type. The structured field is removed when no structured
+// type is configured
+func ChangeESIndex(o logging.OutputSpec) string {
+	vrl := []string{}
+	if o.Elasticsearch != nil && o.Elasticsearch.Index != "" {
+		vrl = append(vrl, fmt.Sprintf(".write_index = %s", IndexVRL(o.Elasticsearch.Index)))
+	}
+	if o.Elasticsearch == nil || (o.Elasticsearch.StructuredTypeKey == "" && o.Elasticsearch.StructuredTypeName == "") {
+		return strings.Join(append(vrl, "del(.structured)"), "\n")
+	}
+	vrl = append(vrl,
+		"if exists(.structured) && .structured != {} {",
+	)
+	if key := o.Elasticsearch.StructuredTypeKey; key != "" {
+		vrl = append(vrl, fmt.Sprintf("  type_name = %s", helpers.VRLPath(key)))
+	} else {
+		vrl = append(vrl, "  type_name = null")
+	}
+	if name := o.Elasticsearch.StructuredTypeName; name != "" {
+		vrl = append(vrl,
+			"  if type_name == null {",
+			fmt.Sprintf("    type_name = %q", name),
+			"  }",
+		)
+	}
+	vrl = append(vrl,
+		"  if type_name != null {",
+		`    .write_index = "app-" + string!(type_name) + "-write"`,
+		"  }",
+		"}",
+	)
+	return strings.Join(vrl, "\n")
+}
+
+// indexTokenRegex matches the log record field references of an index template, e.g. {.log_type}, and strftime
+// conversion specifications with the separators between them, e.g. %Y.%m.%d
+var indexTokenRegex = regexp.MustCompile(`\{\.([^{}]+)\}|%[a-zA-Z]([-._/:]*%[a-zA-Z])*`)
+
+// IndexVRL converts an index name template to a VRL string expression. Log record fields are referenced
+// as {.field.path}, strftime conversion specifications are formatted from the record timestamp
+func IndexVRL(template string) string {
+	parts := []string{}
+	last := 0
+	for _, m := range indexTokenRegex.FindAllStringSubmatchIndex(template, -1) {
+		if m[0] > last {
+			parts = append(parts, fmt.Sprintf("%q", template[last:m[0]]))
+		}
+		if m[2] >= 0 {
+			parts = append(parts, fmt.Sprintf(`(to_string(%s) ?? "")`, helpers.VRLPath(template[m[2]:m[3]])))
+		} else {
+			parts = append(parts, fmt.Sprintf(`format_timestamp!(to_timestamp(."@timestamp") ?? now(), format: %q)`, template[m[0]:m[1]]))
+		}
+		last = m[1]
+	}
+	if last < len(template) {
+		parts = append(parts, fmt.Sprintf("%q", template[last:]))
+	}
+	return strings.Join(parts, " + ")
+}
@@ -0,0 +1,278 @@
+package elasticsearch
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Generate vector config", func() {
+	inputPipeline := []string{"pipeline_1"}
+	var f = func(clspec logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+		return Conf(clfspec.Outputs[0], inputPipeline, secrets[clfspec.Outputs[0].Name], op)
+	}
+	DescribeTable("For Elasticsearch output", generator.TestGenerateConfWith(f),
+		Entry("with username,password", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeElasticsearch,
+						Name: "es-1",
+						URL:  "https://es.svc.infra.cluster:9999",
+						Secret: &logging.OutputSecretSpec{
+							Name: "es-1",
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"es-1": {
+					Data: map[string][]byte{
+						"username": []byte("testuser"),
+						"password": []byte("testpass"),
+					},
+				},
+			},
+			ExpectedConf: `
+# Set Elasticsearch index for output "es-1"
+[transforms.output_es_1_add_es_index]
+  type = "remap"
+  inputs = ["pipeline_1"]
+  source = '''
+  index = "default"
+  if (.log_type == "application") {
+    index = "app"
+  }
+  if (.log_type == "infrastructure") {
+    index = "infra"
+  }
+  if (.log_type == "audit") {
+    index = "audit"
+  }
+  .write_index = index + "-write"
+  .viaq_msg_id = encode_base64(uuid_v4())
+  del(.structured)
+  if exists(.kubernetes.labels) {
+    .kubernetes.flat_labels = split(encode_key_value!(.kubernetes.labels, field_delimiter: ","), ",")
+    del(.kubernetes.labels)
+  }
+'''
+
+[sinks.output_es_1]
+  type = "elasticsearch"
+  inputs = ["output_es_1_add_es_index"]
+  endpoint = "https://es.svc.infra.cluster:9999"
+  index = "{{ write_index }}"
+  request.timeout_secs = 2147483648
+  bulk_action = "create"
+  id_key = "viaq_msg_id"
+
+[sinks.output_es_1.auth]
+  strategy = "basic"
+  user = "${VECTOR_SECRET_es_2d1__username}"
+  password = "${VECTOR_SECRET_es_2d1__password}"
+`,
+		}),
+		Entry("with tls key,cert,ca-bundle", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeElasticsearch,
+						Name: "es-1",
+						URL:  "https://es.svc.infra.cluster",
+						Secret: &logging.OutputSecretSpec{
+							Name: "es-1",
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"es-1": {
+					Data: map[string][]byte{
+						"tls.key":       []byte("junk"),
+						"tls.crt":       []byte("junk"),
+						"ca-bundle.crt": []byte("junk"),
+					},
+				},
+			},
+			ExpectedConf: `
+# Set Elasticsearch index for output "es-1"
+[transforms.output_es_1_add_es_index]
+  type = "remap"
+  inputs = ["pipeline_1"]
+  source = '''
+  index = "default"
+  if (.log_type == "application") {
+    index = "app"
+  }
+  if (.log_type == "infrastructure") {
+    index = "infra"
+  }
+  if (.log_type == "audit") {
+    index = "audit"
+  }
+  .write_index = index + "-write"
+  .viaq_msg_id = encode_base64(uuid_v4())
+  del(.structured)
+  if exists(.kubernetes.labels) {
+    .kubernetes.flat_labels = split(encode_key_value!(.kubernetes.labels, field_delimiter: ","), ",")
+    del(.kubernetes.labels)
+  }
+'''
+
+[sinks.output_es_1]
+  type = "elasticsearch"
+  inputs = ["output_es_1_add_es_index"]
+  endpoint = "https://es.svc.infra.cluster:9200"
+  index = "{{ write_index }}"
+  request.timeout_secs = 2147483648
+  bulk_action = "create"
+  id_key = "viaq_msg_id"
+
+[sinks.output_es_1.tls]
+  key_file = '/var/run/ocp-collector/secrets/es-1/tls.key'
+  crt_file = '/var/run/ocp-collector/secrets/es-1/tls.crt'
+  ca_file = '/var/run/ocp-collector/secrets/es-1/ca-bundle.crt'
+`,
+		}),
+		Entry("with structured type key and name", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeElasticsearch,
+						Name: "es-1",
+						URL:  "http://es.svc.infra.cluster:9200",
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Elasticsearch: &logging.Elasticsearch{
+								StructuredTypeKey:  "kubernetes.labels.app.kubernetes.io/name",
+								StructuredTypeName: "nologformat",
+							},
+						},
+					},
+				},
+			},
+			ExpectedConf: `
+# Set Elasticsearch index for output "es-1"
+[transforms.output_es_1_add_es_index]
+  type = "remap"
+  inputs = ["pipeline_1"]
+  source = '''
+  index = "default"
+  if (.log_type == "application") {
+    index = "app"
+  }
+  if (.log_type == "infrastructure") {
+    index = "infra"
+  }
+  if (.log_type == "audit") {
+    index = "audit"
+  }
+  .write_index = index + "-write"
+  .viaq_msg_id = encode_base64(uuid_v4())
+  if exists(.structured) && .structured != {} {
+    type_name = .kubernetes.labels."app.kubernetes.io/name"
+    if type_name == null {
+      type_name = "nologformat"
+    }
+    if type_name != null {
+      .write_index = "app-" + string!(type_name) + "-write"
+    }
+  }
+  if exists(.kubernetes.labels) {
+    .kubernetes.flat_labels = split(encode_key_value!(.kubernetes.labels, field_delimiter: ","), ",")
+    del(.kubernetes.labels)
+  }
+'''
+
+[sinks.output_es_1]
+  type = "elasticsearch"
+  inputs = ["output_es_1_add_es_index"]
+  endpoint = "http://es.svc.infra.cluster:9200"
+  index = "{{ write_index }}"
+  request.timeout_secs = 2147483648
+  bulk_action = "create"
+  id_key = "viaq_msg_id"
+`,
+		}),
+		Entry("with index template, data stream and api key", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeElasticsearch,
+						Name: "es-1",
+						URL:  "https://es.svc.infra.cluster:9999",
+						Secret: &logging.OutputSecretSpec{
+							Name: "es-1",
+						},
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Elasticsearch: &logging.Elasticsearch{
+								Index:      "logs-{.log_type}-{.kubernetes.namespace_name}",
+								DataStream: true,
+							},
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"es-1": {
+					Data: map[string][]byte{
+						"apiKey": []byte("VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="),
+					},
+				},
+			},
+			ExpectedConf: `
+# Set Elasticsearch index for output "es-1"
+[transforms.output_es_1_add_es_index]
+  type = "remap"
+  inputs = ["pipeline_1"]
+  source = '''
+  index = "default"
+  if (.log_type == "application") {
+    index = "app"
+  }
+  if (.log_type == "infrastructure") {
+    index = "infra"
+  }
+  if (.log_type == "audit") {
+    index = "audit"
+  }
+  .write_index = index + "-write"
+  .viaq_msg_id = encode_base64(uuid_v4())
+  .write_index = "logs-" + (to_string(.log_type) ?? "") + "-" + (to_string(.kubernetes.namespace_name) ?? "")
+  del(.structured)
+  parts = split(string!(.write_index), "-", limit: 3)
+  .data_stream.type = parts[0]
+  .data_stream.dataset = parts[1]
+  .data_stream.namespace = parts[2]
+  del(.write_index)
+  if exists(.kubernetes.labels) {
+    .kubernetes.flat_labels = split(encode_key_value!(.kubernetes.labels, field_delimiter: ","), ",")
+    del(.kubernetes.labels)
+  }
+'''
+
+[sinks.output_es_1]
+  type = "elasticsearch"
+  inputs = ["output_es_1_add_es_index"]
+  endpoint = "https://es.svc.infra.cluster:9999"
+  mode = "data_stream"
+  data_stream.auto_routing = true
+  data_stream.sync_fields = true
+  request.timeout_secs = 2147483648
+  id_key = "viaq_msg_id"
+  suppress_type_name = true
+  request.headers.Authorization = "ApiKey ${VECTOR_SECRET_es_2d1__apiKey}"
+`,
+		}),
+	)
+})
+
+func TestVectorConfGenerator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Vector Conf Generation")
+}
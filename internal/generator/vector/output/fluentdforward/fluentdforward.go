@@ -0,0 +1,60 @@
+package fluentdforward
+
+import (
+	"fmt"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	. "github.com/openshift/cluster-logging-operator/internal/generator"
+	"github.com/openshift/cluster-logging-operator/internal/generator/url"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	defaultFluentdForwardPort = "24224"
+)
+
+type FluentdForward struct {
+	Desc        string
+	ComponentID string
+	Inputs      string
+	Address     string
+}
+
+func (ff FluentdForward) Name() string {
+	return "fluentdForwardTemplate"
+}
+
+func (ff FluentdForward) Template() string {
+	return `{{define "` + ff.Name() + `" -}}
+{{if .Desc -}}
+# {{.Desc}}
+{{end -}}
+[sinks.{{.ComponentID}}]
+  type = "socket"
+  inputs = {{.Inputs}}
+  address = {{.Address}}
+  mode = "tcp"
+  encoding.codec = "json"
+{{end}}`
+}
+
+func Conf(o logging.OutputSpec, inputs []string, secret *corev1.Secret, op Options) []Element {
+	id := output.SinkID(o.Name)
+	// URL is parasable, checked at input sanitization
+	u, _ := url.Parse(o.URL)
+	port := u.Port()
+	if port == "" {
+		port = defaultFluentdForwardPort
+	}
+	return []Element{
+		FluentdForward{
+			Desc:        "vector does not implement the fluentd forward protocol, records are sent as newline delimited JSON",
+			ComponentID: id,
+			Inputs:      helpers.MakeInputs(inputs...),
+			Address:     output.Quote(fmt.Sprintf("%s:%s", u.Hostname(), port)),
+		},
+		output.TLS(id, o, secret, url.IsTLSScheme(u.Scheme)),
+	}
+}
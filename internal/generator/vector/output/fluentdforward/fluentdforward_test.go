@@ -0,0 +1,84 @@
+package fluentdforward
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Generate vector config", func() {
+	inputPipeline := []string{"pipeline_1"}
+	var f = func(clspec logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+		return Conf(clfspec.Outputs[0], inputPipeline, secrets[clfspec.Outputs[0].Name], op)
+	}
+	DescribeTable("For fluentd forward output", generator.TestGenerateConfWith(f),
+		Entry("without security", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeFluentdForward,
+						Name: "secureforward-receiver",
+						URL:  "tcp://es.svc.messaging.cluster.local",
+					},
+				},
+			},
+			ExpectedConf: `
+# vector does not implement the fluentd forward protocol, records are sent as newline delimited JSON
+[sinks.output_secureforward_receiver]
+  type = "socket"
+  inputs = ["pipeline_1"]
+  address = "es.svc.messaging.cluster.local:24224"
+  mode = "tcp"
+  encoding.codec = "json"
+`,
+		}),
+		Entry("with tls key,cert,ca-bundle", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeFluentdForward,
+						Name: "secureforward-receiver",
+						URL:  "tls://es.svc.messaging.cluster.local:24224",
+						Secret: &logging.OutputSecretSpec{
+							Name: "secureforward-receiver",
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"secureforward-receiver": {
+					Data: map[string][]byte{
+						"tls.key":       []byte("junk"),
+						"tls.crt":       []byte("junk"),
+						"ca-bundle.crt": []byte("junk"),
+					},
+				},
+			},
+			ExpectedConf: `
+# vector does not implement the fluentd forward protocol, records are sent as newline delimited JSON
+[sinks.output_secureforward_receiver]
+  type = "socket"
+  inputs = ["pipeline_1"]
+  address = "es.svc.messaging.cluster.local:24224"
+  mode = "tcp"
+  encoding.codec = "json"
+
+[sinks.output_secureforward_receiver.tls]
+  enabled = true
+  key_file = '/var/run/ocp-collector/secrets/secureforward-receiver/tls.key'
+  crt_file = '/var/run/ocp-collector/secrets/secureforward-receiver/tls.crt'
+  ca_file = '/var/run/ocp-collector/secrets/secureforward-receiver/ca-bundle.crt'
+`,
+		}),
+	)
+})
+
+func TestVectorConfGenerator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Vector Conf Generation")
+}
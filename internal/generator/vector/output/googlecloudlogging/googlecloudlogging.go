@@ -0,0 +1,81 @@
+package googlecloudlogging
+
+import (
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	. "github.com/openshift/cluster-logging-operator/internal/generator"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/security"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type GoogleCloudLogging struct {
+	ComponentID     string
+	Inputs          string
+	TargetKey       string
+	TargetID        string
+	LogID           string
+	CredentialsPath string
+	Endpoint        string
+}
+
+func (g GoogleCloudLogging) Name() string {
+	return "googleCloudLoggingTemplate"
+}
+
+func (g GoogleCloudLogging) Template() string {
+	return `{{define "` + g.Name() + `" -}}
+[sinks.{{.ComponentID}}]
+  type = "gcp_stackdriver_logs"
+  inputs = {{.Inputs}}
+  {{.TargetKey}} = {{.TargetID}}
+  log_id = {{.LogID}}
+{{- if .CredentialsPath}}
+  credentials_path = {{.CredentialsPath}}
+{{- end}}
+{{- if .Endpoint}}
+  endpoint = {{.Endpoint}}
+{{- end}}
+  severity_key = "level"
+  resource.type = "k8s_node"
+  resource.node_name = "{{"{{ hostname }}"}}"
+{{end}}`
+}
+
+func Conf(o logging.OutputSpec, inputs []string, secret *corev1.Secret, op Options) []Element {
+	id := output.SinkID(o.Name)
+	key, target := Target(o.GoogleCloudLogging)
+	g := GoogleCloudLogging{
+		ComponentID: id,
+		Inputs:      helpers.MakeInputs(inputs...),
+		TargetKey:   key,
+		TargetID:    output.Quote(target),
+		LogID:       output.Quote(helpers.Template(o.GoogleCloudLogging.LogID)),
+	}
+	if o.URL != "" {
+		g.Endpoint = output.Quote(o.URL)
+	}
+	if o.Secret != nil && security.GetFromSecret(secret, constants.GoogleApplicationCredentialsKey) != "" {
+		g.CredentialsPath = security.SecretPath(o.Secret.Name, constants.GoogleApplicationCredentialsKey)
+	}
+	return []Element{
+		g,
+		output.TLS(id, o, secret, false),
+	}
+}
+
+// Target returns the sink option and the ID of the resource logs are written to. Exactly one of them
+// is set, checked at input sanitization
+func Target(g *logging.GoogleCloudLogging) (string, string) {
+	switch {
+	case g.BillingAccountID != "":
+		return "billing_account_id", g.BillingAccountID
+	case g.OrganizationID != "":
+		return "organization_id", g.OrganizationID
+	case g.FolderID != "":
+		return "folder_id", g.FolderID
+	default:
+		return "project_id", g.ProjectID
+	}
+}
@@ -0,0 +1,106 @@
+package googlecloudlogging
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Generate vector config", func() {
+	inputPipeline := []string{"pipeline_1"}
+	var f = func(clspec logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+		return Conf(clfspec.Outputs[0], inputPipeline, secrets[clfspec.Outputs[0].Name], op)
+	}
+	DescribeTable("For Google Cloud Logging output", generator.TestGenerateConfWith(f),
+		Entry("with project ID and service account credentials", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeGoogleCloudLogging,
+						Name: "gcl-1",
+						Secret: &logging.OutputSecretSpec{
+							Name: "gcl-secret",
+						},
+						OutputTypeSpec: logging.OutputTypeSpec{
+							GoogleCloudLogging: &logging.GoogleCloudLogging{
+								ProjectID: "my-project",
+								LogID:     "app-{.kubernetes.namespace_name}",
+							},
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"gcl-1": {
+					Data: map[string][]byte{
+						"google-application-credentials.json": []byte(`{"type":"service_account"}`),
+					},
+				},
+			},
+			ExpectedConf: `
+[sinks.output_gcl_1]
+  type = "gcp_stackdriver_logs"
+  inputs = ["pipeline_1"]
+  project_id = "my-project"
+  log_id = "app-{{ kubernetes.namespace_name }}"
+  credentials_path = '/var/run/ocp-collector/secrets/gcl-secret/google-application-credentials.json'
+  severity_key = "level"
+  resource.type = "k8s_node"
+  resource.node_name = "{{ hostname }}"
+`,
+		}),
+		Entry("with billing account ID and an endpoint override", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeGoogleCloudLogging,
+						Name: "gcl-1",
+						URL:  "http://localhost:8080/v2/entries:write",
+						Secret: &logging.OutputSecretSpec{
+							Name: "gcl-secret",
+						},
+						OutputTypeSpec: logging.OutputTypeSpec{
+							GoogleCloudLogging: &logging.GoogleCloudLogging{
+								BillingAccountID: "billing-1",
+								LogID:            "vector-1",
+							},
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"gcl-1": {
+					Data: map[string][]byte{
+						"google-application-credentials.json": []byte(`{"type":"service_account"}`),
+						"ca-bundle.crt":                       []byte("junk"),
+					},
+				},
+			},
+			ExpectedConf: `
+[sinks.output_gcl_1]
+  type = "gcp_stackdriver_logs"
+  inputs = ["pipeline_1"]
+  billing_account_id = "billing-1"
+  log_id = "vector-1"
+  credentials_path = '/var/run/ocp-collector/secrets/gcl-secret/google-application-credentials.json'
+  endpoint = "http://localhost:8080/v2/entries:write"
+  severity_key = "level"
+  resource.type = "k8s_node"
+  resource.node_name = "{{ hostname }}"
+
+[sinks.output_gcl_1.tls]
+  ca_file = '/var/run/ocp-collector/secrets/gcl-secret/ca-bundle.crt'
+`,
+		}),
+	)
+})
+
+func TestVectorConfGenerator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Vector Conf Generation")
+}
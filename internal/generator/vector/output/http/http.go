@@ -0,0 +1,100 @@
+package http
+
+import (
+	"sort"
+	"strings"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	. "github.com/openshift/cluster-logging-operator/internal/generator"
+	fluentdhttp "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/http"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/security"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type Http struct {
+	ComponentID string
+	Inputs      string
+	URI         string
+	Method      string
+	Codec       string
+	Compression string
+	Headers     []Header
+}
+
+// Header is an HTTP header sent with every request, with its name and value quoted as TOML strings
+type Header struct {
+	Name  string
+	Value string
+}
+
+func (h Http) Name() string {
+	return "vectorHttpTemplate"
+}
+
+func (h Http) Template() string {
+	return `{{define "` + h.Name() + `" -}}
+[sinks.{{.ComponentID}}]
+  type = "http"
+  inputs = {{.Inputs}}
+  uri = {{.URI}}
+  method = {{.Method}}
+  encoding.codec = "{{.Codec}}"
+{{- if .Compression}}
+  compression = {{.Compression}}
+{{- end}}
+{{- range $header := .Headers}}
+  headers.{{$header.Name}} = {{$header.Value}}
+{{- end}}
+{{end}}`
+}
+
+func Conf(o logging.OutputSpec, inputs []string, secret *corev1.Secret, op Options) []Element {
+	id := output.SinkID(o.Name)
+	h := Http{
+		ComponentID: id,
+		Inputs:      helpers.MakeInputs(inputs...),
+		URI:         output.Quote(o.URL),
+		Method:      output.Quote("post"),
+		Codec:       fluentdhttp.FormatNDJSON,
+	}
+	if o.Http != nil {
+		if o.Http.Method != "" {
+			h.Method = output.Quote(strings.ToLower(o.Http.Method))
+		}
+		if o.Http.Format == fluentdhttp.FormatJSON {
+			h.Codec = fluentdhttp.FormatJSON
+		}
+		if o.Http.Compression == fluentdhttp.CompressionGzip {
+			h.Compression = output.Quote(fluentdhttp.CompressionGzip)
+		}
+		h.Headers = Headers(o.Http.Headers)
+	}
+	auth := output.Auth(id, o, secret)
+	if security.HasBearerToken(secret) {
+		auth = output.TokenAuth(id, o, secret)
+	}
+	return []Element{
+		h,
+		output.TLS(id, o, secret, false),
+		auth,
+	}
+}
+
+// Headers returns the HTTP headers sorted by name
+func Headers(headers map[string]string) []Header {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	hs := make([]Header, len(names))
+	for i, name := range names {
+		hs[i] = Header{
+			Name:  output.Quote(name),
+			Value: output.Quote(headers[name]),
+		}
+	}
+	return hs
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Generate vector config", func() {
+	inputPipeline := []string{"pipeline_1"}
+	var f = func(clspec logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+		return Conf(clfspec.Outputs[0], inputPipeline, secrets[clfspec.Outputs[0].Name], op)
+	}
+	DescribeTable("For HTTP output", generator.TestGenerateConfWith(f),
+		Entry("with defaults", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeHttp,
+						Name: "http-receiver",
+						URL:  "http://logs.example.com:8080/ingest",
+					},
+				},
+			},
+			ExpectedConf: `
+[sinks.output_http_receiver]
+  type = "http"
+  inputs = ["pipeline_1"]
+  uri = "http://logs.example.com:8080/ingest"
+  method = "post"
+  encoding.codec = "ndjson"
+`,
+		}),
+		Entry("with quotes, backslashes and environment variable references in the values", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeHttp,
+						Name: "http-receiver",
+						URL:  `http://logs.example.com:8080/ingest?q="${HOME}"`,
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Http: &logging.Http{
+								Headers: map[string]string{
+									"X-Tenant": `a\b$USER`,
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectedConf: `
+[sinks.output_http_receiver]
+  type = "http"
+  inputs = ["pipeline_1"]
+  uri = "http://logs.example.com:8080/ingest?q=\"$${HOME}\""
+  method = "post"
+  encoding.codec = "ndjson"
+  headers."X-Tenant" = "a\\b$$USER"
+`,
+		}),
+		Entry("with method, format, compression, headers, TLS and a bearer token", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeHttp,
+						Name: "http-receiver",
+						URL:  "https://logs.example.com/ingest",
+						Secret: &logging.OutputSecretSpec{
+							Name: "http-receiver",
+						},
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Http: &logging.Http{
+								Method:      "PUT",
+								Format:      "json",
+								Compression: "gzip",
+								Headers: map[string]string{
+									"X-Scope-OrgID": "tenant-a",
+									"Content-Type":  "application/json",
+								},
+							},
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"http-receiver": {
+					Data: map[string][]byte{
+						"ca-bundle.crt": []byte("-- ca-bundle --"),
+						"token":         []byte("my-token"),
+					},
+				},
+			},
+			ExpectedConf: `
+[sinks.output_http_receiver]
+  type = "http"
+  inputs = ["pipeline_1"]
+  uri = "https://logs.example.com/ingest"
+  method = "put"
+  encoding.codec = "json"
+  compression = "gzip"
+  headers."Content-Type" = "application/json"
+  headers."X-Scope-OrgID" = "tenant-a"
+
+[sinks.output_http_receiver.tls]
+  ca_file = '/var/run/ocp-collector/secrets/http-receiver/ca-bundle.crt'
+
+[sinks.output_http_receiver.auth]
+  strategy = "bearer"
+  token = "${VECTOR_SECRET_http_2dreceiver__token}"
+`,
+		}),
+	)
+})
+
+func TestVectorConfGenerator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Vector Conf Generation")
+}
@@ -0,0 +1,170 @@
+package kafka
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	. "github.com/openshift/cluster-logging-operator/internal/generator"
+	fluentdhelpers "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/helpers"
+	fluentdkafka "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/kafka"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/security"
+	"github.com/openshift/cluster-logging-operator/internal/generator/url"
+	. "github.com/openshift/cluster-logging-operator/internal/generator/vector/elements"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	headersKey = "_kafka_headers"
+)
+
+type Kafka struct {
+	ComponentID      string
+	Inputs           string
+	BootstrapServers string
+	Topic            string
+	KeyField         string
+	HeadersKey       string
+	Compression      string
+}
+
+func (k Kafka) Name() string {
+	return "kafkaTemplate"
+}
+
+func (k Kafka) Template() string {
+	return `{{define "` + k.Name() + `" -}}
+[sinks.{{.ComponentID}}]
+  type = "kafka"
+  inputs = {{.Inputs}}
+  bootstrap_servers = {{.BootstrapServers}}
+  topic = {{.Topic}}
+{{- if .KeyField}}
+  key_field = {{.KeyField}}
+{{- end}}
+{{- if .HeadersKey}}
+  headers_key = "{{.HeadersKey}}"
+{{- end}}
+{{- if .Compression}}
+  compression = {{.Compression}}
+{{- end}}
+  encoding.codec = "json"
+  encoding.timestamp_format = "rfc3339"
+{{- if .HeadersKey}}
+  encoding.except_fields = ["{{.HeadersKey}}"]
+{{- end}}
+{{end}}`
+}
+
+type SASL struct {
+	ComponentID string
+	Mechanism   string
+	Username    string
+	Password    string
+}
+
+func (s SASL) Name() string {
+	return "kafkaSASLTemplate"
+}
+
+func (s SASL) Template() string {
+	return `{{define "` + s.Name() + `" -}}
+[sinks.{{.ComponentID}}.sasl]
+  enabled = true
+  mechanism = {{.Mechanism}}
+  username = {{.Username}}
+  password = {{.Password}}
+{{end}}`
+}
+
+func Conf(o logging.OutputSpec, inputs []string, secret *corev1.Secret, op Options) []Element {
+	id := output.SinkID(o.Name)
+	k := Kafka{
+		ComponentID:      id,
+		Inputs:           helpers.MakeInputs(inputs...),
+		BootstrapServers: output.Quote(fluentdkafka.Brokers(o)),
+		Topic:            output.Quote(helpers.Template(fluentdkafka.Topics(o))),
+	}
+	var headers Element = Nil
+	if o.Kafka != nil {
+		// the key and the header values referencing a record field are a single field, checked at input sanitization
+		if field, ok := fluentdhelpers.RecordField(o.Kafka.Key); ok {
+			k.KeyField = output.Quote(field)
+		}
+		if len(o.Kafka.Headers) != 0 {
+			headersID := id + "_add_headers"
+			headers = Remap{
+				Desc:        fmt.Sprintf("Set Kafka headers for output %q", o.Name),
+				ComponentID: headersID,
+				Inputs:      k.Inputs,
+				VRL:         HeadersVRL(o.Kafka.Headers),
+			}
+			k.Inputs = helpers.MakeInputs(headersID)
+			k.HeadersKey = headersKey
+		}
+		if o.Kafka.Compression != "" {
+			k.Compression = output.Quote(o.Kafka.Compression)
+		}
+	}
+	return []Element{
+		headers,
+		k,
+		output.TLS(id, o, secret, IsTLS(o, secret)),
+		SASLConf(id, o, secret),
+	}
+}
+
+// HeadersVRL sets the headers of the messages to the static values and the values of the referenced record fields
+func HeadersVRL(headers map[string]string) string {
+	names := []string{}
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	values := []string{}
+	for _, name := range names {
+		value := fmt.Sprintf("%q", headers[name])
+		if field, ok := fluentdhelpers.RecordField(headers[name]); ok {
+			value = fmt.Sprintf(`to_string(%s) ?? ""`, helpers.VRLPath(field))
+		}
+		values = append(values, fmt.Sprintf("%q: %s", name, value))
+	}
+	return fmt.Sprintf(".%s = {%s}", headersKey, strings.Join(values, ", "))
+}
+
+// IsTLS returns true if any broker uses a TLS scheme, or the output secret provides TLS certificates or requests SASL over SSL
+func IsTLS(o logging.OutputSpec, secret *corev1.Secret) bool {
+	if secret != nil {
+		if _, ok := secret.Data[constants.SaslOverSSL]; ok {
+			return true
+		}
+	}
+	urls := []string{o.URL}
+	if o.Kafka != nil {
+		urls = append(urls, o.Kafka.Brokers...)
+	}
+	for _, s := range urls {
+		if u, err := url.Parse(s); err == nil && url.IsTLSScheme(u.Scheme) {
+			return true
+		}
+	}
+	return output.HasTLSMaterial(secret)
+}
+
+// SASLConf authenticates with the username and password of the output secret, using the SASL mechanism
+// of the secret, PLAIN if unspecified
+func SASLConf(id string, o logging.OutputSpec, secret *corev1.Secret) Element {
+	if o.Secret == nil || !security.HasUsernamePassword(secret) {
+		return Nil
+	}
+	return SASL{
+		ComponentID: id,
+		Mechanism:   output.Quote(fluentdkafka.SASLMechanism(secret)),
+		Username:    output.SecretString(o, constants.ClientUsername),
+		Password:    output.SecretString(o, constants.ClientPassword),
+	}
+}
@@ -0,0 +1,214 @@
+package kafka
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Generate vector config", func() {
+	inputPipeline := []string{"pipeline_1"}
+	var f = func(clspec logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+		return Conf(clfspec.Outputs[0], inputPipeline, secrets[clfspec.Outputs[0].Name], op)
+	}
+	DescribeTable("For Kafka output", generator.TestGenerateConfWith(f),
+		Entry("with username,password over tls", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeKafka,
+						Name: "kafka-receiver",
+						URL:  "tls://broker1-kafka.svc.messaging.cluster.local:9092/topic",
+						Secret: &logging.OutputSecretSpec{
+							Name: "kafka-receiver-1",
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"kafka-receiver": {
+					Data: map[string][]byte{
+						"username": []byte("testuser"),
+						"password": []byte("testpass"),
+					},
+				},
+			},
+			ExpectedConf: `
+[sinks.output_kafka_receiver]
+  type = "kafka"
+  inputs = ["pipeline_1"]
+  bootstrap_servers = "broker1-kafka.svc.messaging.cluster.local:9092"
+  topic = "topic"
+  encoding.codec = "json"
+  encoding.timestamp_format = "rfc3339"
+
+[sinks.output_kafka_receiver.tls]
+  enabled = true
+
+[sinks.output_kafka_receiver.sasl]
+  enabled = true
+  mechanism = "PLAIN"
+  username = "${VECTOR_SECRET_kafka_2dreceiver_2d1__username}"
+  password = "${VECTOR_SECRET_kafka_2dreceiver_2d1__password}"
+`,
+		}),
+		Entry("with brokers, topic and tls key,cert,ca-bundle", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeKafka,
+						Name: "kafka-receiver",
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Kafka: &logging.Kafka{
+								Topic:   "build_complete",
+								Brokers: []string{"tls://broker1-kafka.svc.messaging.cluster.local:9092", "tls://broker2-kafka.svc.messaging.cluster.local:9092"},
+							},
+						},
+						Secret: &logging.OutputSecretSpec{
+							Name: "kafka-receiver-1",
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"kafka-receiver": {
+					Data: map[string][]byte{
+						"tls.key":       []byte("junk"),
+						"tls.crt":       []byte("junk"),
+						"ca-bundle.crt": []byte("junk"),
+					},
+				},
+			},
+			ExpectedConf: `
+[sinks.output_kafka_receiver]
+  type = "kafka"
+  inputs = ["pipeline_1"]
+  bootstrap_servers = "broker1-kafka.svc.messaging.cluster.local:9092,broker2-kafka.svc.messaging.cluster.local:9092"
+  topic = "build_complete"
+  encoding.codec = "json"
+  encoding.timestamp_format = "rfc3339"
+
+[sinks.output_kafka_receiver.tls]
+  enabled = true
+  key_file = '/var/run/ocp-collector/secrets/kafka-receiver-1/tls.key'
+  crt_file = '/var/run/ocp-collector/secrets/kafka-receiver-1/tls.crt'
+  ca_file = '/var/run/ocp-collector/secrets/kafka-receiver-1/ca-bundle.crt'
+`,
+		}),
+		Entry("without security", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeKafka,
+						Name: "kafka-receiver",
+						URL:  "tcp://broker1-kafka.svc.messaging.cluster.local:9092/topic",
+					},
+				},
+			},
+			ExpectedConf: `
+[sinks.output_kafka_receiver]
+  type = "kafka"
+  inputs = ["pipeline_1"]
+  bootstrap_servers = "broker1-kafka.svc.messaging.cluster.local:9092"
+  topic = "topic"
+  encoding.codec = "json"
+  encoding.timestamp_format = "rfc3339"
+`,
+		}),
+		Entry("with SCRAM authentication and a topic referencing record fields", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeKafka,
+						Name: "kafka-receiver",
+						URL:  "tcp://broker1-kafka.svc.messaging.cluster.local:9092",
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Kafka: &logging.Kafka{
+								Topic: "app-{.kubernetes.namespace_name}",
+							},
+						},
+						Secret: &logging.OutputSecretSpec{
+							Name: "kafka-receiver-1",
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"kafka-receiver": {
+					Data: map[string][]byte{
+						"username":       []byte("testuser"),
+						"password":       []byte("testpass"),
+						"sasl_mechanism": []byte("SCRAM-SHA-256"),
+					},
+				},
+			},
+			ExpectedConf: `
+[sinks.output_kafka_receiver]
+  type = "kafka"
+  inputs = ["pipeline_1"]
+  bootstrap_servers = "broker1-kafka.svc.messaging.cluster.local:9092"
+  topic = "app-{{ kubernetes.namespace_name }}"
+  encoding.codec = "json"
+  encoding.timestamp_format = "rfc3339"
+
+[sinks.output_kafka_receiver.sasl]
+  enabled = true
+  mechanism = "SCRAM-SHA-256"
+  username = "${VECTOR_SECRET_kafka_2dreceiver_2d1__username}"
+  password = "${VECTOR_SECRET_kafka_2dreceiver_2d1__password}"
+`,
+		}),
+		Entry("with message key, headers and compression", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeKafka,
+						Name: "kafka-receiver",
+						URL:  "tcp://broker1-kafka.svc.messaging.cluster.local:9092/topic",
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Kafka: &logging.Kafka{
+								Key: "{.kubernetes.pod_id}",
+								Headers: map[string]string{
+									"cluster":   "east",
+									"namespace": "{.kubernetes.namespace_name}",
+								},
+								Compression: "zstd",
+							},
+						},
+					},
+				},
+			},
+			ExpectedConf: `
+# Set Kafka headers for output "kafka-receiver"
+[transforms.output_kafka_receiver_add_headers]
+  type = "remap"
+  inputs = ["pipeline_1"]
+  source = '''
+  ._kafka_headers = {"cluster": "east", "namespace": to_string(.kubernetes.namespace_name) ?? ""}
+'''
+
+[sinks.output_kafka_receiver]
+  type = "kafka"
+  inputs = ["output_kafka_receiver_add_headers"]
+  bootstrap_servers = "broker1-kafka.svc.messaging.cluster.local:9092"
+  topic = "topic"
+  key_field = "kubernetes.pod_id"
+  headers_key = "_kafka_headers"
+  compression = "zstd"
+  encoding.codec = "json"
+  encoding.timestamp_format = "rfc3339"
+  encoding.except_fields = ["_kafka_headers"]
+`,
+		}),
+	)
+})
+
+func TestVectorConfGenerator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Vector Conf Generation")
+}
@@ -0,0 +1,144 @@
+package loki
+
+import (
+	"sort"
+	"strings"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	. "github.com/openshift/cluster-logging-operator/internal/generator"
+	fluentdloki "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/loki"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/security"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	lokiLabelKubernetesHost = "kubernetes.host"
+)
+
+var (
+	defaultLabelKeys = []string{
+		"log_type",
+
+		//container labels
+		"kubernetes.namespace_name",
+		"kubernetes.pod_name",
+		"kubernetes.container_name",
+	}
+	requiredLabelKeys = []string{
+		lokiLabelKubernetesHost,
+	}
+)
+
+type Loki struct {
+	ComponentID string
+	Inputs      string
+	Endpoint    string
+	TenantID    string
+	Labels      []Label
+}
+
+// Label is a loki stream label and the template of its value
+type Label struct {
+	Name  string
+	Value string
+}
+
+func (l Loki) Name() string {
+	return "lokiTemplate"
+}
+
+func (l Loki) Template() string {
+	return `{{define "` + l.Name() + `" -}}
+[sinks.{{.ComponentID}}]
+  type = "loki"
+  inputs = {{.Inputs}}
+  endpoint = {{.Endpoint}}
+  out_of_order_action = "accept"
+  encoding.codec = "json"
+{{- if .TenantID}}
+  tenant_id = {{.TenantID}}
+{{- end}}
+{{- range $label := .Labels}}
+  labels.{{$label.Name}} = {{$label.Value}}
+{{- end}}
+{{end}}`
+}
+
+func Conf(o logging.OutputSpec, inputs []string, secret *corev1.Secret, op Options) []Element {
+	id := output.SinkID(o.Name)
+	auth := output.Auth(id, o, secret)
+	if security.HasBearerToken(secret) {
+		auth = output.TokenAuth(id, o, secret)
+	}
+	return []Element{
+		Loki{
+			ComponentID: id,
+			Inputs:      helpers.MakeInputs(inputs...),
+			Endpoint:    output.Quote(fluentdloki.URLBase(o)),
+			TenantID:    Tenant(o.Loki),
+			Labels:      Labels(o.Loki),
+		},
+		output.TLS(id, o, secret, false),
+		auth,
+	}
+}
+
+func lokiLabelKeys(l *logging.Loki) []string {
+	var keys sets.String
+	if l != nil && len(l.LabelKeys) != 0 {
+		keys = sets.NewString(l.LabelKeys...)
+	} else {
+		keys = sets.NewString(defaultLabelKeys...)
+	}
+	// Ensure required tags for serialization
+	keys.Insert(requiredLabelKeys...)
+	return keys.List()
+}
+
+// Labels returns the loki stream labels for the label keys of the output followed by the labels built
+// from templates, which replace the label of a label key with the same name. The host label is taken
+// from the node the collector runs on
+func Labels(l *logging.Loki) []Label {
+	labels := []Label{}
+	index := map[string]int{}
+	add := func(name, value string) {
+		if i, found := index[name]; found {
+			labels[i].Value = value
+			return
+		}
+		index[name] = len(labels)
+		labels = append(labels, Label{
+			Name:  name,
+			Value: value,
+		})
+	}
+	for _, k := range lokiLabelKeys(l) {
+		value := output.Quote(helpers.TemplatePath(k))
+		if k == lokiLabelKubernetesHost {
+			value = `"${VECTOR_SELF_NODE_NAME}"`
+		}
+		add(strings.Replace(k, ".", "_", -1), value)
+	}
+	if l != nil {
+		names := make([]string, 0, len(l.Labels))
+		for name := range l.Labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			add(name, output.Quote(helpers.Template(l.Labels[name])))
+		}
+	}
+	return labels
+}
+
+// Tenant returns the template of the tenant ID, taken from the record field named by TenantKey
+func Tenant(l *logging.Loki) string {
+	if l == nil || l.TenantKey == "" {
+		return ""
+	}
+	return output.Quote(helpers.TemplatePath(l.TenantKey))
+}
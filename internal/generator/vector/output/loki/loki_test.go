@@ -0,0 +1,142 @@
+package loki
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Generate vector config", func() {
+	inputPipeline := []string{"pipeline_1"}
+	var f = func(clspec logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+		return Conf(clfspec.Outputs[0], inputPipeline, secrets[clfspec.Outputs[0].Name], op)
+	}
+	DescribeTable("For Loki output", generator.TestGenerateConfWith(f),
+		Entry("with default labels", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeLoki,
+						Name: "loki-receiver",
+						URL:  "https://logs-us-west1.grafana.net",
+						Secret: &logging.OutputSecretSpec{
+							Name: "loki-receiver",
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"loki-receiver": {
+					Data: map[string][]byte{
+						"username": []byte("testuser"),
+						"password": []byte("testpass"),
+					},
+				},
+			},
+			ExpectedConf: `
+[sinks.output_loki_receiver]
+  type = "loki"
+  inputs = ["pipeline_1"]
+  endpoint = "https://logs-us-west1.grafana.net"
+  out_of_order_action = "accept"
+  encoding.codec = "json"
+  labels.kubernetes_container_name = "{{ kubernetes.container_name }}"
+  labels.kubernetes_host = "${VECTOR_SELF_NODE_NAME}"
+  labels.kubernetes_namespace_name = "{{ kubernetes.namespace_name }}"
+  labels.kubernetes_pod_name = "{{ kubernetes.pod_name }}"
+  labels.log_type = "{{ log_type }}"
+
+[sinks.output_loki_receiver.auth]
+  strategy = "basic"
+  user = "${VECTOR_SECRET_loki_2dreceiver__username}"
+  password = "${VECTOR_SECRET_loki_2dreceiver__password}"
+`,
+		}),
+		Entry("with custom labels and tenant key", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeLoki,
+						Name: "loki-receiver",
+						URL:  "http://lokistack-dev-distributor-http.openshift-logging.svc:3100",
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Loki: &logging.Loki{
+								TenantKey: "kubernetes.namespace_name",
+								LabelKeys: []string{"kubernetes.labels.app", "kubernetes.container_name"},
+							},
+						},
+					},
+				},
+			},
+			ExpectedConf: `
+[sinks.output_loki_receiver]
+  type = "loki"
+  inputs = ["pipeline_1"]
+  endpoint = "http://lokistack-dev-distributor-http.openshift-logging.svc:3100"
+  out_of_order_action = "accept"
+  encoding.codec = "json"
+  tenant_id = "{{ kubernetes.namespace_name }}"
+  labels.kubernetes_container_name = "{{ kubernetes.container_name }}"
+  labels.kubernetes_host = "${VECTOR_SELF_NODE_NAME}"
+  labels.kubernetes_labels_app = "{{ kubernetes.labels.app }}"
+`,
+		}),
+		Entry("with label templates, URL path prefix and tenant bearer token", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeLoki,
+						Name: "loki-receiver",
+						URL:  "https://gateway.example.com/api/logs/v1/application/",
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Loki: &logging.Loki{
+								LabelKeys: []string{"kubernetes.container_name"},
+								Labels: map[string]string{
+									"cluster":                   "east",
+									"app":                       "{.kubernetes.labels.app}",
+									"kubernetes_container_name": "container-{.kubernetes.container_name}",
+								},
+							},
+						},
+						Secret: &logging.OutputSecretSpec{
+							Name: "loki-receiver",
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"loki-receiver": {
+					Data: map[string][]byte{
+						"token": []byte("tenant-token"),
+					},
+				},
+			},
+			ExpectedConf: `
+[sinks.output_loki_receiver]
+  type = "loki"
+  inputs = ["pipeline_1"]
+  endpoint = "https://gateway.example.com/api/logs/v1/application"
+  out_of_order_action = "accept"
+  encoding.codec = "json"
+  labels.kubernetes_container_name = "container-{{ kubernetes.container_name }}"
+  labels.kubernetes_host = "${VECTOR_SELF_NODE_NAME}"
+  labels.app = "{{ kubernetes.labels.app }}"
+  labels.cluster = "east"
+
+[sinks.output_loki_receiver.auth]
+  strategy = "bearer"
+  token = "${VECTOR_SECRET_loki_2dreceiver__token}"
+`,
+		}),
+	)
+})
+
+func TestVectorConfGenerator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Vector Conf Generation")
+}
@@ -0,0 +1,10 @@
+package output
+
+import "github.com/openshift/cluster-logging-operator/internal/generator/vector/helpers"
+
+const outputPrefix = "output_"
+
+// SinkID returns the component ID of the sink for an output
+func SinkID(name string) string {
+	return outputPrefix + helpers.FormatComponentID(name)
+}
@@ -0,0 +1,192 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/security"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TLSConf is the 'tls' table of a sink
+type TLSConf struct {
+	ComponentID string
+	// Enabled is required by sinks which do not derive TLS from the endpoint scheme (e.g. kafka, socket)
+	Enabled    bool
+	KeyPath    string
+	CertPath   string
+	CAFilePath string
+}
+
+func (t TLSConf) Name() string {
+	return "vectorTLSTemplate"
+}
+
+func (t TLSConf) Template() string {
+	return `{{define "` + t.Name() + `" -}}
+[sinks.{{.ComponentID}}.tls]
+{{- if .Enabled}}
+  enabled = true
+{{- end}}
+{{- if .KeyPath}}
+  key_file = {{.KeyPath}}
+  crt_file = {{.CertPath}}
+{{- end}}
+{{- if .CAFilePath}}
+  ca_file = {{.CAFilePath}}
+{{- end}}
+{{end}}`
+}
+
+// BasicAuth is the 'auth' table of a sink using HTTP basic authentication
+type BasicAuth struct {
+	ComponentID string
+	Username    string
+	Password    string
+}
+
+func (b BasicAuth) Name() string {
+	return "vectorBasicAuthTemplate"
+}
+
+func (b BasicAuth) Template() string {
+	return `{{define "` + b.Name() + `" -}}
+[sinks.{{.ComponentID}}.auth]
+  strategy = "basic"
+  user = {{.Username}}
+  password = {{.Password}}
+{{end}}`
+}
+
+// BearerAuth is the 'auth' table of a sink using a bearer token
+type BearerAuth struct {
+	ComponentID string
+	Token       string
+}
+
+func (b BearerAuth) Name() string {
+	return "vectorBearerAuthTemplate"
+}
+
+func (b BearerAuth) Template() string {
+	return `{{define "` + b.Name() + `" -}}
+[sinks.{{.ComponentID}}.auth]
+  strategy = "bearer"
+  token = {{.Token}}
+{{end}}`
+}
+
+// TLS returns the TLS configuration of a sink using the certificates of the output secret, or Nil when
+// there is nothing to configure
+func TLS(id string, o logging.OutputSpec, secret *corev1.Secret, enabled bool) generator.Element {
+	conf := TLSConf{
+		ComponentID: id,
+		Enabled:     enabled,
+	}
+	if o.Secret != nil {
+		if o.Name == logging.OutputNameDefault || security.HasTLSCertAndKey(secret) {
+			conf.KeyPath = security.SecretPath(o.Secret.Name, constants.ClientPrivateKey)
+			conf.CertPath = security.SecretPath(o.Secret.Name, constants.ClientCertKey)
+		}
+		if o.Name == logging.OutputNameDefault || security.HasCABundle(secret) {
+			conf.CAFilePath = security.SecretPath(o.Secret.Name, constants.TrustedCABundleKey)
+		}
+	}
+	if !conf.Enabled && conf.KeyPath == "" && conf.CAFilePath == "" {
+		return generator.Nil
+	}
+	return conf
+}
+
+// HasTLSMaterial returns true if the output secret provides a CA bundle or a client certificate
+func HasTLSMaterial(secret *corev1.Secret) bool {
+	return security.HasCABundle(secret) || security.HasTLSCertAndKey(secret)
+}
+
+// SecretEnvKeys are the keys of output secrets which are read by vector from the environment, so they are
+// not written to the configuration
+var SecretEnvKeys = []string{
+	constants.ClientUsername,
+	constants.ClientPassword,
+	constants.BearerTokenFileKey,
+	constants.SplunkHECTokenKey,
+	constants.ElasticsearchAPIKey,
+	constants.AWSAccessKeyID,
+	constants.AWSSecretAccessKey,
+}
+
+// SecretEnvVar returns the name of the collector environment variable set from a key of an output secret,
+// the key must be one of SecretEnvKeys. The characters of the secret name which may not be in vector
+// variable names are hex escaped, e.g. the password of my-secret is VECTOR_SECRET_my_2dsecret__password
+func SecretEnvVar(secretName, key string) string {
+	b := &strings.Builder{}
+	for _, c := range []byte(secretName) {
+		if ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(b, "_%02x", c)
+		}
+	}
+	return fmt.Sprintf("VECTOR_SECRET_%s__%s", b.String(), key)
+}
+
+// SecretString returns the TOML string interpolating the environment variable set from a key of the output
+// secret, the key must be one of SecretEnvKeys
+func SecretString(o logging.OutputSpec, key string) string {
+	return SecretStringWithPrefix(o, key, "")
+}
+
+// SecretStringWithPrefix returns the TOML string of a prefix followed by the interpolated key of the output secret
+func SecretStringWithPrefix(o logging.OutputSpec, key, prefix string) string {
+	return fmt.Sprintf(`"%s${%s}"`, prefix, SecretEnvVar(o.Secret.Name, key))
+}
+
+// Auth returns the basic authentication configuration of a sink, or Nil if the output secret
+// has no username and password
+func Auth(id string, o logging.OutputSpec, secret *corev1.Secret) generator.Element {
+	if o.Secret == nil || !security.HasUsernamePassword(secret) {
+		return generator.Nil
+	}
+	return BasicAuth{
+		ComponentID: id,
+		Username:    SecretString(o, constants.ClientUsername),
+		Password:    SecretString(o, constants.ClientPassword),
+	}
+}
+
+// Quote returns s as a TOML basic string. Dollar signs are doubled so vector does not interpolate
+// environment variables into the value
+func Quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '$':
+			b.WriteString("$$")
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// TokenAuth returns the bearer token authentication configuration of a sink, or Nil if the output secret
+// has no token
+func TokenAuth(id string, o logging.OutputSpec, secret *corev1.Secret) generator.Element {
+	if o.Secret == nil || !security.HasBearerToken(secret) {
+		return generator.Nil
+	}
+	return BearerAuth{
+		ComponentID: id,
+		Token:       SecretString(o, constants.BearerTokenFileKey),
+	}
+}
@@ -0,0 +1,93 @@
+package splunk
+
+import (
+	"fmt"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	. "github.com/openshift/cluster-logging-operator/internal/generator"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/security"
+	fluentdsplunk "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/splunk"
+	urlhelper "github.com/openshift/cluster-logging-operator/internal/generator/url"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type Splunk struct {
+	ComponentID string
+	Inputs      string
+	Endpoint    string
+	Token       string
+	Index       string
+	Source      string
+	SourceType  string
+}
+
+func (s Splunk) Name() string {
+	return "vectorSplunkTemplate"
+}
+
+func (s Splunk) Template() string {
+	return `{{define "` + s.Name() + `" -}}
+[sinks.{{.ComponentID}}]
+  type = "splunk_hec"
+  inputs = {{.Inputs}}
+  endpoint = {{.Endpoint}}
+{{- if .Token}}
+  token = {{.Token}}
+{{- end}}
+{{- if .Index}}
+  index = {{.Index}}
+{{- end}}
+{{- if .Source}}
+  source = {{.Source}}
+{{- end}}
+{{- if .SourceType}}
+  sourcetype = {{.SourceType}}
+{{- end}}
+  encoding.codec = "json"
+{{end}}`
+}
+
+func Conf(o logging.OutputSpec, inputs []string, secret *corev1.Secret, op Options) []Element {
+	id := output.SinkID(o.Name)
+	s := Splunk{
+		ComponentID: id,
+		Inputs:      helpers.MakeInputs(inputs...),
+		Endpoint:    output.Quote(Endpoint(o)),
+	}
+	if o.Secret != nil && security.GetFromSecret(secret, constants.SplunkHECTokenKey) != "" {
+		s.Token = output.SecretString(o, constants.SplunkHECTokenKey)
+	}
+	if o.Splunk != nil {
+		s.Index = quoteTemplate(o.Splunk.Index)
+		s.Source = quoteTemplate(o.Splunk.Source)
+		s.SourceType = quoteTemplate(o.Splunk.SourceType)
+	}
+	return []Element{
+		s,
+		output.TLS(id, o, secret, false),
+	}
+}
+
+// quoteTemplate returns the quoted vector template of an output template, or an empty string if the output
+// template is empty
+func quoteTemplate(template string) string {
+	if template == "" {
+		return ""
+	}
+	return output.Quote(helpers.Template(template))
+}
+
+// Endpoint returns the base URL of the HTTP Event Collector, on the default HEC port unless the
+// output URL has a port
+func Endpoint(o logging.OutputSpec) string {
+	// url is parasable, checked at input sanitization
+	u, _ := urlhelper.Parse(o.URL)
+	port := u.Port()
+	if port == "" {
+		port = fluentdsplunk.DefaultHECPort
+	}
+	return fmt.Sprintf("%s://%s:%s", u.Scheme, u.Hostname(), port)
+}
@@ -0,0 +1,94 @@
+package splunk
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Generate vector config", func() {
+	inputPipeline := []string{"pipeline_1"}
+	var f = func(clspec logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+		return Conf(clfspec.Outputs[0], inputPipeline, secrets[clfspec.Outputs[0].Name], op)
+	}
+	DescribeTable("For Splunk output", generator.TestGenerateConfWith(f),
+		Entry("with the default HEC port", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeSplunk,
+						Name: "splunk-receiver",
+						URL:  "https://splunk.example.com",
+						Secret: &logging.OutputSecretSpec{
+							Name: "splunk-receiver",
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"splunk-receiver": {
+					Data: map[string][]byte{
+						"hecToken": []byte("my-hec-token"),
+					},
+				},
+			},
+			ExpectedConf: `
+[sinks.output_splunk_receiver]
+  type = "splunk_hec"
+  inputs = ["pipeline_1"]
+  endpoint = "https://splunk.example.com:8088"
+  token = "${VECTOR_SECRET_splunk_2dreceiver__hecToken}"
+  encoding.codec = "json"
+`,
+		}),
+		Entry("with templated index, source and sourcetype", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeSplunk,
+						Name: "splunk-receiver",
+						URL:  "http://splunk.example.com:9088",
+						Secret: &logging.OutputSecretSpec{
+							Name: "splunk-receiver",
+						},
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Splunk: &logging.Splunk{
+								Index:      "app-{.kubernetes.namespace_name}",
+								Source:     "openshift",
+								SourceType: "{.log_type}",
+							},
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"splunk-receiver": {
+					Data: map[string][]byte{
+						"hecToken": []byte("my-hec-token"),
+					},
+				},
+			},
+			ExpectedConf: `
+[sinks.output_splunk_receiver]
+  type = "splunk_hec"
+  inputs = ["pipeline_1"]
+  endpoint = "http://splunk.example.com:9088"
+  token = "${VECTOR_SECRET_splunk_2dreceiver__hecToken}"
+  index = "app-{{ kubernetes.namespace_name }}"
+  source = "openshift"
+  sourcetype = "{{ log_type }}"
+  encoding.codec = "json"
+`,
+		}),
+	)
+})
+
+func TestVectorConfGenerator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Vector Conf Generation")
+}
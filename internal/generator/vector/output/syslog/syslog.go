@@ -0,0 +1,154 @@
+package syslog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	. "github.com/openshift/cluster-logging-operator/internal/generator"
+	fluentdsyslog "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/syslog"
+	urlhelper "github.com/openshift/cluster-logging-operator/internal/generator/url"
+	. "github.com/openshift/cluster-logging-operator/internal/generator/vector/elements"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/helpers"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	defaultFacility = 1 // user
+	defaultSeverity = 7 // debug
+	nilValue        = `"-"`
+)
+
+var (
+	facilities = map[string]int{
+		"kern": 0, "kernel": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+		"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11, "ntp": 12, "security": 13, "console": 14, "solaris-cron": 15,
+		"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+	}
+	severities = map[string]int{
+		"emergency": 0, "emerg": 0, "alert": 1, "critical": 2, "crit": 2, "error": 3, "err": 3,
+		"warning": 4, "warn": 4, "notice": 5, "informational": 6, "info": 6, "debug": 7,
+	}
+)
+
+type Syslog struct {
+	ComponentID string
+	Inputs      string
+	Address     string
+	Mode        string
+}
+
+func (s Syslog) Name() string {
+	return "syslogTemplate"
+}
+
+func (s Syslog) Template() string {
+	return `{{define "` + s.Name() + `" -}}
+[sinks.{{.ComponentID}}]
+  type = "socket"
+  inputs = {{.Inputs}}
+  address = {{.Address}}
+  mode = "{{.Mode}}"
+  encoding.codec = "text"
+{{end}}`
+}
+
+const RFC5424VRL = `
+ts = to_timestamp(."@timestamp") ?? now()
+.message = "<" + to_string(pri) + ">1 " + format_timestamp!(ts, format: "%+") + " " + hostname + " " + app_name + " " + proc_id + " " + msg_id + " - " + payload
+`
+
+const RFC3164VRL = `
+ts = to_timestamp(."@timestamp") ?? now()
+.message = "<" + to_string(pri) + ">" + format_timestamp!(ts, format: "%b %e %H:%M:%S") + " " + hostname + " " + tag + ": " + payload
+`
+
+func Conf(o logging.OutputSpec, inputs []string, secret *corev1.Secret, op Options) []Element {
+	id := output.SinkID(o.Name)
+	formatID := id + "_format"
+	// URL is parasable, checked at input sanitization
+	u, _ := urlhelper.Parse(o.URL)
+	mode := urlhelper.PlainScheme(u.Scheme)
+	var tls Element = Nil
+	if mode == "tcp" {
+		tls = output.TLS(id, o, secret, urlhelper.IsTLSScheme(u.Scheme))
+	}
+	return []Element{
+		Remap{
+			Desc:        fmt.Sprintf("Format records as syslog messages for output %q", o.Name),
+			ComponentID: formatID,
+			Inputs:      helpers.MakeInputs(inputs...),
+			VRL:         FormatVRL(o.Syslog),
+		},
+		Syslog{
+			ComponentID: id,
+			Inputs:      helpers.MakeInputs(formatID),
+			Address:     output.Quote(u.Host),
+			Mode:        mode,
+		},
+		tls,
+	}
+}
+
+// FormatVRL builds the syslog message for the configured RFC in the message field
+func FormatVRL(s *logging.Syslog) string {
+	if s == nil {
+		s = &logging.Syslog{}
+	}
+	vrl := []string{
+		fmt.Sprintf("pri = %s * 8 + %s", code(s.Facility, facilities, defaultFacility), code(s.Severity, severities, defaultSeverity)),
+		`hostname = get_env_var("VECTOR_SELF_NODE_NAME") ?? "-"`,
+		fmt.Sprintf("payload = %s", payload(s.PayloadKey)),
+	}
+	if fluentdsyslog.Rfc(s) == "rfc3164" {
+		vrl = append(vrl,
+			fmt.Sprintf("tag = %s", value(s.Tag)),
+			strings.TrimSpace(RFC3164VRL),
+		)
+	} else {
+		vrl = append(vrl,
+			fmt.Sprintf("app_name = %s", value(s.AppName)),
+			fmt.Sprintf("proc_id = %s", value(s.ProcID)),
+			fmt.Sprintf("msg_id = %s", value(s.MsgID)),
+			strings.TrimSpace(RFC5424VRL),
+		)
+	}
+	return strings.Join(vrl, "\n")
+}
+
+// code returns the VRL expression for a facility or severity, given as a decimal integer, a keyword
+// or a record field holding a decimal integer
+func code(v string, keywords map[string]int, defaultCode int) string {
+	if fluentdsyslog.IsKeyExpr(v) {
+		return fmt.Sprintf("(to_int(%s) ?? %d)", helpers.VRLPath(strings.TrimPrefix(v, "$.")), defaultCode)
+	}
+	if c, err := strconv.Atoi(v); err == nil {
+		return strconv.Itoa(c)
+	}
+	if c, ok := keywords[strings.ToLower(v)]; ok {
+		return strconv.Itoa(c)
+	}
+	return strconv.Itoa(defaultCode)
+}
+
+// value returns the VRL expression for a header field, given as a literal or a record field.
+// Fluentd tag expressions have no equivalent and are sent as the nil value
+func value(v string) string {
+	if v == "" || v == "tag" || fluentdsyslog.IsTagExpr(v) {
+		return nilValue
+	}
+	if fluentdsyslog.IsKeyExpr(v) {
+		return fmt.Sprintf("to_string(%s) ?? %s", helpers.VRLPath(strings.TrimPrefix(v, "$.")), nilValue)
+	}
+	return fmt.Sprintf("%q", v)
+}
+
+func payload(key string) string {
+	path := ".message"
+	if key != "" {
+		path = helpers.VRLPath(strings.TrimPrefix(key, "$."))
+	}
+	return fmt.Sprintf("to_string(%s) ?? encode_json(%s)", path, path)
+}
@@ -0,0 +1,158 @@
+package syslog
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/generator"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Generate vector config", func() {
+	inputPipeline := []string{"pipeline_1"}
+	var f = func(clspec logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
+		return Conf(clfspec.Outputs[0], inputPipeline, secrets[clfspec.Outputs[0].Name], op)
+	}
+	DescribeTable("For Syslog output", generator.TestGenerateConfWith(f),
+		Entry("RFC5424 over tcp with defaults", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeSyslog,
+						Name: "syslog-receiver",
+						URL:  "tcp://syslog-receiver.openshift-logging.svc:24224",
+					},
+				},
+			},
+			ExpectedConf: `
+# Format records as syslog messages for output "syslog-receiver"
+[transforms.output_syslog_receiver_format]
+  type = "remap"
+  inputs = ["pipeline_1"]
+  source = '''
+  pri = 1 * 8 + 7
+  hostname = get_env_var("VECTOR_SELF_NODE_NAME") ?? "-"
+  payload = to_string(.message) ?? encode_json(.message)
+  app_name = "-"
+  proc_id = "-"
+  msg_id = "-"
+  ts = to_timestamp(."@timestamp") ?? now()
+  .message = "<" + to_string(pri) + ">1 " + format_timestamp!(ts, format: "%+") + " " + hostname + " " + app_name + " " + proc_id + " " + msg_id + " - " + payload
+'''
+
+[sinks.output_syslog_receiver]
+  type = "socket"
+  inputs = ["output_syslog_receiver_format"]
+  address = "syslog-receiver.openshift-logging.svc:24224"
+  mode = "tcp"
+  encoding.codec = "text"
+`,
+		}),
+		Entry("RFC3164 over udp with record field expressions", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeSyslog,
+						Name: "syslog-receiver",
+						URL:  "udp://syslog-receiver.openshift-logging.svc:514",
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Syslog: &logging.Syslog{
+								RFC:        "RFC3164",
+								Facility:   "local0",
+								Severity:   "$.severity",
+								Tag:        "$.kubernetes.container_name",
+								PayloadKey: "structured",
+							},
+						},
+					},
+				},
+			},
+			ExpectedConf: `
+# Format records as syslog messages for output "syslog-receiver"
+[transforms.output_syslog_receiver_format]
+  type = "remap"
+  inputs = ["pipeline_1"]
+  source = '''
+  pri = 16 * 8 + (to_int(.severity) ?? 7)
+  hostname = get_env_var("VECTOR_SELF_NODE_NAME") ?? "-"
+  payload = to_string(.structured) ?? encode_json(.structured)
+  tag = to_string(.kubernetes.container_name) ?? "-"
+  ts = to_timestamp(."@timestamp") ?? now()
+  .message = "<" + to_string(pri) + ">" + format_timestamp!(ts, format: "%b %e %H:%M:%S") + " " + hostname + " " + tag + ": " + payload
+'''
+
+[sinks.output_syslog_receiver]
+  type = "socket"
+  inputs = ["output_syslog_receiver_format"]
+  address = "syslog-receiver.openshift-logging.svc:514"
+  mode = "udp"
+  encoding.codec = "text"
+`,
+		}),
+		Entry("RFC5424 over tls with ca-bundle", generator.ConfGenerateTest{
+			CLFSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{
+						Type: logging.OutputTypeSyslog,
+						Name: "syslog-receiver",
+						URL:  "tls://syslog-receiver.openshift-logging.svc:6514",
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Syslog: &logging.Syslog{
+								Facility: "user",
+								Severity: "informational",
+								AppName:  "myapp",
+								ProcID:   "$.kubernetes.pod_name",
+								MsgID:    "mymsg",
+							},
+						},
+						Secret: &logging.OutputSecretSpec{
+							Name: "syslog-receiver",
+						},
+					},
+				},
+			},
+			Secrets: map[string]*corev1.Secret{
+				"syslog-receiver": {
+					Data: map[string][]byte{
+						"ca-bundle.crt": []byte("junk"),
+					},
+				},
+			},
+			ExpectedConf: `
+# Format records as syslog messages for output "syslog-receiver"
+[transforms.output_syslog_receiver_format]
+  type = "remap"
+  inputs = ["pipeline_1"]
+  source = '''
+  pri = 1 * 8 + 6
+  hostname = get_env_var("VECTOR_SELF_NODE_NAME") ?? "-"
+  payload = to_string(.message) ?? encode_json(.message)
+  app_name = "myapp"
+  proc_id = to_string(.kubernetes.pod_name) ?? "-"
+  msg_id = "mymsg"
+  ts = to_timestamp(."@timestamp") ?? now()
+  .message = "<" + to_string(pri) + ">1 " + format_timestamp!(ts, format: "%+") + " " + hostname + " " + app_name + " " + proc_id + " " + msg_id + " - " + payload
+'''
+
+[sinks.output_syslog_receiver]
+  type = "socket"
+  inputs = ["output_syslog_receiver_format"]
+  address = "syslog-receiver.openshift-logging.svc:6514"
+  mode = "tcp"
+  encoding.codec = "text"
+
+[sinks.output_syslog_receiver.tls]
+  enabled = true
+  ca_file = '/var/run/ocp-collector/secrets/syslog-receiver/ca-bundle.crt'
+`,
+		}),
+	)
+})
+
+func TestVectorConfGenerator(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Vector Conf Generation")
+}
@@ -0,0 +1,61 @@
+package vector
+
+import (
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	. "github.com/openshift/cluster-logging-operator/internal/generator"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output/cloudwatch"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output/elasticsearch"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output/fluentdforward"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output/googlecloudlogging"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output/http"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output/kafka"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output/loki"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output/splunk"
+	"github.com/openshift/cluster-logging-operator/internal/generator/vector/output/syslog"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Outputs(clspec *logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec *logging.ClusterLogForwarderSpec, op Options) []Element {
+	outputs := []Element{
+		Comment("Ship logs to specific outputs"),
+	}
+	for _, o := range clfspec.Outputs {
+		secret := secrets[o.Name]
+		inputs := OutputInputs(clfspec, o.Name)
+		switch o.Type {
+		case logging.OutputTypeElasticsearch:
+			outputs = MergeElements(outputs, elasticsearch.Conf(o, inputs, secret, op))
+		case logging.OutputTypeFluentdForward:
+			outputs = MergeElements(outputs, fluentdforward.Conf(o, inputs, secret, op))
+		case logging.OutputTypeKafka:
+			outputs = MergeElements(outputs, kafka.Conf(o, inputs, secret, op))
+		case logging.OutputTypeCloudwatch:
+			outputs = MergeElements(outputs, cloudwatch.Conf(o, inputs, secret, op))
+		case logging.OutputTypeSyslog:
+			outputs = MergeElements(outputs, syslog.Conf(o, inputs, secret, op))
+		case logging.OutputTypeLoki:
+			outputs = MergeElements(outputs, loki.Conf(o, inputs, secret, op))
+		case logging.OutputTypeGoogleCloudLogging:
+			outputs = MergeElements(outputs, googlecloudlogging.Conf(o, inputs, secret, op))
+		case logging.OutputTypeHttp:
+			outputs = MergeElements(outputs, http.Conf(o, inputs, secret, op))
+		case logging.OutputTypeSplunk:
+			outputs = MergeElements(outputs, splunk.Conf(o, inputs, secret, op))
+		}
+	}
+	return outputs
+}
+
+// OutputInputs returns the IDs of the pipeline transforms referencing an output
+func OutputInputs(clfspec *logging.ClusterLogForwarderSpec, output string) []string {
+	inputs := []string{}
+	for _, p := range clfspec.Pipelines {
+		for _, outRef := range p.OutputRefs {
+			if outRef == output {
+				inputs = append(inputs, PipelineID(p.Name))
+				break
+			}
+		}
+	}
+	return inputs
+}
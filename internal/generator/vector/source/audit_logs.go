@@ -21,7 +21,7 @@ const OpenshiftAuditLogTemplate = `
 [sources.{{.ComponentID}}]
   type = "file"
   ignore_older_secs = 600
-  include = ["/var/log/oauth-apiserver.audit.log"]
+  include = ["/var/log/oauth-apiserver/audit.log", "/var/log/openshift-apiserver/audit.log"]
 {{end}}
 `
 
@@ -38,3 +38,15 @@ const K8sAuditLogTemplate = `
 `
 
 type K8sAuditLog = generator.ConfLiteral
+
+const OVNAuditLogTemplate = `
+{{define "inputSourceOVNAuditTemplate" -}}
+# {{.Desc}}
+[sources.{{.ComponentID}}]
+  type = "file"
+  ignore_older_secs = 600
+  include = ["/var/log/ovn/acl-audit-log.log"]
+{{end}}
+`
+
+type OVNAuditLog = generator.ConfLiteral
@@ -2,14 +2,23 @@ package vector
 
 import (
 	"fmt"
+	"strings"
+
 	"github.com/openshift/cluster-logging-operator/internal/generator"
-	source2 "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/source"
 	"github.com/openshift/cluster-logging-operator/internal/generator/vector/source"
-	"strings"
 
 	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
 )
 
+const (
+	ContainerLogsID      = "container_logs"
+	JournalLogsID        = "journal_logs"
+	HostAuditLogsID      = "host_audit_logs"
+	K8sAuditLogsID       = "k8s_audit_logs"
+	OpenshiftAuditLogsID = "openshift_audit_logs"
+	OVNAuditLogsID       = "ovn_audit_logs"
+)
+
 func Sources(spec *logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
 	return generator.MergeElements(
 		LogSources(spec, op),
@@ -22,39 +31,45 @@ func LogSources(spec *logging.ClusterLogForwarderSpec, op generator.Options) []g
 	if types.Has(logging.InputNameApplication) || types.Has(logging.InputNameInfrastructure) {
 		el = append(el,
 			source.KubernetesLogs{
-				ComponentID:  "container_logs",
+				ComponentID:  ContainerLogsID,
 				Desc:         "Logs from containers (including openshift containers)",
 				ExcludePaths: ExcludeContainerPaths(),
 			})
 	}
 	if types.Has(logging.InputNameInfrastructure) {
 		el = append(el,
-			source2.JournalLog{
-				ComponentID:  "journal_logs",
+			source.JournalLog{
+				ComponentID:  JournalLogsID,
 				Desc:         "Logs from linux journal",
 				TemplateName: "inputSourceJournalTemplate",
-				TemplateStr:  source2.JournalLogTemplate,
+				TemplateStr:  source.JournalLogTemplate,
 			})
 	}
 	if types.Has(logging.InputNameAudit) {
 		el = append(el,
-			source2.HostAuditLog{
-				ComponentID:  "host_audit_logs",
+			source.HostAuditLog{
+				ComponentID:  HostAuditLogsID,
 				Desc:         "Logs from host audit",
 				TemplateName: "inputSourceHostAuditTemplate",
-				TemplateStr:  source2.HostAuditLogTemplate,
+				TemplateStr:  source.HostAuditLogTemplate,
 			},
-			source2.K8sAuditLog{
-				ComponentID:  "k8s_audit_logs",
+			source.K8sAuditLog{
+				ComponentID:  K8sAuditLogsID,
 				Desc:         "Logs from kubernetes audit",
 				TemplateName: "inputSourceK8sAuditTemplate",
-				TemplateStr:  source2.K8sAuditLogTemplate,
+				TemplateStr:  source.K8sAuditLogTemplate,
 			},
-			source2.OpenshiftAuditLog{
-				ComponentID:  "openshift_audit_logs",
+			source.OpenshiftAuditLog{
+				ComponentID:  OpenshiftAuditLogsID,
 				Desc:         "Logs from openshift audit",
 				TemplateName: "inputSourceOpenShiftAuditTemplate",
-				TemplateStr:  source2.OpenshiftAuditLogTemplate,
+				TemplateStr:  source.OpenshiftAuditLogTemplate,
+			},
+			source.OVNAuditLog{
+				ComponentID:  OVNAuditLogsID,
+				Desc:         "Logs from ovn audit",
+				TemplateName: "inputSourceOVNAuditTemplate",
+				TemplateStr:  source.OVNAuditLogTemplate,
 			})
 	}
 	return el
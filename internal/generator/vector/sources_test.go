@@ -8,7 +8,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-var _ = PDescribe("Vector Config Generation", func() {
+var _ = Describe("Vector Config Generation", func() {
 	var f = func(clspec logging.ClusterLoggingSpec, secrets map[string]*corev1.Secret, clfspec logging.ClusterLogForwarderSpec, op generator.Options) []generator.Element {
 		return generator.MergeElements(
 			LogSources(&clfspec, op),
@@ -30,6 +30,7 @@ var _ = PDescribe("Vector Config Generation", func() {
 			ExpectedConf: `
 # Logs from containers (including openshift containers)
 [sources.container_logs]
+  type = "kubernetes_logs"
   auto_partial_merge = true
   exclude_paths_glob_patterns = ["/var/log/containers/vector-*_openshift-logging_*.log", "/var/log/containers/elasticsearch-*_openshift-logging_*.log", "/var/log/containers/kibana-*_openshift-logging_*.log"]
 `,
@@ -49,6 +50,7 @@ var _ = PDescribe("Vector Config Generation", func() {
 			ExpectedConf: `
 # Logs from containers (including openshift containers)
 [sources.container_logs]
+  type = "kubernetes_logs"
   auto_partial_merge = true
   exclude_paths_glob_patterns = ["/var/log/containers/vector-*_openshift-logging_*.log", "/var/log/containers/elasticsearch-*_openshift-logging_*.log", "/var/log/containers/kibana-*_openshift-logging_*.log"]
 
@@ -85,7 +87,13 @@ var _ = PDescribe("Vector Config Generation", func() {
 [sources.openshift_audit_logs]
   type = "file"
   ignore_older_secs = 600
-  include = ["/var/log/oauth-apiserver.audit.log"]
+  include = ["/var/log/oauth-apiserver/audit.log", "/var/log/openshift-apiserver/audit.log"]
+
+# Logs from ovn audit
+[sources.ovn_audit_logs]
+  type = "file"
+  ignore_older_secs = 600
+  include = ["/var/log/ovn/acl-audit-log.log"]
 `,
 		}),
 		Entry("All Log Sources", generator.ConfGenerateTest{
@@ -105,6 +113,7 @@ var _ = PDescribe("Vector Config Generation", func() {
 			ExpectedConf: `
 # Logs from containers (including openshift containers)
 [sources.container_logs]
+  type = "kubernetes_logs"
   auto_partial_merge = true
   exclude_paths_glob_patterns = ["/var/log/containers/vector-*_openshift-logging_*.log", "/var/log/containers/elasticsearch-*_openshift-logging_*.log", "/var/log/containers/kibana-*_openshift-logging_*.log"]
 
@@ -127,6 +136,13 @@ var _ = PDescribe("Vector Config Generation", func() {
 [sources.openshift_audit_logs]
   type = "file"
   ignore_older_secs = 600
-  include = ["/var/log/oauth-apiserver.audit.log"]`,
+  include = ["/var/log/oauth-apiserver/audit.log", "/var/log/openshift-apiserver/audit.log"]
+
+# Logs from ovn audit
+[sources.ovn_audit_logs]
+  type = "file"
+  ignore_older_secs = 600
+  include = ["/var/log/ovn/acl-audit-log.log"]
+`,
 		}))
 })
@@ -24,6 +24,10 @@ type ClusterLoggingRequest struct {
 	// ForwarderSpec is the normalized and sanitized logforwarder spec
 	ForwarderSpec logging.ClusterLogForwarderSpec
 
+	// ForwarderInstances are the ClusterLogForwarders other than the singleton and the
+	// LogForwarders. Their normalized pipelines are merged into ForwarderSpec.
+	ForwarderInstances []*logging.ClusterLogForwarder
+
 	// OutputSecrets are retrieved during validation and used for generation.
 	OutputSecrets map[string]*corev1.Secret
 
@@ -36,6 +40,9 @@ type ClusterLoggingRequest struct {
 	//CLFVerifier is a collection of functions to control verification
 	//of ClusterLogForwarding
 	CLFVerifier ClusterLogForwarderVerifier
+
+	// forwarderInstance is the forwarder instance being normalized, nil for the singleton
+	forwarderInstance *logging.ClusterLogForwarder
 }
 
 type ClusterLogForwarderVerifier struct {
@@ -47,7 +47,7 @@ func (clusterRequest *ClusterLoggingRequest) CreateOrUpdateCollection() (err err
 
 	// there is no easier way to check this in golang without writing a helper function
 	// TODO: write a helper function to validate Type is a valid option for common setup or tear down
-	if cluster.Spec.Collection != nil && cluster.Spec.Collection.Logs.Type == logging.LogCollectionTypeFluentd {
+	if cluster.Spec.Collection != nil && (cluster.Spec.Collection.Logs.Type == logging.LogCollectionTypeFluentd || cluster.Spec.Collection.Logs.Type == logging.LogCollectionTypeVector) {
 
 		//TODO: Remove me once fully migrated to new collector naming
 		if err = clusterRequest.removeCollector(constants.FluentdName); err != nil {
@@ -84,15 +84,16 @@ func (clusterRequest *ClusterLoggingRequest) CreateOrUpdateCollection() (err err
 			return
 		}
 
-		if err = clusterRequest.createOrUpdateFluentdPrometheusRule(); err != nil {
+		if clusterRequest.isVectorCollector() {
+			// The fluentd alerts do not apply to vector
+			if err = clusterRequest.RemovePrometheusRule(constants.CollectorName); err != nil {
+				return
+			}
+		} else if err = clusterRequest.createOrUpdateFluentdPrometheusRule(); err != nil {
 			log.Error(err, "unable to create or update fluentd prometheus rule")
 		}
 
-		if err = clusterRequest.createOrUpdateFluentdConfigMap(collectorConfig); err != nil {
-			return
-		}
-
-		if err = clusterRequest.createOrUpdateFluentdDaemonset(collectorConfHash); err != nil {
+		if err = clusterRequest.reconcileFluentdConfig(collectorConfig, collectorConfHash); err != nil {
 			return
 		}
 
@@ -126,6 +127,38 @@ func (clusterRequest *ClusterLoggingRequest) CreateOrUpdateCollection() (err err
 	return nil
 }
 
+// reconcileFluentdConfig rolls out the collector configuration according to the rollout policy,
+// or only previews it in dry-run mode
+func (clusterRequest *ClusterLoggingRequest) reconcileFluentdConfig(collectorConfig, collectorConfHash string) error {
+	if clusterRequest.isDryRun() {
+		// Preview the configuration without rolling it out to the collectors
+		return clusterRequest.createOrUpdatePreviewConfigMap(collectorConfig)
+	}
+	if err := clusterRequest.RemoveConfigMap(CollectorPreviewName); err != nil {
+		return err
+	}
+
+	if policy := clusterRequest.rolloutPolicy(); policy != nil {
+		return clusterRequest.rolloutFluentd(policy, collectorConfig, collectorConfHash)
+	}
+	if err := clusterRequest.removeFluentdCanary(); err != nil {
+		return err
+	}
+
+	if clusterRequest.isVectorCollector() {
+		if err := clusterRequest.createOrUpdateVectorConfigMap(collectorConfig); err != nil {
+			return err
+		}
+		return clusterRequest.createOrUpdateVectorDaemonset(collectorConfHash)
+	}
+
+	if err := clusterRequest.createOrUpdateFluentdConfigMap(constants.CollectorName, collectorConfig); err != nil {
+		return err
+	}
+
+	return clusterRequest.createOrUpdateFluentdDaemonset(collectorConfHash)
+}
+
 func (clusterRequest *ClusterLoggingRequest) UpdateFluentdStatus() (err error) {
 
 	cluster := clusterRequest.Cluster
@@ -155,6 +155,62 @@ var _ = Describe("Reconciling", func() {
 				Expect(ds.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(trustedCABundleVolumeMount))
 			})
 		})
+
+		Context("when the collector is vector", func() {
+			var (
+				collectorKey = types.NamespacedName{Name: constants.CollectorName, Namespace: cluster.GetNamespace()}
+			)
+			BeforeEach(func() {
+				vectorCluster := cluster.DeepCopy()
+				vectorCluster.Spec.Collection.Logs.Type = loggingv1.LogCollectionTypeVector
+				client = fake.NewFakeClient(
+					vectorCluster,
+					fluentdSecret,
+					fluentdCABundle,
+				)
+				forwarder := &loggingv1.ClusterLogForwarder{
+					ObjectMeta: metav1.ObjectMeta{Name: constants.SingletonName, Namespace: cluster.GetNamespace()},
+					Spec: loggingv1.ClusterLogForwarderSpec{
+						Outputs: []loggingv1.OutputSpec{
+							{Name: "es", Type: loggingv1.OutputTypeElasticsearch, URL: "http://es.svc:9200"},
+						},
+						Pipelines: []loggingv1.PipelineSpec{
+							{Name: "apps", InputRefs: []string{loggingv1.InputNameApplication}, OutputRefs: []string{"es"}},
+						},
+					},
+				}
+				clusterRequest = &ClusterLoggingRequest{
+					Client:           client,
+					Cluster:          vectorCluster,
+					ForwarderRequest: forwarder,
+					ForwarderSpec:    forwarder.Spec,
+				}
+			})
+
+			It("should deploy vector with the generated vector.toml", func() {
+				Expect(clusterRequest.CreateOrUpdateCollection()).To(Succeed())
+
+				cm := &corev1.ConfigMap{}
+				Expect(client.Get(context.TODO(), collectorKey, cm)).To(Succeed())
+				Expect(cm.Data).To(HaveKey(vectorConfigKey))
+				Expect(cm.Data[vectorConfigKey]).To(ContainSubstring(`type = "elasticsearch"`))
+				Expect(cm.Data).NotTo(HaveKey("fluent.conf"))
+
+				ds := &appsv1.DaemonSet{}
+				Expect(client.Get(context.TODO(), collectorKey, ds)).To(Succeed())
+				container := ds.Spec.Template.Spec.Containers[0]
+				Expect(container.Command).To(Equal([]string{"vector", "--config-toml", "/etc/vector/vector.toml"}))
+				Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: config, ReadOnly: true, MountPath: vectorConfigValue}))
+				envNames := []string{}
+				for _, env := range container.Env {
+					envNames = append(envNames, env.Name)
+				}
+				Expect(envNames).To(ContainElement(vectorSelfNodeEnvVar))
+				Expect(envNames).To(ContainElement(vectorConfHashEnvVar))
+
+				Expect(client.Get(context.TODO(), types.NamespacedName{Name: constants.CollectorName, Namespace: cluster.GetNamespace()}, &monitoringv1.PrometheusRule{})).NotTo(Succeed())
+			})
+		})
 	})
 })
 
@@ -75,6 +75,11 @@ const (
 	metricsVolumeValue         = "/etc/fluent/metrics"
 	tmp                        = "tmp"
 	tmpValue                   = "/tmp"
+
+	// serviceAccountTokenAudience is the audience of the projected service account token, it must be
+	// trusted by the identity provider of the cloud platform (e.g. the AWS IAM OIDC provider)
+	serviceAccountTokenAudience          = "openshift"
+	serviceAccountTokenExpirationSeconds = 3600
 )
 
 func (clusterRequest *ClusterLoggingRequest) removeCollector(name string) (err error) {
@@ -105,6 +110,10 @@ func (clusterRequest *ClusterLoggingRequest) removeCollector(name string) (err e
 			return
 		}
 
+		if err = clusterRequest.removeFluentdCanary(); err != nil {
+			return
+		}
+
 		// Wait longer than the terminationGracePeriodSeconds
 		time.Sleep(12 * time.Second)
 
@@ -197,6 +206,12 @@ func (clusterRequest *ClusterLoggingRequest) reconcileFluentdServiceMonitor() er
 		},
 	}
 
+	endpoints := []monitoringv1.Endpoint{endpoint, logMetricExporterEndpoint}
+	if clusterRequest.isVectorCollector() {
+		// vector does not serve the fluentd metrics
+		endpoints = []monitoringv1.Endpoint{logMetricExporterEndpoint}
+	}
+
 	labelSelector := metav1.LabelSelector{
 		MatchLabels: map[string]string{
 			"logging-infra": "support",
@@ -205,7 +220,7 @@ func (clusterRequest *ClusterLoggingRequest) reconcileFluentdServiceMonitor() er
 
 	desired.Spec = monitoringv1.ServiceMonitorSpec{
 		JobLabel:  "monitor-collector",
-		Endpoints: []monitoringv1.Endpoint{endpoint, logMetricExporterEndpoint},
+		Endpoints: endpoints,
 		Selector:  labelSelector,
 		NamespaceSelector: monitoringv1.NamespaceSelector{
 			MatchNames: []string{cluster.Namespace},
@@ -331,14 +346,19 @@ func (clusterRequest *ClusterLoggingRequest) useOldRemoteSyslogPlugin() bool {
 	return found && enabled == "enabled"
 }
 
-func (clusterRequest *ClusterLoggingRequest) createOrUpdateFluentdConfigMap(fluentConf string) error {
+func (clusterRequest *ClusterLoggingRequest) createOrUpdateFluentdConfigMap(configMapName, fluentConf string) error {
+	return clusterRequest.createOrUpdateCollectorConfigMap(configMapName, map[string]string{
+		"fluent.conf": fluentConf,
+		"run.sh":      fluentd.RunScript,
+	})
+}
+
+// createOrUpdateCollectorConfigMap creates the configmap of the collector or updates its data
+func (clusterRequest *ClusterLoggingRequest) createOrUpdateCollectorConfigMap(configMapName string, data map[string]string) error {
 	fluentdConfigMap := NewConfigMap(
-		constants.CollectorName,
+		configMapName,
 		clusterRequest.Cluster.Namespace,
-		map[string]string{
-			"fluent.conf": fluentConf,
-			"run.sh":      fluentd.RunScript,
-		},
+		data,
 	)
 
 	utils.AddOwnerRefToObject(fluentdConfigMap, utils.AsOwner(clusterRequest.Cluster))
@@ -455,6 +475,28 @@ func newFluentdPodSpec(cluster *logging.ClusterLogging, trustedCABundleCM *v1.Co
 	proxyEnv := utils.GetProxyEnvVars()
 	fluentdContainer.Env = append(fluentdContainer.Env, proxyEnv...)
 
+	// The Google Cloud Logging output plugin reads the service account credentials from the application default
+	// credentials file, all Google Cloud Logging outputs use the same secret
+	for _, o := range pipelineSpec.Outputs {
+		if o.Type == logging.OutputTypeGoogleCloudLogging && o.Secret != nil {
+			fluentdContainer.Env = append(fluentdContainer.Env, v1.EnvVar{
+				Name:  "GOOGLE_APPLICATION_CREDENTIALS",
+				Value: fmt.Sprintf("%s/%s/%s", constants.CollectorSecretsDir, o.Secret.Name, constants.GoogleApplicationCredentialsKey),
+			})
+			break
+		}
+	}
+
+	envNames := sets.NewString()
+	for _, env := range fluentdContainer.Env {
+		envNames.Insert(env.Name)
+	}
+	for _, env := range collectionSpec.Logs.FluentdSpec.Env {
+		if !envNames.Has(env.Name) {
+			fluentdContainer.Env = append(fluentdContainer.Env, env)
+		}
+	}
+
 	fluentdContainer.VolumeMounts = []v1.VolumeMount{
 		{Name: logContainers, ReadOnly: true, MountPath: logContainersValue},
 		{Name: logPods, ReadOnly: true, MountPath: logPodsValue},
@@ -498,6 +540,21 @@ func newFluentdPodSpec(cluster *logging.ClusterLogging, trustedCABundleCM *v1.Co
 		fluentdContainer.VolumeMounts = append(fluentdContainer.VolumeMounts, v1.VolumeMount{Name: name, ReadOnly: true, MountPath: path})
 	}
 
+	// Outputs assuming an AWS role use the projected service account token as web identity
+	addServiceAccountTokenVolume := false
+	for _, o := range pipelineSpec.Outputs {
+		if o.Type == logging.OutputTypeCloudwatch {
+			addServiceAccountTokenVolume = true
+			fluentdContainer.VolumeMounts = append(fluentdContainer.VolumeMounts,
+				v1.VolumeMount{
+					Name:      constants.CollectorServiceAccountTokenVolume,
+					ReadOnly:  true,
+					MountPath: constants.CollectorServiceAccountTokenDir,
+				})
+			break
+		}
+	}
+
 	addTrustedCAVolume := false
 	// If trusted CA bundle ConfigMap exists and its hash value is non-zero, mount the bundle.
 	if trustedCABundleCM != nil && hasTrustedCABundle(trustedCABundleCM) {
@@ -573,6 +630,26 @@ func newFluentdPodSpec(cluster *logging.ClusterLogging, trustedCABundleCM *v1.Co
 		fluentdPodSpec.Volumes = append(fluentdPodSpec.Volumes, v1.Volume{Name: name, VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: name}}})
 	}
 
+	if addServiceAccountTokenVolume {
+		fluentdPodSpec.Volumes = append(fluentdPodSpec.Volumes,
+			v1.Volume{
+				Name: constants.CollectorServiceAccountTokenVolume,
+				VolumeSource: v1.VolumeSource{
+					Projected: &v1.ProjectedVolumeSource{
+						Sources: []v1.VolumeProjection{
+							{
+								ServiceAccountToken: &v1.ServiceAccountTokenProjection{
+									Audience:          serviceAccountTokenAudience,
+									ExpirationSeconds: utils.GetInt64(serviceAccountTokenExpirationSeconds),
+									Path:              constants.BearerTokenFileKey,
+								},
+							},
+						},
+					},
+				},
+			})
+	}
+
 	if addTrustedCAVolume {
 		fluentdPodSpec.Volumes = append(fluentdPodSpec.Volumes,
 			v1.Volume{
@@ -594,6 +671,10 @@ func newFluentdPodSpec(cluster *logging.ClusterLogging, trustedCABundleCM *v1.Co
 	}
 
 	fluentdPodSpec.PriorityClassName = clusterLoggingPriorityClassName
+	if collectionSpec.Logs.FluentdSpec.PriorityClassName != "" {
+		fluentdPodSpec.PriorityClassName = collectionSpec.Logs.FluentdSpec.PriorityClassName
+	}
+	fluentdPodSpec.Affinity = collectionSpec.Logs.FluentdSpec.Affinity
 	// Shorten the termination grace period from the default 30 sec to 10 sec.
 	fluentdPodSpec.TerminationGracePeriodSeconds = utils.GetInt64(10)
 
@@ -668,24 +749,35 @@ func newFluentdInitContainer(cluster *logging.ClusterLogging) v1.Container {
 }
 
 func (clusterRequest *ClusterLoggingRequest) createOrUpdateFluentdDaemonset(pipelineConfHash string) (err error) {
+	fluentdDaemonset, err := clusterRequest.newFluentdDaemonset(pipelineConfHash)
+	if err != nil {
+		return err
+	}
+	return clusterRequest.applyFluentdDaemonset(fluentdDaemonset)
+}
+
+// newFluentdDaemonset returns the desired collector daemonset running the configuration with the given hash
+func (clusterRequest *ClusterLoggingRequest) newFluentdDaemonset(pipelineConfHash string) (*apps.DaemonSet, error) {
 
 	cluster := clusterRequest.Cluster
 
-	var fluentdTrustBundle *v1.ConfigMap
 	// Create or update cluster proxy trusted CA bundle.
-	fluentdTrustBundle, err = clusterRequest.createOrGetTrustedCABundleConfigMap(constants.CollectorTrustedCAName)
+	fluentdTrustBundle, err := clusterRequest.createOrGetTrustedCABundleConfigMap(constants.CollectorTrustedCAName)
 	if err != nil {
-		return
+		return nil, err
 	}
 
 	fluentdPodSpec := newFluentdPodSpec(cluster, fluentdTrustBundle, clusterRequest.ForwarderSpec)
 
 	fluentdDaemonset := NewDaemonSet(constants.CollectorName, cluster.Namespace, constants.CollectorName, constants.CollectorName, fluentdPodSpec)
 	fluentdDaemonset.Spec.Template.Spec.Containers[0].Env = updateEnvVar(v1.EnvVar{Name: "FLUENT_CONF_HASH", Value: pipelineConfHash}, fluentdDaemonset.Spec.Template.Spec.Containers[0].Env)
+	if cluster.Spec.Collection != nil {
+		customizeFluentdDaemonset(fluentdDaemonset, cluster.Spec.Collection.Logs.FluentdSpec)
+	}
 
 	trustedCAHashValue, err := clusterRequest.getTrustedCABundleHash()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	fluentdDaemonset.Spec.Template.Annotations[constants.TrustedCABundleHashName] = trustedCAHashValue
 
@@ -698,6 +790,35 @@ func (clusterRequest *ClusterLoggingRequest) createOrUpdateFluentdDaemonset(pipe
 		utils.AddOwnerRefToObject(fluentdDaemonset, NewLogCollectorServiceAccountRef(uid))
 	}
 
+	return fluentdDaemonset, nil
+}
+
+// customizeFluentdDaemonset applies the update strategy and the extra pod labels and annotations of the spec
+// to the collector daemonset
+func customizeFluentdDaemonset(fluentdDaemonset *apps.DaemonSet, fluentdSpec logging.FluentdSpec) {
+	if fluentdSpec.UpdateStrategy != nil {
+		fluentdDaemonset.Spec.UpdateStrategy = *fluentdSpec.UpdateStrategy.DeepCopy()
+	}
+
+	template := &fluentdDaemonset.Spec.Template
+	// The template labels are shared with the selector, which must not change
+	labels := map[string]string{}
+	for key, value := range fluentdSpec.PodLabels {
+		labels[key] = value
+	}
+	for key, value := range template.Labels {
+		labels[key] = value
+	}
+	template.Labels = labels
+	for key, value := range fluentdSpec.PodAnnotations {
+		if _, found := template.Annotations[key]; !found {
+			template.Annotations[key] = value
+		}
+	}
+}
+
+// applyFluentdDaemonset creates the collector daemonset or updates it when managed
+func (clusterRequest *ClusterLoggingRequest) applyFluentdDaemonset(fluentdDaemonset *apps.DaemonSet) (err error) {
 	err = clusterRequest.Create(fluentdDaemonset)
 	if err != nil && !errors.IsAlreadyExists(err) {
 		return fmt.Errorf("Failure creating collector Daemonset %v", err)
@@ -732,7 +853,7 @@ func (clusterRequest *ClusterLoggingRequest) updateFluentdDaemonsetIfRequired(de
 		current.Spec.Template.Spec.Containers[0].Env = updateEnvVar(v1.EnvVar{Name: "FLUSH_AT_SHUTDOWN", Value: "True"}, current.Spec.Template.Spec.Containers[0].Env)
 	}
 	trustedCABundleHashAreSame := current.Spec.Template.Annotations[constants.TrustedCABundleHashName] == desired.Spec.Template.Annotations[constants.TrustedCABundleHashName]
-	if !daemonsets.AreSame(current, desired) || !trustedCABundleHashAreSame {
+	if !daemonsets.AreSame(current, desired) || !trustedCABundleHashAreSame || !isRolloutStateSame(current, desired) {
 		log.V(3).Info("Current and desired collectors are different, updating DaemonSet", "DaemonSet", current.Name)
 		if flushBuffer {
 			log.Info("Updating and restarting collector pods to flush its buffers...")
@@ -746,8 +867,13 @@ func (clusterRequest *ClusterLoggingRequest) updateFluentdDaemonsetIfRequired(de
 				return fmt.Errorf("Timed out waiting for collector to be ready")
 			}
 		}
+		labels, annotations := current.Spec.Template.Labels, current.Spec.Template.Annotations
 		current.Spec = desired.Spec
-		if err = clusterRequest.Update(desired); err != nil {
+		// keep the pod template labels and annotations not set by the operator
+		current.Spec.Template.Labels = mergeMaps(labels, desired.Spec.Template.Labels)
+		current.Spec.Template.Annotations = mergeMaps(annotations, desired.Spec.Template.Annotations)
+		setRolloutState(current, desired)
+		if err = clusterRequest.Update(current); err != nil {
 			return err
 		}
 	}
@@ -755,6 +881,18 @@ func (clusterRequest *ClusterLoggingRequest) updateFluentdDaemonsetIfRequired(de
 	return nil
 }
 
+// mergeMaps returns the entries of current overridden by the entries of desired
+func mergeMaps(current, desired map[string]string) map[string]string {
+	merged := map[string]string{}
+	for key, value := range current {
+		merged[key] = value
+	}
+	for key, value := range desired {
+		merged[key] = value
+	}
+	return merged
+}
+
 func (clusterRequest *ClusterLoggingRequest) getTrustedCABundleHash() (string, error) {
 
 	fluentdTrustBundle := &v1.ConfigMap{}
@@ -795,7 +933,7 @@ func (clusterRequest *ClusterLoggingRequest) RestartFluentd() (err error) {
 		return
 	}
 
-	if err = clusterRequest.createOrUpdateFluentdDaemonset(collectorConfHash); err != nil {
+	if err = clusterRequest.reconcileFluentdConfig(collectorConfig, collectorConfHash); err != nil {
 		return
 	}
 
@@ -1,7 +1,9 @@
 package k8shandler
 
 import (
+	"context"
 	"os"
+	"path"
 	"reflect"
 	"strconv"
 	"testing"
@@ -12,10 +14,15 @@ import (
 
 	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
 	"github.com/openshift/cluster-logging-operator/internal/constants"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd"
 	"github.com/openshift/cluster-logging-operator/internal/utils"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = Describe("fluentd.go#newFluentdPodSpec", func() {
@@ -36,10 +43,148 @@ var _ = Describe("fluentd.go#newFluentdPodSpec", func() {
 					FieldRef: &v1.ObjectFieldSelector{
 						APIVersion: "v1", FieldPath: "status.podIP"}}}))
 		})
+
+		It("should not mount the service account token without cloudwatch outputs", func() {
+			for _, v := range podSpec.Volumes {
+				Expect(v.Name).ToNot(Equal(constants.CollectorServiceAccountTokenVolume))
+			}
+		})
+
+		It("should mount the projected service account token for cloudwatch outputs", func() {
+			podSpec = newFluentdPodSpec(cluster, nil, logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{Name: "cw", Type: logging.OutputTypeCloudwatch},
+				},
+			})
+			Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(v1.VolumeMount{
+				Name:      constants.CollectorServiceAccountTokenVolume,
+				ReadOnly:  true,
+				MountPath: constants.CollectorServiceAccountTokenDir,
+			}))
+			var volume *v1.Volume
+			for i, v := range podSpec.Volumes {
+				if v.Name == constants.CollectorServiceAccountTokenVolume {
+					volume = &podSpec.Volumes[i]
+				}
+			}
+			Expect(volume).ToNot(BeNil())
+			Expect(volume.Projected.Sources[0].ServiceAccountToken.Path).To(Equal("token"))
+		})
+
+		It("should provide the credentials of google cloud logging outputs as application default credentials", func() {
+			podSpec = newFluentdPodSpec(cluster, nil, logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{Name: "gcl", Type: logging.OutputTypeGoogleCloudLogging, Secret: &logging.OutputSecretSpec{Name: "gcl-secret"}},
+				},
+			})
+			Expect(podSpec.Containers[0].Env).To(IncludeEnvVar(v1.EnvVar{
+				Name:  "GOOGLE_APPLICATION_CREDENTIALS",
+				Value: "/var/run/ocp-collector/secrets/gcl-secret/google-application-credentials.json",
+			}))
+		})
+	})
+
+	Describe("when customizing the collector pods", func() {
+		var (
+			fluentdSpec logging.FluentdSpec
+		)
+		BeforeEach(func() {
+			maxUnavailable := intstr.FromString("25%")
+			fluentdSpec = logging.FluentdSpec{
+				UpdateStrategy: &appsv1.DaemonSetUpdateStrategy{
+					Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable},
+				},
+				PodAnnotations: map[string]string{
+					"example.com/team":                           "logging",
+					"scheduler.alpha.kubernetes.io/critical-pod": "false",
+				},
+				PodLabels: map[string]string{
+					"example.com/team": "logging",
+					"component":        "other",
+				},
+				Affinity: &v1.Affinity{
+					NodeAffinity: &v1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+							NodeSelectorTerms: []v1.NodeSelectorTerm{
+								{MatchExpressions: []v1.NodeSelectorRequirement{{Key: "example.com/logs", Operator: v1.NodeSelectorOpExists}}},
+							},
+						},
+					},
+				},
+				PriorityClassName: "system-node-critical",
+				Env: []v1.EnvVar{
+					{Name: "LOG_LEVEL", Value: "debug"},
+					{Name: "POD_IP", Value: "127.0.0.1"},
+				},
+			}
+		})
+
+		It("should add the affinity, priority class and extra environment to the pod spec", func() {
+			podSpec = newFluentdPodSpec(&logging.ClusterLogging{
+				Spec: logging.ClusterLoggingSpec{
+					Collection: &logging.CollectionSpec{
+						Logs: logging.LogCollectionSpec{Type: logging.LogCollectionTypeFluentd, FluentdSpec: fluentdSpec},
+					},
+				},
+			}, nil, logging.ClusterLogForwarderSpec{})
+			Expect(podSpec.Affinity).To(Equal(fluentdSpec.Affinity))
+			Expect(podSpec.PriorityClassName).To(Equal("system-node-critical"))
+			Expect(podSpec.Containers[0].Env).To(IncludeEnvVar(v1.EnvVar{Name: "LOG_LEVEL", Value: "debug"}))
+			Expect(podSpec.Containers[0].Env).ToNot(IncludeEnvVar(v1.EnvVar{Name: "POD_IP", Value: "127.0.0.1"}), "Exp. the variables of the operator to take precedence")
+		})
+
+		It("should default the priority class", func() {
+			Expect(podSpec.PriorityClassName).To(Equal(clusterLoggingPriorityClassName))
+			Expect(podSpec.Affinity).To(BeNil())
+		})
+
+		It("should apply the update strategy and extra pod labels and annotations to the daemonset", func() {
+			ds := NewDaemonSet(constants.CollectorName, constants.OpenshiftNS, constants.CollectorName, constants.CollectorName, v1.PodSpec{})
+			customizeFluentdDaemonset(ds, fluentdSpec)
+			Expect(ds.Spec.UpdateStrategy).To(Equal(*fluentdSpec.UpdateStrategy))
+			Expect(ds.Spec.Template.Labels).To(HaveKeyWithValue("example.com/team", "logging"))
+			Expect(ds.Spec.Template.Labels).To(HaveKeyWithValue("component", constants.CollectorName), "Exp. the labels of the operator to take precedence")
+			Expect(ds.Spec.Selector.MatchLabels).ToNot(HaveKey("example.com/team"))
+			Expect(ds.Labels).ToNot(HaveKey("example.com/team"))
+			Expect(ds.Spec.Template.Annotations).To(HaveKeyWithValue("example.com/team", "logging"))
+			Expect(ds.Spec.Template.Annotations).To(HaveKeyWithValue("scheduler.alpha.kubernetes.io/critical-pod", ""))
+		})
 	})
 
 })
 
+var _ = Describe("fluentd.go#updateFluentdDaemonsetIfRequired", func() {
+	defer GinkgoRecover()
+
+	It("should keep the pod template labels and annotations not set by the operator", func() {
+		podSpec := v1.PodSpec{Containers: []v1.Container{{Name: constants.CollectorName, Image: "fluentd"}}}
+		current := NewDaemonSet(constants.CollectorName, constants.OpenshiftNS, constants.CollectorName, constants.CollectorName, podSpec)
+		current.Spec.Template.Labels["example.com/team"] = "logging"
+		current.Spec.Template.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": "2021-01-01T00:00:00Z"}
+		fakeClient := fake.NewFakeClient(current.DeepCopy())
+		clusterRequest := &ClusterLoggingRequest{
+			Client:  fakeClient,
+			Cluster: &logging.ClusterLogging{ObjectMeta: metav1.ObjectMeta{Name: constants.SingletonName, Namespace: constants.OpenshiftNS}},
+		}
+
+		desired := NewDaemonSet(constants.CollectorName, constants.OpenshiftNS, constants.CollectorName, constants.CollectorName, *podSpec.DeepCopy())
+		desired.Spec.Template.Spec.Containers[0].Image = "fluentd-next"
+		desired.Spec.Template.Annotations = map[string]string{constants.TrustedCABundleHashName: "abc"}
+		Expect(clusterRequest.updateFluentdDaemonsetIfRequired(desired)).To(Succeed())
+
+		updated := &appsv1.DaemonSet{}
+		Expect(fakeClient.Get(context.TODO(), types.NamespacedName{Name: constants.CollectorName, Namespace: constants.OpenshiftNS}, updated)).To(Succeed())
+		Expect(updated.Spec.Template.Spec.Containers[0].Image).To(Equal("fluentd-next"))
+		Expect(updated.Spec.Template.Labels).To(HaveKeyWithValue("example.com/team", "logging"))
+		Expect(updated.Spec.Template.Labels).To(HaveKeyWithValue("component", constants.CollectorName))
+		Expect(updated.Spec.Template.Annotations).To(Equal(map[string]string{
+			"kubectl.kubernetes.io/restartedAt": "2021-01-01T00:00:00Z",
+			constants.TrustedCABundleHashName:   "abc",
+		}))
+	})
+})
+
 func TestNewFluentdPodSpecWhenFieldsAreUndefined(t *testing.T) {
 
 	cluster := &logging.ClusterLogging{}
@@ -459,3 +604,18 @@ func checkFluentdForwarderEnvVar(t *testing.T, podSpec v1.PodSpec, name string,
 		t.Errorf("EnvVar %s not found", name)
 	}
 }
+
+func TestFluentdPrometheusRuleRecordsRateLimitDroppedRecords(t *testing.T) {
+	spec, err := NewPrometheusRuleSpecFrom(path.Join("..", "..", "files", fluentdAlertsFile))
+	if err != nil {
+		t.Fatalf("Unexpected error loading the prometheus rule: %v", err)
+	}
+	for _, group := range spec.Groups {
+		for _, rule := range group.Rules {
+			if rule.Record == fluentd.RateLimitDroppedRecordTotal {
+				return
+			}
+		}
+	}
+	t.Errorf("Exp. a recording rule for %s", fluentd.RateLimitDroppedRecordTotal)
+}
@@ -0,0 +1,86 @@
+package k8shandler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ValidateClusterLogForwarder verifies the inputs, outputs and pipelines of a ClusterLogForwarder
+// the same way they are verified when generating the collector configuration.
+func ValidateClusterLogForwarder(forwarder *logging.ClusterLogForwarder, requestClient client.Client) error {
+	instance := forwarder.DeepCopy()
+	instance.Kind = logging.ClusterLogForwarderKind
+	return validateForwarder(instance, requestClient)
+}
+
+// ValidateLogForwarder verifies the inputs, outputs and pipelines of a LogForwarder the same way
+// they are verified when generating the collector configuration.
+func ValidateLogForwarder(forwarder *logging.LogForwarder, requestClient client.Client) error {
+	return validateForwarder(&logging.ClusterLogForwarder{
+		TypeMeta:   metav1.TypeMeta{Kind: logging.LogForwarderKind, APIVersion: forwarder.APIVersion},
+		ObjectMeta: *forwarder.ObjectMeta.DeepCopy(),
+		Spec:       *forwarder.Spec.DeepCopy(),
+	}, requestClient)
+}
+
+// validateForwarder returns an error listing the invalid and degraded inputs, outputs and pipelines
+// of a forwarder, nil if there are none.
+func validateForwarder(instance *logging.ClusterLogForwarder, requestClient client.Client) error {
+	clusterRequest := &ClusterLoggingRequest{
+		Client:           requestClient,
+		ForwarderRequest: instance,
+		ForwarderSpec:    instance.Spec,
+	}
+	if instance.Kind == logging.LogForwarderKind || instance.Name != constants.SingletonName {
+		clusterRequest.forwarderInstance = instance
+	}
+	clusterRequest.CLFVerifier.VerifyOutputSecret = clusterRequest.verifyOutputSecret
+
+	clusterRequest.Cluster = clusterRequest.getClusterLogging()
+	if clusterRequest.Cluster == nil {
+		// Without a ClusterLogging instance yet, assume the default log store will be available.
+		clusterRequest.Cluster = &logging.ClusterLogging{
+			ObjectMeta: metav1.ObjectMeta{Name: constants.SingletonName, Namespace: constants.OpenshiftNS},
+			Spec: logging.ClusterLoggingSpec{
+				LogStore: &logging.LogStoreSpec{Type: logging.LogStoreTypeElasticsearch},
+			},
+		}
+	}
+
+	spec := &logging.ClusterLogForwarderSpec{}
+	status := &logging.ClusterLogForwarderStatus{}
+	clusterRequest.verifyInputs(spec, status)
+	clusterRequest.verifyOutputs(spec, status)
+	clusterRequest.verifyPipelines(spec, status)
+
+	msgs := []string{}
+	for _, named := range []struct {
+		what  string
+		conds logging.NamedConditions
+	}{
+		{"input", status.Inputs},
+		{"output", status.Outputs},
+		{"pipeline", status.Pipelines},
+	} {
+		for _, name := range sets.StringKeySet(named.conds).List() {
+			for _, cond := range named.conds[name] {
+				if (cond.Type == logging.ConditionReady && cond.Status == corev1.ConditionFalse) ||
+					(cond.Type == logging.ConditionDegraded && cond.Status == corev1.ConditionTrue) {
+					msgs = append(msgs, fmt.Sprintf("%s %s: %s", named.what, name, cond.Message))
+				}
+			}
+		}
+	}
+	if len(msgs) > 0 {
+		return errors.New(strings.Join(msgs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Validating a forwarder", func() {
+	var forwarder *logging.ClusterLogForwarder
+	BeforeEach(func() {
+		_ = logging.SchemeBuilder.AddToScheme(scheme.Scheme)
+		forwarder = &logging.ClusterLogForwarder{
+			ObjectMeta: metav1.ObjectMeta{Name: constants.SingletonName, Namespace: constants.OpenshiftNS},
+			Spec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{Name: "es", Type: logging.OutputTypeElasticsearch, URL: "http://es.svc:9200"},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{Name: "apps", InputRefs: []string{logging.InputNameApplication}, OutputRefs: []string{"es", logging.OutputNameDefault}},
+				},
+			},
+		}
+	})
+
+	It("should accept a valid forwarder without a ClusterLogging instance", func() {
+		Expect(ValidateClusterLogForwarder(forwarder, fake.NewFakeClient())).To(Succeed())
+	})
+
+	It("should list the invalid and degraded inputs, outputs and pipelines", func() {
+		forwarder.Spec.Inputs = []logging.InputSpec{{Name: logging.InputNameAudit, Audit: &logging.Audit{}}}
+		forwarder.Spec.Outputs = append(forwarder.Spec.Outputs,
+			logging.OutputSpec{Name: "es", Type: logging.OutputTypeElasticsearch, URL: "http://other.svc:9200"},
+			logging.OutputSpec{Name: "bad-url", Type: logging.OutputTypeElasticsearch, URL: "es.svc:9200"},
+			logging.OutputSpec{Name: "tls", Type: logging.OutputTypeElasticsearch, URL: "https://es.svc:9200", Secret: &logging.OutputSecretSpec{Name: "es-secret"}},
+		)
+		forwarder.Spec.Pipelines[0].InputRefs = append(forwarder.Spec.Pipelines[0].InputRefs, "nosuchinput")
+		err := ValidateClusterLogForwarder(forwarder, fake.NewFakeClient())
+		Expect(err).To(MatchError(`input input_0_: input name "audit" is reserved; ` +
+			`output bad-url: invalid URL: no host: "es.svc:9200"; ` +
+			`output output_1_: duplicate name: "es"; ` +
+			`output tls: secret "es-secret" not found; ` +
+			`pipeline apps: invalid: unrecognized inputs: [nosuchinput]`))
+	})
+
+	It("should find secrets in the operator namespace", func() {
+		forwarder.Spec.Outputs[0].URL = "https://es.svc:9200"
+		forwarder.Spec.Outputs[0].Secret = &logging.OutputSecretSpec{Name: "es-secret"}
+		client := fake.NewFakeClient(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "es-secret", Namespace: constants.OpenshiftNS},
+			Data:       map[string][]byte{"ca-bundle.crt": []byte("ca")},
+		})
+		Expect(ValidateClusterLogForwarder(forwarder, client)).To(Succeed())
+	})
+
+	It("should restrict a LogForwarder to the application logs of its namespace", func() {
+		lf := &logging.LogForwarder{
+			ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "my-app"},
+			Spec:       forwarder.Spec,
+		}
+		lf.Spec.Pipelines = []logging.PipelineSpec{
+			{Name: "infra", InputRefs: []string{logging.InputNameInfrastructure}, OutputRefs: []string{"es"}},
+		}
+		Expect(ValidateLogForwarder(lf, fake.NewFakeClient())).To(MatchError("pipeline infra: invalid: unrecognized inputs: [infrastructure], no valid inputs"))
+		lf.Spec.Pipelines[0].InputRefs = []string{logging.InputNameApplication}
+		Expect(ValidateLogForwarder(lf, fake.NewFakeClient())).To(Succeed())
+	})
+
+	It("should reject LogForwarder outputs with templates evaluating ruby", func() {
+		lf := &logging.LogForwarder{
+			ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "my-app"},
+			Spec:       forwarder.Spec,
+		}
+		lf.Spec.Outputs = []logging.OutputSpec{{
+			Name: "loki",
+			Type: logging.OutputTypeLoki,
+			URL:  "http://loki.svc:3100",
+			OutputTypeSpec: logging.OutputTypeSpec{Loki: &logging.Loki{
+				Labels: map[string]string{"app": "a#{File.read('/etc/shadow')}-{.kubernetes.pod_name}"},
+			}},
+		}}
+		lf.Spec.Pipelines = []logging.PipelineSpec{
+			{Name: "apps", InputRefs: []string{logging.InputNameApplication}, OutputRefs: []string{"loki"}},
+		}
+		Expect(ValidateLogForwarder(lf, fake.NewFakeClient())).To(MatchError(ContainSubstring(
+			`output loki: output "loki": label "app" "a#{File.read('/etc/shadow')}-{.kubernetes.pod_name}" may only be literal text and log record field references`)))
+		lf.Spec.Outputs[0].Loki.Labels["app"] = "a-{.kubernetes.pod_name}"
+		Expect(ValidateLogForwarder(lf, fake.NewFakeClient())).To(Succeed())
+	})
+})
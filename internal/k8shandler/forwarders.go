@@ -0,0 +1,238 @@
+package k8shandler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ViaQ/logerr/log"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getForwarderInstances returns the ClusterLogForwarders other than the singleton and the
+// LogForwarders of all namespaces, ordered by kind, namespace and name.
+// LogForwarders are returned as ClusterLogForwarders of kind LogForwarder.
+func (clusterRequest *ClusterLoggingRequest) getForwarderInstances() []*logging.ClusterLogForwarder {
+	instances := []*logging.ClusterLogForwarder{}
+
+	clfs := &logging.ClusterLogForwarderList{}
+	if err := clusterRequest.Client.List(context.TODO(), clfs, client.InNamespace(constants.OpenshiftNS)); err != nil {
+		log.Error(err, "Encountered unexpected error listing", "kind", logging.ClusterLogForwarderKind)
+	}
+	for i := range clfs.Items {
+		clf := &clfs.Items[i]
+		if clf.Name == constants.SingletonName || clf.DeletionTimestamp != nil {
+			continue
+		}
+		clf.Kind = logging.ClusterLogForwarderKind
+		instances = append(instances, clf)
+	}
+
+	lfs := &logging.LogForwarderList{}
+	if err := clusterRequest.Client.List(context.TODO(), lfs); err != nil {
+		log.Error(err, "Encountered unexpected error listing", "kind", logging.LogForwarderKind)
+	}
+	for _, lf := range lfs.Items {
+		if lf.DeletionTimestamp != nil {
+			continue
+		}
+		instances = append(instances, &logging.ClusterLogForwarder{
+			TypeMeta:   metav1.TypeMeta{Kind: logging.LogForwarderKind, APIVersion: lf.APIVersion},
+			ObjectMeta: lf.ObjectMeta,
+			Spec:       lf.Spec,
+			Status:     lf.Status,
+		})
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		a, b := instances[i], instances[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return instances
+}
+
+// getForwarderInstance returns the forwarder instance of the given kind, namespace and name, nil if not found.
+func (clusterRequest *ClusterLoggingRequest) getForwarderInstance(kind, namespace, name string) *logging.ClusterLogForwarder {
+	for _, instance := range clusterRequest.ForwarderInstances {
+		if instance.Kind == kind && instance.Namespace == namespace && instance.Name == name {
+			return instance
+		}
+	}
+	return nil
+}
+
+// forwarderPrefix is prepended to the input, output and pipeline names of a forwarder instance
+// when they are merged into the collector configuration.
+func forwarderPrefix(instance *logging.ClusterLogForwarder) string {
+	if instance.Kind == logging.LogForwarderKind {
+		return fmt.Sprintf("%s.%s.", instance.Namespace, instance.Name)
+	}
+	return instance.Name + "."
+}
+
+// forwarderNamespace returns the namespace the forwarder being normalized is restricted to,
+// empty unless it is a LogForwarder.
+func (clusterRequest *ClusterLoggingRequest) forwarderNamespace() string {
+	if instance := clusterRequest.forwarderInstance; instance != nil && instance.Kind == logging.LogForwarderKind {
+		return instance.Namespace
+	}
+	return ""
+}
+
+// mergeForwarderInstances normalizes each of the ForwarderInstances on its own, sets its status
+// and merges its valid inputs, outputs and pipelines into ForwarderSpec.
+func (clusterRequest *ClusterLoggingRequest) mergeForwarderInstances() {
+	for _, instance := range clusterRequest.ForwarderInstances {
+		instanceRequest := *clusterRequest
+		instanceRequest.forwarderInstance = instance
+		instanceRequest.ForwarderRequest = instance
+		instanceRequest.ForwarderSpec = instance.Spec
+		instanceRequest.CLFVerifier = ClusterLogForwarderVerifier{}
+
+		spec, status := instanceRequest.NormalizeForwarder()
+		instanceRequest.ForwarderSpec = *spec
+		instance.Status = *status
+		if instanceRequest.forwarderNamespace() == "" {
+			instanceRequest.applyOutputDefaults()
+		}
+
+		if err := clusterRequest.mergeForwarderSpec(instance, instanceRequest.ForwarderSpec, instanceRequest.OutputSecrets); err != nil {
+			log.V(3).Info("Forwarder instance not merged", "kind", instance.Kind, "namespace", instance.Namespace, "name", instance.Name, "error", err)
+			instance.Status.Conditions.SetCondition(condInvalid("%v", err))
+		}
+	}
+}
+
+// mergeForwarderSpec adds the normalized spec of a forwarder instance to ForwarderSpec with prefixed names.
+// References to the reserved inputs and the default output are kept, except that the application input
+// of a LogForwarder is replaced by an input selecting its namespace.
+func (clusterRequest *ClusterLoggingRequest) mergeForwarderSpec(instance *logging.ClusterLogForwarder, spec logging.ClusterLogForwarderSpec, secrets map[string]*corev1.Secret) error {
+	prefix := forwarderPrefix(instance)
+	namespace := ""
+	if instance.Kind == logging.LogForwarderKind {
+		namespace = instance.Namespace
+	}
+	inputRef := func(ref string) string {
+		if logging.IsInputTypeName(ref) && !(namespace != "" && ref == logging.InputNameApplication) {
+			return ref
+		}
+		return prefix + ref
+	}
+	outputRef := func(ref string) string {
+		if logging.IsReservedOutputName(ref) {
+			return ref
+		}
+		return prefix + ref
+	}
+
+	merged := &clusterRequest.ForwarderSpec
+	inputs, outputs, pipelines := sets.NewString(), sets.NewString(), sets.NewString()
+	for _, input := range merged.Inputs {
+		inputs.Insert(input.Name)
+	}
+	for _, output := range merged.Outputs {
+		outputs.Insert(output.Name)
+	}
+	for _, pipeline := range merged.Pipelines {
+		pipelines.Insert(pipeline.Name)
+	}
+
+	newInputs := []logging.InputSpec{}
+	for _, input := range spec.Inputs {
+		input.Name = inputRef(input.Name)
+		newInputs = append(newInputs, input)
+	}
+	newPipelines := []logging.PipelineSpec{}
+	namespaceInput := false
+	for _, pipeline := range spec.Pipelines {
+		pipeline.Name = prefix + pipeline.Name
+		inRefs := make([]string, len(pipeline.InputRefs))
+		for i, ref := range pipeline.InputRefs {
+			inRefs[i] = inputRef(ref)
+			namespaceInput = namespaceInput || (namespace != "" && ref == logging.InputNameApplication)
+		}
+		outRefs := make([]string, len(pipeline.OutputRefs))
+		for i, ref := range pipeline.OutputRefs {
+			outRefs[i] = outputRef(ref)
+		}
+		pipeline.InputRefs, pipeline.OutputRefs = inRefs, outRefs
+		newPipelines = append(newPipelines, pipeline)
+	}
+	if namespaceInput {
+		newInputs = append(newInputs, logging.InputSpec{
+			Name:        inputRef(logging.InputNameApplication),
+			Application: &logging.Application{Namespaces: []string{namespace}},
+		})
+	}
+	newOutputs := []logging.OutputSpec{}
+	for _, output := range spec.Outputs {
+		if logging.IsReservedOutputName(output.Name) && outputs.Has(output.Name) {
+			continue // Shared default output
+		}
+		output.Name = outputRef(output.Name)
+		newOutputs = append(newOutputs, output)
+	}
+
+	conflicts := sets.NewString()
+	for _, input := range newInputs {
+		if inputs.Has(input.Name) {
+			conflicts.Insert(input.Name)
+		}
+	}
+	for _, output := range newOutputs {
+		if outputs.Has(output.Name) {
+			conflicts.Insert(output.Name)
+		}
+	}
+	for _, pipeline := range newPipelines {
+		if pipelines.Has(pipeline.Name) {
+			conflicts.Insert(pipeline.Name)
+		}
+	}
+	if conflicts.Len() > 0 {
+		return fmt.Errorf("names conflict with another forwarder: %v", conflicts.List())
+	}
+
+	if clusterRequest.OutputSecrets == nil {
+		clusterRequest.OutputSecrets = map[string]*corev1.Secret{}
+	}
+	for name, secret := range secrets {
+		clusterRequest.OutputSecrets[outputRef(name)] = secret
+	}
+	merged.Inputs = append(merged.Inputs, newInputs...)
+	merged.Outputs = append(merged.Outputs, newOutputs...)
+	merged.Pipelines = append(merged.Pipelines, newPipelines...)
+	return nil
+}
+
+// verifyInputNamespace verifies an input of a LogForwarder only selects application logs of its namespace,
+// and limits the input to the namespace when it does not name namespaces.
+func verifyInputNamespace(input *logging.InputSpec, namespace string, conds logging.NamedConditions) bool {
+	if input.Application == nil || input.Infrastructure != nil || input.Audit != nil {
+		conds.Set(input.Name, condInvalid("LogForwarder inputs only select application logs of namespace %q", namespace))
+		return false
+	}
+	for _, ns := range input.Application.Namespaces {
+		if ns != namespace {
+			conds.Set(input.Name, condInvalid("LogForwarder inputs only select application logs of namespace %q", namespace))
+			return false
+		}
+	}
+	if len(input.Application.Namespaces) == 0 {
+		application := *input.Application
+		application.Namespaces = []string{namespace}
+		input.Application = &application
+	}
+	return true
+}
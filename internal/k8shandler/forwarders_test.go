@@ -0,0 +1,173 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Merging forwarder instances", func() {
+	var (
+		request   *ClusterLoggingRequest
+		clf       *logging.ClusterLogForwarder
+		lf        *logging.ClusterLogForwarder
+		normalize = func() {
+			spec, status := request.NormalizeForwarder()
+			request.ForwarderSpec = *spec
+			request.ForwarderRequest.Status = *status
+			request.mergeForwarderInstances()
+		}
+	)
+	BeforeEach(func() {
+		request = &ClusterLoggingRequest{
+			Client: fake.NewFakeClient(),
+			Cluster: &logging.ClusterLogging{
+				ObjectMeta: metav1.ObjectMeta{Name: constants.SingletonName, Namespace: constants.OpenshiftNS},
+				Spec: logging.ClusterLoggingSpec{
+					LogStore: &logging.LogStoreSpec{Type: logging.LogStoreTypeElasticsearch},
+				},
+			},
+			ForwarderRequest: &logging.ClusterLogForwarder{
+				ObjectMeta: metav1.ObjectMeta{Name: constants.SingletonName, Namespace: constants.OpenshiftNS},
+			},
+			ForwarderSpec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{Name: "es", Type: logging.OutputTypeElasticsearch, URL: "http://es.svc:9200"},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{Name: "infra", InputRefs: []string{logging.InputNameInfrastructure}, OutputRefs: []string{"es", logging.OutputNameDefault}},
+				},
+			},
+			OutputSecrets: map[string]*corev1.Secret{},
+		}
+		clf = &logging.ClusterLogForwarder{
+			TypeMeta:   metav1.TypeMeta{Kind: logging.ClusterLogForwarderKind},
+			ObjectMeta: metav1.ObjectMeta{Name: "audit", Namespace: constants.OpenshiftNS},
+			Spec: logging.ClusterLogForwarderSpec{
+				Outputs: []logging.OutputSpec{
+					{Name: "es", Type: logging.OutputTypeElasticsearch, URL: "http://audit.svc:9200"},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{Name: "audit", InputRefs: []string{logging.InputNameAudit}, OutputRefs: []string{"es"}},
+				},
+			},
+		}
+		lf = &logging.ClusterLogForwarder{
+			TypeMeta:   metav1.TypeMeta{Kind: logging.LogForwarderKind},
+			ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "my-app"},
+			Spec: logging.ClusterLogForwarderSpec{
+				Inputs: []logging.InputSpec{
+					{Name: "frontend", Application: &logging.Application{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "frontend"}}}},
+				},
+				Outputs: []logging.OutputSpec{
+					{Name: "es", Type: logging.OutputTypeElasticsearch, URL: "http://es.my-app.svc:9200"},
+				},
+				Pipelines: []logging.PipelineSpec{
+					{Name: "all", InputRefs: []string{logging.InputNameApplication}, OutputRefs: []string{"es", logging.OutputNameDefault}},
+					{Name: "frontend", InputRefs: []string{"frontend"}, OutputRefs: []string{"es"}},
+				},
+			},
+		}
+		request.ForwarderInstances = []*logging.ClusterLogForwarder{clf, lf}
+	})
+
+	It("should merge the pipelines of all instances with prefixed names", func() {
+		normalize()
+		Expect(request.ForwarderRequest.Status.Conditions).To(HaveCondition("Ready", true, "", ""))
+		Expect(clf.Status.Conditions).To(HaveCondition("Ready", true, "", ""))
+		Expect(lf.Status.Conditions).To(HaveCondition("Ready", true, "", ""))
+		Expect(lf.Status.Pipelines).To(HaveKey("all"))
+		Expect(lf.Status.Outputs).To(HaveKey("es"))
+
+		Expect(request.ForwarderSpec.Pipelines).To(Equal([]logging.PipelineSpec{
+			{Name: "infra", InputRefs: []string{logging.InputNameInfrastructure}, OutputRefs: []string{logging.OutputNameDefault, "es"}},
+			{Name: "audit.audit", InputRefs: []string{logging.InputNameAudit}, OutputRefs: []string{"audit.es"}},
+			{Name: "my-app.apps.all", InputRefs: []string{"my-app.apps.application"}, OutputRefs: []string{logging.OutputNameDefault, "my-app.apps.es"}},
+			{Name: "my-app.apps.frontend", InputRefs: []string{"my-app.apps.frontend"}, OutputRefs: []string{"my-app.apps.es"}},
+		}))
+		Expect(request.ForwarderSpec.Inputs).To(Equal([]logging.InputSpec{
+			{Name: "my-app.apps.frontend", Application: &logging.Application{
+				Namespaces: []string{"my-app"},
+				Selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "frontend"}},
+			}},
+			{Name: "my-app.apps.application", Application: &logging.Application{Namespaces: []string{"my-app"}}},
+		}))
+		names := []string{}
+		for _, output := range request.ForwarderSpec.Outputs {
+			names = append(names, output.Name)
+		}
+		Expect(names).To(Equal([]string{"es", logging.OutputNameDefault, "audit.es", "my-app.apps.es"}))
+	})
+
+	It("should not add the default pipeline for an instance without pipelines", func() {
+		clf.Spec.Pipelines = nil
+		normalize()
+		Expect(clf.Status.Conditions).To(HaveCondition("Ready", false, "Invalid", "all pipelines invalid"))
+		Expect(request.ForwarderSpec.Pipelines).To(HaveLen(3))
+	})
+
+	It("should restrict a LogForwarder to the application logs of its namespace", func() {
+		lf.Spec.Inputs = append(lf.Spec.Inputs,
+			logging.InputSpec{Name: "other", Application: &logging.Application{Namespaces: []string{"other-app"}}},
+			logging.InputSpec{Name: "nodes", Infrastructure: &logging.Infrastructure{}},
+		)
+		lf.Spec.Pipelines = []logging.PipelineSpec{
+			{Name: "infra", InputRefs: []string{logging.InputNameInfrastructure}, OutputRefs: []string{"es"}},
+			{Name: "other", InputRefs: []string{"other", "nodes"}, OutputRefs: []string{"es"}},
+			{Name: "frontend", InputRefs: []string{"frontend"}, OutputRefs: []string{"es"}},
+		}
+		normalize()
+		Expect(lf.Status.Pipelines["infra"]).To(HaveCondition("Ready", false, "Invalid", `unrecognized inputs: \[infrastructure\]`))
+		Expect(lf.Status.Pipelines["other"]).To(HaveCondition("Ready", false, "Invalid", `unrecognized inputs: \[nodes other\]`))
+		Expect(lf.Status.Pipelines["frontend"]).To(HaveCondition("Ready", true, "", ""))
+		Expect(request.ForwarderSpec.Pipelines).To(ContainElement(logging.PipelineSpec{
+			Name: "my-app.apps.frontend", InputRefs: []string{"my-app.apps.frontend"}, OutputRefs: []string{"my-app.apps.es"},
+		}))
+		Expect(request.ForwarderSpec.Pipelines).To(HaveLen(3))
+	})
+
+	It("should not allow LogForwarder outputs to reference secrets", func() {
+		lf.Spec.Outputs[0].Secret = &logging.OutputSecretSpec{Name: constants.CollectorSecretName}
+		normalize()
+		Expect(lf.Status.Outputs["es"]).To(HaveCondition("Ready", false, "Invalid", "may not reference secrets"))
+		Expect(request.OutputSecrets).To(BeEmpty())
+	})
+
+	It("should not merge an instance with conflicting names", func() {
+		request.ForwarderSpec.Pipelines[0].Name = "audit.audit"
+		normalize()
+		Expect(clf.Status.Conditions).To(HaveCondition("Ready", false, "Invalid", `names conflict with another forwarder: \[audit.audit\]`))
+		Expect(request.ForwarderSpec.Pipelines).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("Getting forwarder instances", func() {
+	It("should list ClusterLogForwarders other than the singleton and LogForwarders in all namespaces", func() {
+		_ = logging.SchemeBuilder.AddToScheme(scheme.Scheme)
+		request := &ClusterLoggingRequest{
+			Client: fake.NewFakeClient(
+				&logging.ClusterLogForwarder{ObjectMeta: metav1.ObjectMeta{Name: constants.SingletonName, Namespace: constants.OpenshiftNS}},
+				&logging.ClusterLogForwarder{ObjectMeta: metav1.ObjectMeta{Name: "audit", Namespace: constants.OpenshiftNS}},
+				&logging.LogForwarder{ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "team-b"}},
+				&logging.LogForwarder{ObjectMeta: metav1.ObjectMeta{Name: "apps", Namespace: "team-a"}},
+			),
+		}
+		instances := request.getForwarderInstances()
+		keys := []string{}
+		for _, instance := range instances {
+			keys = append(keys, instance.Kind+"/"+instance.Namespace+"/"+instance.Name)
+		}
+		Expect(keys).To(Equal([]string{
+			"ClusterLogForwarder/openshift-logging/audit",
+			"LogForwarder/team-a/apps",
+			"LogForwarder/team-b/apps",
+		}))
+		Expect(forwarderPrefix(instances[0])).To(Equal("audit."))
+		Expect(forwarderPrefix(instances[1])).To(Equal("team-a.apps."))
+	})
+})
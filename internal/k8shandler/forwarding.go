@@ -2,7 +2,9 @@ package k8shandler
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/ViaQ/logerr/log"
@@ -11,10 +13,17 @@ import (
 	"github.com/openshift/cluster-logging-operator/internal/constants"
 	"github.com/openshift/cluster-logging-operator/internal/generator"
 	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/helpers"
+	fluentdkafka "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/kafka"
+	fluentdloki "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/loki"
+	"github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/security"
+	fluentdsyslog "github.com/openshift/cluster-logging-operator/internal/generator/fluentd/output/syslog"
+	genhelper "github.com/openshift/cluster-logging-operator/internal/generator/helpers"
 	"github.com/openshift/cluster-logging-operator/internal/generator/vector"
 	"github.com/openshift/cluster-logging-operator/internal/status"
 	"github.com/openshift/cluster-logging-operator/internal/url"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	client "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -26,7 +35,7 @@ func (clusterRequest *ClusterLoggingRequest) generateCollectorConfig() (config s
 		return "", nil
 	}
 	switch clusterRequest.Cluster.Spec.Collection.Logs.Type {
-	case logging.LogCollectionTypeFluentd:
+	case logging.LogCollectionTypeFluentd, logging.LogCollectionTypeVector:
 		break
 	default:
 		return "", fmt.Errorf("%s collector does not support pipelines feature", clusterRequest.Cluster.Spec.Collection.Logs.Type)
@@ -39,27 +48,8 @@ func (clusterRequest *ClusterLoggingRequest) generateCollectorConfig() (config s
 	spec, status := clusterRequest.NormalizeForwarder()
 	clusterRequest.ForwarderSpec = *spec
 	clusterRequest.ForwarderRequest.Status = *status
-
-	if clusterRequest.ForwarderSpec.OutputDefaults != nil {
-		defaultOutputSpecs := clusterRequest.ForwarderSpec.OutputDefaults
-		if defaultOutputSpecs.Elasticsearch != nil {
-			for i, out := range clusterRequest.ForwarderSpec.Outputs {
-				// copy from defaults if output specific spec not present
-				if out.Type == logging.OutputTypeElasticsearch && out.Elasticsearch == nil {
-					out.Elasticsearch = defaultOutputSpecs.Elasticsearch
-					out.Secret = &logging.OutputSecretSpec{
-						Name: constants.CollectorSecretName,
-					}
-					secret, err := clusterRequest.GetSecret(constants.CollectorSecretName)
-					if err != nil {
-						log.V(2).Info("no secret for default output type")
-					}
-					clusterRequest.OutputSecrets[out.Name] = secret
-					clusterRequest.ForwarderSpec.Outputs[i] = out
-				}
-			}
-		}
-	}
+	clusterRequest.applyOutputDefaults()
+	clusterRequest.mergeForwarderInstances()
 
 	op := generator.Options{}
 	if clusterRequest.includeLegacyForwardConfig() {
@@ -90,7 +80,7 @@ func (clusterRequest *ClusterLoggingRequest) generateCollectorConfig() (config s
 	switch collector {
 	case logging.LogCollectionTypeFluentd:
 		confSections = fluentd.Conf(&clusterRequest.Cluster.Spec, clusterRequest.OutputSecrets, &clusterRequest.ForwarderSpec, op)
-	case "vector":
+	case logging.LogCollectionTypeVector:
 		confSections = vector.Conf(&clusterRequest.Cluster.Spec, clusterRequest.OutputSecrets, &clusterRequest.ForwarderSpec, op)
 	case "fluent-bit":
 	}
@@ -117,6 +107,30 @@ func (clusterRequest *ClusterLoggingRequest) generateCollectorConfig() (config s
 	return generatedConfig, err
 }
 
+// applyOutputDefaults copies the OutputDefaults to the outputs of the normalized ForwarderSpec
+func (clusterRequest *ClusterLoggingRequest) applyOutputDefaults() {
+	if clusterRequest.ForwarderSpec.OutputDefaults != nil {
+		defaultOutputSpecs := clusterRequest.ForwarderSpec.OutputDefaults
+		if defaultOutputSpecs.Elasticsearch != nil {
+			for i, out := range clusterRequest.ForwarderSpec.Outputs {
+				// copy from defaults if output specific spec not present
+				if out.Type == logging.OutputTypeElasticsearch && out.Elasticsearch == nil {
+					out.Elasticsearch = defaultOutputSpecs.Elasticsearch
+					out.Secret = &logging.OutputSecretSpec{
+						Name: constants.CollectorSecretName,
+					}
+					secret, err := clusterRequest.GetSecret(constants.CollectorSecretName)
+					if err != nil {
+						log.V(2).Info("no secret for default output type")
+					}
+					clusterRequest.OutputSecrets[out.Name] = secret
+					clusterRequest.ForwarderSpec.Outputs[i] = out
+				}
+			}
+		}
+	}
+}
+
 func (clusterRequest *ClusterLoggingRequest) readClusterName() (string, error) {
 	infra := configv1.Infrastructure{}
 	err := clusterRequest.Client.Get(context.Background(), client.ObjectKey{Name: constants.ClusterInfrastructureInstance}, &infra)
@@ -133,8 +147,8 @@ func (clusterRequest *ClusterLoggingRequest) NormalizeForwarder() (*logging.Clus
 		clusterRequest.CLFVerifier.VerifyOutputSecret = clusterRequest.verifyOutputSecret
 	}
 
-	// Check for default configuration
-	if len(clusterRequest.ForwarderSpec.Pipelines) == 0 {
+	// Check for default configuration, forwarder instances other than the singleton have none.
+	if len(clusterRequest.ForwarderSpec.Pipelines) == 0 && clusterRequest.forwarderInstance == nil {
 		if clusterRequest.Cluster.Spec.LogStore != nil && clusterRequest.Cluster.Spec.LogStore.Type == logging.LogStoreTypeElasticsearch {
 			log.V(2).Info("ClusterLogForwarder forwarding to default store")
 			defaultPipeline := logging.PipelineSpec{
@@ -249,7 +263,12 @@ func (clusterRequest *ClusterLoggingRequest) verifyPipelines(spec *logging.Clust
 	// Known output names, note if "default" is enabled it will already be in the OutputMap()
 	outputs := sets.StringKeySet(spec.OutputMap())
 	// Known input names, reserved names not in InputMap() we don't expose default inputs.
-	inputs := sets.StringKeySet(spec.InputMap()).Union(logging.ReservedInputNames)
+	// A LogForwarder only has the application input.
+	reserved := logging.ReservedInputNames
+	if clusterRequest.forwarderNamespace() != "" {
+		reserved = sets.NewString(logging.InputNameApplication)
+	}
+	inputs := sets.StringKeySet(spec.InputMap()).Union(reserved)
 
 	for i, pipeline := range clusterRequest.ForwarderSpec.Pipelines {
 		if pipeline.Name == "" {
@@ -263,9 +282,23 @@ func (clusterRequest *ClusterLoggingRequest) verifyPipelines(spec *logging.Clust
 		}
 		names.Insert(pipeline.Name)
 
+		if len(pipeline.Filters) != 0 && clusterRequest.isVectorCollector() {
+			status.Pipelines.Set(pipeline.Name, condInvalid("filters are not supported by the vector collector"))
+			continue
+		}
+		if err := verifyPipelineFilters(pipeline.Filters); err != nil {
+			status.Pipelines.Set(pipeline.Name, condInvalid("invalid filter: %v", err))
+			continue
+		}
+
 		goodIn, msgIn := verifyRefs("inputs", pipeline.InputRefs, inputs)
 		goodOut, msgOut := verifyRefs("outputs", pipeline.OutputRefs, outputs)
 
+		if err := verifyPipelineFields(pipeline.Fields, goodOut, spec.OutputMap()); err != nil {
+			status.Pipelines.Set(pipeline.Name, condInvalid("invalid fields: %v", err))
+			continue
+		}
+
 		if msgs := append(msgIn, msgOut...); len(msgs) > 0 { // Something wrong
 			msg := strings.Join(msgs, ", ")
 			if len(goodIn) == 0 || len(goodOut) == 0 { // All bad, disabled
@@ -282,10 +315,158 @@ func (clusterRequest *ClusterLoggingRequest) verifyPipelines(spec *logging.Clust
 			OutputRefs: goodOut.List(),
 			Labels:     pipeline.Labels,
 			Parse:      pipeline.Parse,
+			Filters:    pipeline.Filters,
+			Fields:     pipeline.Fields,
 		})
 	}
 }
 
+// verifyPipelineFilters returns an error for the first filter that is not valid
+func verifyPipelineFilters(filters []logging.FilterSpec) error {
+	for i, f := range filters {
+		switch f.Action {
+		case logging.FilterActionKeep, logging.FilterActionDrop:
+		default:
+			return fmt.Errorf("filters[%d]: unknown action %q", i, f.Action)
+		}
+		switch {
+		case f.Field != "" && len(f.Levels) != 0:
+			return fmt.Errorf("filters[%d]: only one of field or levels can be set", i)
+		case len(f.Levels) != 0:
+			continue
+		case f.Field == "":
+			return fmt.Errorf("filters[%d]: one of field or levels must be set", i)
+		case !fieldPathRegex.MatchString(f.Field) || strings.Contains(f.Field, "'"):
+			return fmt.Errorf("filters[%d]: invalid field %q", i, f.Field)
+		case f.Pattern == "":
+			return fmt.Errorf("filters[%d]: field %q requires a pattern", i, f.Field)
+		}
+		if _, err := regexp.Compile(f.Pattern); err != nil {
+			return fmt.Errorf("filters[%d]: invalid pattern %q: %v", i, f.Pattern, err)
+		}
+		// The pattern is matched by the collector with a Ruby regular expression
+		if rubyIncompatibleRegex.MatchString(f.Pattern) {
+			return fmt.Errorf("filters[%d]: pattern %q uses a syntax not supported by Ruby regular expressions", i, f.Pattern)
+		}
+	}
+	return nil
+}
+
+// verifyPipelineFields returns an error if the fields are not valid paths, or remove a field required by an output
+func verifyPipelineFields(fields *logging.FieldsSpec, outputRefs sets.String, outputs map[string]*logging.OutputSpec) error {
+	if fields == nil {
+		return nil
+	}
+	// the fields are record accessors and comma separated record keys of the fluentd collector
+	validField := func(f string) bool {
+		return fieldPathRegex.MatchString(f) && !strings.ContainsAny(f, "',")
+	}
+	for _, f := range append(append([]string{}, fields.Keep...), fields.Prune...) {
+		if !validField(f) {
+			return fmt.Errorf("invalid field %q", f)
+		}
+	}
+	for _, r := range fields.Rename {
+		switch {
+		case !validField(r.From):
+			return fmt.Errorf("invalid field %q", r.From)
+		case r.To == "" || strings.Contains(r.To, "."):
+			return fmt.Errorf("field %q must be renamed to a top level field", r.From)
+		case r.To == strings.Split(r.From, ".")[0]:
+			return fmt.Errorf("field %q can not be renamed to its top level field", r.From)
+		}
+	}
+	// a field is removed with its nested fields, the key of a label is a single field
+	within := func(field, path string) bool {
+		fieldKeys, pathKeys := genhelper.FieldPath(field), genhelper.FieldPath(path)
+		if len(pathKeys) > len(fieldKeys) {
+			return false
+		}
+		for i, key := range pathKeys {
+			if fieldKeys[i] != key {
+				return false
+			}
+		}
+		return true
+	}
+	for _, name := range outputRefs.List() {
+		output, ok := outputs[name]
+		if !ok {
+			continue
+		}
+		for _, required := range outputRequiredFields(output) {
+			if len(fields.Keep) != 0 {
+				kept := false
+				for _, k := range fields.Keep {
+					kept = kept || within(required, k) || within(k, required)
+				}
+				if !kept {
+					return fmt.Errorf("field %q required by output %q is not kept", required, name)
+				}
+			}
+			for _, p := range fields.Prune {
+				if within(required, p) {
+					return fmt.Errorf("field %q required by output %q is pruned", required, name)
+				}
+			}
+			for _, r := range fields.Rename {
+				if within(required, r.From) {
+					return fmt.Errorf("field %q required by output %q is renamed", required, name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// outputRequiredFields returns the dot delimited paths of the log record fields an output uses to route or label logs,
+// including the fields referenced by its templates
+func outputRequiredFields(output *logging.OutputSpec) []string {
+	fields := []string{}
+	templates := outputTemplates(output)
+	for _, name := range sets.StringKeySet(templates).List() {
+		fields = append(fields, helpers.RecordFields(templates[name])...)
+	}
+	switch output.Type {
+	case logging.OutputTypeLoki:
+		fields = append(fields, fluentdloki.RecordFields(output.Loki)...)
+	case logging.OutputTypeCloudwatch:
+		if cw := output.Cloudwatch; cw != nil {
+			switch cw.GroupBy {
+			case logging.LogGroupByNamespaceName:
+				fields = append(fields, "kubernetes.namespace_name")
+			case logging.LogGroupByNamespaceUUID:
+				fields = append(fields, "kubernetes.namespace_id")
+			case logging.LogGroupByKey:
+				fields = append(fields, cw.GroupByKey)
+			}
+			switch cw.StreamName {
+			case logging.LogStreamNameByPodName:
+				fields = append(fields, "kubernetes.namespace_name", "kubernetes.pod_name")
+			case logging.LogStreamNameByContainerName:
+				fields = append(fields, "kubernetes.namespace_name", "kubernetes.pod_name", "kubernetes.container_name")
+			}
+		}
+	case logging.OutputTypeElasticsearch:
+		// the index, document id and timestamp of the records written to Elasticsearch
+		fields = append(fields, "viaq_index_name", "viaq_msg_id", "@timestamp")
+		if es := output.Elasticsearch; es != nil && es.StructuredTypeKey != "" {
+			fields = append(fields, es.StructuredTypeKey)
+		}
+	case logging.OutputTypeSyslog:
+		values := syslogValues(output.Syslog)
+		for _, name := range sets.StringKeySet(values).List() {
+			if fluentdsyslog.IsKeyExpr(values[name]) {
+				fields = append(fields, strings.TrimPrefix(values[name], "$."))
+			}
+		}
+		if output.Syslog != nil && output.Syslog.PayloadKey != "" {
+			fields = append(fields, output.Syslog.PayloadKey)
+		}
+	}
+	return fields
+}
+
 // verifyInputs and set status.Inputs conditions
 func (clusterRequest *ClusterLoggingRequest) verifyInputs(spec *logging.ClusterLogForwarderSpec, status *logging.ClusterLogForwarderStatus) {
 	// Collect input conditions
@@ -303,6 +484,20 @@ func (clusterRequest *ClusterLoggingRequest) verifyInputs(spec *logging.ClusterL
 			badName("input name %q is reserved", input.Name)
 		case len(status.Inputs[input.Name]) > 0:
 			badName("duplicate name: %q", input.Name)
+		case clusterRequest.forwarderNamespace() != "" && !verifyInputNamespace(&input, clusterRequest.forwarderNamespace(), status.Inputs):
+			log.V(3).Info("verifyInputs failed", "reason", "input selects logs outside of the LogForwarder namespace", "input name", input.Name)
+		case input.Application != nil && !verifyInputNamespaces(&input, status.Inputs):
+			log.V(3).Info("verifyInputs failed", "reason", "application namespaces are invalid", "input name", input.Name)
+		case input.Application != nil && !clusterRequest.verifyInputSelector(&input, status.Inputs):
+			log.V(3).Info("verifyInputs failed", "reason", "application selector is not supported", "input name", input.Name)
+		case input.Application != nil && input.Application.DetectExceptions != nil && clusterRequest.isVectorCollector():
+			status.Inputs.Set(input.Name, condInvalid("detectExceptions is not supported by the vector collector"))
+		case input.Application != nil && !clusterRequest.verifyInputRateLimit(&input, status.Inputs):
+			log.V(3).Info("verifyInputs failed", "reason", "application rate limit is invalid", "input name", input.Name)
+		case input.Infrastructure != nil && !logging.InfrastructureSources.HasAll(input.Infrastructure.Sources...):
+			status.Inputs.Set(input.Name, condInvalid("infrastructure inputs only support sources: %v", logging.InfrastructureSources.List()))
+		case input.Audit != nil && !logging.AuditSources.HasAll(input.Audit.Sources...):
+			status.Inputs.Set(input.Name, condInvalid("audit inputs only support sources: %v", logging.AuditSources.List()))
 		default:
 			spec.Inputs = append(spec.Inputs, input)
 			status.Inputs.Set(input.Name, condReady)
@@ -310,6 +505,67 @@ func (clusterRequest *ClusterLoggingRequest) verifyInputs(spec *logging.ClusterL
 	}
 }
 
+// namespacePatternRegex matches namespace names that may contain '*' wildcards
+var namespacePatternRegex = regexp.MustCompile(`^[a-z0-9*]([-a-z0-9*]*[a-z0-9*])?$`)
+
+// verifyInputNamespaces verifies the included and excluded namespaces of an application input are names or patterns
+func verifyInputNamespaces(input *logging.InputSpec, conds logging.NamedConditions) bool {
+	for _, ns := range append(input.Application.Namespaces, input.Application.ExcludeNamespaces...) {
+		if !namespacePatternRegex.MatchString(ns) {
+			conds.Set(input.Name, condInvalid("invalid namespace or namespace pattern: %q", ns))
+			return false
+		}
+	}
+	return true
+}
+
+// verifyInputSelector verifies the label selector of an application input can be honored by the collector
+func (clusterRequest *ClusterLoggingRequest) verifyInputSelector(input *logging.InputSpec, conds logging.NamedConditions) bool {
+	selector := input.Application.Selector
+	if selector == nil {
+		return true
+	}
+	if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		conds.Set(input.Name, condInvalid("invalid selector: %v", err))
+		return false
+	}
+	if clusterRequest.isVectorCollector() {
+		return true
+	}
+	// label_router only matches label values
+	for _, req := range selector.MatchExpressions {
+		if req.Operator == metav1.LabelSelectorOpExists || req.Operator == metav1.LabelSelectorOpDoesNotExist {
+			conds.Set(input.Name, condInvalid("selector operator %q is not supported by the fluentd collector", req.Operator))
+			return false
+		}
+	}
+	return true
+}
+
+// verifyInputRateLimit verifies the rate limit of an application input
+func (clusterRequest *ClusterLoggingRequest) verifyInputRateLimit(input *logging.InputSpec, conds logging.NamedConditions) bool {
+	rl := input.Application.RateLimit
+	switch {
+	case rl == nil:
+		return true
+	case clusterRequest.isVectorCollector():
+		conds.Set(input.Name, condInvalid("rateLimit is not supported by the vector collector"))
+	case rl.RecordsPerSecond < 1:
+		conds.Set(input.Name, condInvalid("rateLimit recordsPerSecond must be positive"))
+	case rl.Burst != 0 && rl.Burst < rl.RecordsPerSecond:
+		conds.Set(input.Name, condInvalid("rateLimit burst must not be less than recordsPerSecond"))
+	default:
+		return true
+	}
+	return false
+}
+
+// isVectorCollector returns true if logs are collected by vector
+func (clusterRequest *ClusterLoggingRequest) isVectorCollector() bool {
+	cluster := clusterRequest.Cluster
+	return cluster != nil && cluster.Spec.Collection != nil && cluster.Spec.Collection.Logs.Type == logging.LogCollectionTypeVector
+}
+
 func (clusterRequest *ClusterLoggingRequest) verifyOutputs(spec *logging.ClusterLogForwarderSpec, status *logging.ClusterLogForwarderStatus) {
 	status.Outputs = logging.NamedConditions{}
 	clusterRequest.OutputSecrets = make(map[string]*corev1.Secret, len(clusterRequest.ForwarderSpec.Outputs))
@@ -336,13 +592,36 @@ func (clusterRequest *ClusterLoggingRequest) verifyOutputs(spec *logging.Cluster
 			status.Outputs.Set(output.Name, condInvalid("output %q: unknown output type %q", output.Name, output.Type))
 		case !clusterRequest.verifyOutputURL(&output, status.Outputs):
 			log.V(3).Info("verifyOutputs failed", "reason", "output URL is invalid", "output URL", output.URL)
+		case output.Secret != nil && clusterRequest.forwarderNamespace() != "":
+			log.V(3).Info("verifyOutputs failed", "reason", "LogForwarder outputs may not reference secrets", "output name", output.Name)
+			status.Outputs.Set(output.Name, condInvalid("output %q: LogForwarder outputs may not reference secrets", output.Name))
 		case !clusterRequest.verifyOutputSecret(&output, status.Outputs):
 			log.V(3).Info("verifyOutputs failed", "reason", "output secret is invalid")
 		case !clusterRequest.CLFVerifier.VerifyOutputSecret(&output, status.Outputs):
 			break
+		case !verifyOutputBuffer(&output, status.Outputs):
+			log.V(3).Info("verifyOutputs failed", "reason", "output buffer is invalid", "output name", output.Name)
+		case !verifyOutputTemplates(&output, status.Outputs):
+			log.V(3).Info("verifyOutputs failed", "reason", "output template is invalid", "output name", output.Name)
+		case output.Type == logging.OutputTypeSyslog && !clusterRequest.verifyOutputSyslog(&output, status.Outputs):
+			log.V(3).Info("verifyOutputs failed", "reason", "syslog output is invalid", "output name", output.Name)
+		case output.Type == logging.OutputTypeKafka && !verifyOutputKafka(&output, status.Outputs):
+			log.V(3).Info("verifyOutputs failed", "reason", "kafka output is invalid", "output name", output.Name)
+		case output.Type == logging.OutputTypeElasticsearch && output.Elasticsearch != nil && output.Elasticsearch.DataStream && output.Elasticsearch.Index == "":
+			log.V(3).Info("verifyOutputs failed", "reason", "Elasticsearch data streams require an index", "output name", output.Name)
+			status.Outputs.Set(output.Name, condInvalid("output %q: dataStream requires the index naming the data streams", output.Name))
+		case output.Type == logging.OutputTypeLoki && !verifyOutputLoki(&output, status.Outputs):
+			log.V(3).Info("verifyOutputs failed", "reason", "loki output is invalid", "output name", output.Name)
 		case output.Type == logging.OutputTypeCloudwatch && output.Cloudwatch == nil:
 			log.V(3).Info("verifyOutputs failed", "reason", "Cloudwatch output requires type spec", "output name", output.Name)
 			status.Outputs.Set(output.Name, condInvalid("output %q: Cloudwatch output requires type spec", output.Name))
+		case output.Type == logging.OutputTypeCloudwatch && !clusterRequest.verifyOutputCloudwatch(&output, status.Outputs):
+			log.V(3).Info("verifyOutputs failed", "reason", "Cloudwatch output is invalid", "output name", output.Name)
+		case output.Type == logging.OutputTypeSplunk && output.Secret == nil:
+			log.V(3).Info("verifyOutputs failed", "reason", "Splunk output requires a secret", "output name", output.Name)
+			status.Outputs.Set(output.Name, condInvalid("output %q: Splunk output requires a secret with key %q", output.Name, constants.SplunkHECTokenKey))
+		case output.Type == logging.OutputTypeGoogleCloudLogging && !clusterRequest.verifyOutputGoogleCloudLogging(&output, status.Outputs):
+			log.V(3).Info("verifyOutputs failed", "reason", "Google Cloud Logging output is invalid", "output name", output.Name)
 		default:
 			status.Outputs.Set(output.Name, condReady)
 			spec.Outputs = append(spec.Outputs, output)
@@ -377,7 +656,7 @@ func (clusterRequest *ClusterLoggingRequest) verifyOutputs(spec *logging.Cluster
 		}
 	}
 
-	if clusterRequest.ForwarderSpec.OutputDefaults != nil {
+	if clusterRequest.ForwarderSpec.OutputDefaults != nil && clusterRequest.forwarderNamespace() == "" {
 		spec.OutputDefaults = clusterRequest.ForwarderSpec.OutputDefaults
 	}
 }
@@ -390,7 +669,7 @@ func (clusterRequest *ClusterLoggingRequest) verifyOutputURL(output *logging.Out
 	if output.URL == "" {
 		// Some output types (currently just kafka) allow a missing URL
 		// TODO (alanconway) move output-specific valiation to the output implementation.
-		if output.Type == logging.OutputTypeKafka || output.Type == logging.OutputTypeCloudwatch {
+		if output.Type == logging.OutputTypeKafka || output.Type == logging.OutputTypeCloudwatch || output.Type == logging.OutputTypeGoogleCloudLogging {
 			return true
 		} else {
 			return fail(condInvalid("URL is required for output type %v", output.Type))
@@ -403,6 +682,9 @@ func (clusterRequest *ClusterLoggingRequest) verifyOutputURL(output *logging.Out
 	if err := url.CheckAbsolute(u); err != nil {
 		return fail(condInvalid("invalid URL: %v", err))
 	}
+	if (output.Type == logging.OutputTypeHttp || output.Type == logging.OutputTypeSplunk) && u.Scheme != "http" && u.Scheme != "https" {
+		return fail(condInvalid("URL scheme %q is not supported for output type %v, must be http or https", u.Scheme, output.Type))
+	}
 	return true
 }
 
@@ -424,16 +706,260 @@ func (clusterRequest *ClusterLoggingRequest) verifyOutputSecret(output *logging.
 		return fail(condMissing("secret %q not found", output.Secret.Name))
 	}
 	verifySecret := verifySecretKeysForTLS
-	if output.Type == logging.OutputTypeCloudwatch {
+	switch output.Type {
+	case logging.OutputTypeCloudwatch:
 		verifySecret = verifySecretKeysForCloudwatch
+	case logging.OutputTypeSplunk:
+		verifySecret = verifySecretKeysForSplunk
+	case logging.OutputTypeGoogleCloudLogging:
+		verifySecret = verifySecretKeysForGoogleCloudLogging
+	case logging.OutputTypeKafka:
+		verifySecret = verifySecretKeysForKafka
+	case logging.OutputTypeElasticsearch:
+		verifySecret = verifySecretKeysForElasticsearch
 	}
 	if !verifySecret(output, conds, secret) {
 		return false
 	}
+	if output.Type == logging.OutputTypeCloudwatch && security.HasAWSWebIdentityRole(secret) && clusterRequest.isVectorCollector() {
+		return fail(condInvalid("%v is not supported by the vector collector", constants.AWSWebIdentityRoleKey))
+	}
 	clusterRequest.OutputSecrets[output.Name] = secret
 	return true
 }
 
+// verifyOutputSyslog verifies the framing of a syslog output and the trust material of syslog outputs over TLS.
+// The secret of a syslog output over TLS must have a CA bundle, a client certificate and key, or both. Outputs
+// without a secret or a CA bundle verify the server with the system trust store
+func (clusterRequest *ClusterLoggingRequest) verifyOutputSyslog(output *logging.OutputSpec, conds logging.NamedConditions) bool {
+	fail := func(c status.Condition) bool {
+		conds.Set(output.Name, c)
+		return false
+	}
+	// url is parsable, checked by verifyOutputURL
+	u, _ := url.Parse(output.URL)
+	// a client certificate without its key, or a key without its certificate, is rejected by verifySecretKeysForTLS
+	if secret := clusterRequest.OutputSecrets[output.Name]; url.IsTLSScheme(u.Scheme) && output.Secret != nil &&
+		len(secret.Data[constants.TrustedCABundleKey]) == 0 && len(secret.Data[constants.ClientCertKey]) == 0 {
+		return fail(condMissing("output %q: syslog over %v requires a secret with key %v, or keys %v and %v",
+			output.Name, u.Scheme, constants.TrustedCABundleKey, constants.ClientCertKey, constants.ClientPrivateKey))
+	}
+	if output.Syslog != nil && output.Syslog.Framing != "" && url.PlainScheme(u.Scheme) == "udp" {
+		return fail(condInvalid("output %q: framing is not supported over %v", output.Name, u.Scheme))
+	}
+	return true
+}
+
+// verifyOutputKafka verifies the message key and the headers taken from log record fields reference a single field
+func verifyOutputKafka(output *logging.OutputSpec, conds logging.NamedConditions) bool {
+	if output.Kafka == nil {
+		return true
+	}
+	fail := func(format string, args ...interface{}) bool {
+		conds.Set(output.Name, condInvalid("output %q: %s", output.Name, fmt.Sprintf(format, args...)))
+		return false
+	}
+	if _, ok := helpers.RecordField(output.Kafka.Key); output.Kafka.Key != "" && !ok {
+		return fail("key %q must reference a single log record field", output.Kafka.Key)
+	}
+	for name, value := range output.Kafka.Headers {
+		if _, ok := helpers.RecordField(value); helpers.IsRecordTemplate(value) && !ok {
+			return fail("header %q must be static or reference a single log record field", name)
+		}
+	}
+	return true
+}
+
+// verifyOutputCloudwatch verifies the group by key is set if and only if logs are grouped by key, and the
+// creation and retention of log groups
+func (clusterRequest *ClusterLoggingRequest) verifyOutputCloudwatch(output *logging.OutputSpec, conds logging.NamedConditions) bool {
+	cw := output.Cloudwatch
+	switch {
+	case cw.CreateLogGroup != nil && !*cw.CreateLogGroup && !clusterRequest.isVectorCollector():
+		// The fluentd output plugin cannot create missing log streams without creating missing log groups
+		conds.Set(output.Name, condInvalid("output %q: createLogGroup false is not supported by the fluentd collector", output.Name))
+		return false
+	case cw.RetentionInDays != nil && !cloudwatchRetentionDays.Has(int(*cw.RetentionInDays)):
+		conds.Set(output.Name, condInvalid("output %q: retentionInDays %d is not a valid Cloudwatch retention", output.Name, *cw.RetentionInDays))
+		return false
+	case cw.RetentionInDays != nil && clusterRequest.isVectorCollector():
+		conds.Set(output.Name, condInvalid("output %q: retentionInDays is not supported by the vector collector", output.Name))
+		return false
+	case cw.GroupBy == logging.LogGroupByKey && strings.Trim(cw.GroupByKey, ".") == "":
+		conds.Set(output.Name, condInvalid("output %q: groupBy %q requires groupByKey", output.Name, logging.LogGroupByKey))
+		return false
+	case cw.GroupBy != logging.LogGroupByKey && cw.GroupByKey != "":
+		conds.Set(output.Name, condInvalid("output %q: groupByKey requires groupBy %q", output.Name, logging.LogGroupByKey))
+		return false
+	}
+	return true
+}
+
+// outputTemplates returns the values of an output which are literal text and log record field references,
+// e.g. app-{.kubernetes.namespace_name}, keyed by their parameter names
+func outputTemplates(output *logging.OutputSpec) map[string]string {
+	templates := map[string]string{}
+	switch {
+	case output.Type == logging.OutputTypeLoki && output.Loki != nil:
+		for name, value := range output.Loki.Labels {
+			templates[fmt.Sprintf("label %q", name)] = value
+		}
+		templates["tenantKey"] = output.Loki.TenantKey
+	case output.Type == logging.OutputTypeElasticsearch && output.Elasticsearch != nil:
+		templates["index"] = output.Elasticsearch.Index
+	case output.Type == logging.OutputTypeSplunk && output.Splunk != nil:
+		templates["index"] = output.Splunk.Index
+		templates["source"] = output.Splunk.Source
+		templates["sourceType"] = output.Splunk.SourceType
+	case output.Type == logging.OutputTypeGoogleCloudLogging && output.GoogleCloudLogging != nil:
+		templates["logId"] = output.GoogleCloudLogging.LogID
+	case output.Type == logging.OutputTypeKafka:
+		templates["topic"] = fluentdkafka.Topics(*output)
+		if output.Kafka != nil {
+			templates["key"] = output.Kafka.Key
+			for name, value := range output.Kafka.Headers {
+				templates[fmt.Sprintf("header %q", name)] = value
+			}
+		}
+	}
+	return templates
+}
+
+// syslogValues returns the values of a syslog output which are literal text, tag expressions, e.g. ${tag[0]},
+// or record accessors, e.g. $.kubernetes.namespace_name, keyed by their parameter names
+func syslogValues(s *logging.Syslog) map[string]string {
+	if s == nil {
+		return map[string]string{}
+	}
+	return map[string]string{
+		"severity":   s.Severity,
+		"facility":   s.Facility,
+		"tag":        s.Tag,
+		"payloadKey": s.PayloadKey,
+		"appName":    s.AppName,
+		"procID":     s.ProcID,
+		"msgID":      s.MsgID,
+	}
+}
+
+// templateSpecialChars start ruby interpolations or quote the values the collector configuration is generated with
+const templateSpecialChars = "$#{}`'\""
+
+// verifyOutputTemplates verifies the templated values of an output are restricted to literal text and log
+// record field references, the literal text may not contain any of templateSpecialChars
+func verifyOutputTemplates(output *logging.OutputSpec, conds logging.NamedConditions) bool {
+	fail := func(name, value string) bool {
+		conds.Set(output.Name, condInvalid("output %q: %s %q may only be literal text and log record field references, without any of %s", output.Name, name, value, templateSpecialChars))
+		return false
+	}
+	templates := outputTemplates(output)
+	for _, name := range sets.StringKeySet(templates).List() {
+		value := templates[name]
+		if strings.ContainsAny(helpers.LiteralText(value), templateSpecialChars) {
+			return fail(name, value)
+		}
+		for _, field := range helpers.RecordFields(value) {
+			if !fieldPathRegex.MatchString(field) || strings.ContainsAny(field, templateSpecialChars) {
+				return fail(name, value)
+			}
+		}
+	}
+	if output.Type == logging.OutputTypeSyslog {
+		values := syslogValues(output.Syslog)
+		for _, name := range sets.StringKeySet(values).List() {
+			value := values[name]
+			if !fluentdsyslog.IsKeyExpr(value) && strings.ContainsAny(fluentdsyslog.WithoutTagExprs(value), templateSpecialChars) {
+				return fail(name, value)
+			}
+		}
+	}
+	return true
+}
+
+// verifyOutputLoki verifies the names of the labels built from templates are valid Loki label names
+func verifyOutputLoki(output *logging.OutputSpec, conds logging.NamedConditions) bool {
+	if output.Loki == nil {
+		return true
+	}
+	for name := range output.Loki.Labels {
+		if !lokiLabelNameRegex.MatchString(name) {
+			conds.Set(output.Name, condInvalid("output %q: invalid Loki label name %q", output.Name, name))
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	fieldPathRegex      = regexp.MustCompile(`^[^.]+(\.[^.]+)*$`)
+	lokiLabelNameRegex  = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	awsRoleARNRegex     = regexp.MustCompile(`^arn:aws(-[a-z]+)*:iam::[0-9]{12}:role/\S+$`)
+	bufferSizeUnitRegex = regexp.MustCompile(`^([0-9]+)([kmgtKMGT]{0,1})$`)
+	bufferTimeUnitRegex = regexp.MustCompile(`^([0-9]+)([smhd]{0,1})$`)
+
+	// rubyIncompatibleRegex matches the Go regular expression syntax with another or no meaning in Ruby:
+	// \Q...\E quoting, (?P<name>...) groups and the m, s and U flags
+	rubyIncompatibleRegex = regexp.MustCompile(`(^|[^\\])(\\\\)*(\\Q|\(\?(P<|[a-zA-Z-]*[msU][a-zA-Z-]*[:)]))`)
+
+	// cloudwatchRetentionDays are the retention periods accepted by Cloudwatch
+	cloudwatchRetentionDays = sets.NewInt(1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1827, 3653)
+)
+
+// verifyOutputBuffer verifies the per-output buffer tuning parameters
+func verifyOutputBuffer(output *logging.OutputSpec, conds logging.NamedConditions) bool {
+	if output.Buffer == nil {
+		return true
+	}
+	fail := func(format string, args ...interface{}) bool {
+		conds.Set(output.Name, condInvalid("output %q: invalid buffer %s", output.Name, fmt.Sprintf(format, args...)))
+		return false
+	}
+	buffer := output.Buffer
+	for _, size := range []struct {
+		name  string
+		value logging.FluentdSizeUnit
+	}{
+		{"chunkLimitSize", buffer.ChunkLimitSize},
+		{"totalLimitSize", buffer.TotalLimitSize},
+	} {
+		if size.value != "" && !bufferSizeUnitRegex.MatchString(string(size.value)) {
+			return fail("%s %q", size.name, size.value)
+		}
+	}
+	for _, duration := range []struct {
+		name  string
+		value logging.FluentdTimeUnit
+	}{
+		{"flushInterval", buffer.FlushInterval},
+		{"retryWait", buffer.RetryWait},
+		{"retryMaxInterval", buffer.RetryMaxInterval},
+		{"retryTimeout", buffer.RetryTimeout},
+	} {
+		if duration.value != "" && !bufferTimeUnitRegex.MatchString(string(duration.value)) {
+			return fail("%s %q", duration.name, duration.value)
+		}
+	}
+	switch buffer.OverflowAction {
+	case "", logging.ThrowExceptionAction, logging.BlockAction, logging.DropOldestChunkAction:
+	default:
+		return fail("overflowAction %q", buffer.OverflowAction)
+	}
+	switch buffer.FlushMode {
+	case "", logging.FlushModeLazy, logging.FlushModeInterval, logging.FlushModeImmediate:
+	default:
+		return fail("flushMode %q", buffer.FlushMode)
+	}
+	switch buffer.RetryType {
+	case "", logging.RetryExponentialBackoff, logging.RetryPeriodic:
+	default:
+		return fail("retryType %q", buffer.RetryType)
+	}
+	if buffer.FlushThreadCount < 0 {
+		return fail("flushThreadCount %d", buffer.FlushThreadCount)
+	}
+	return true
+}
+
 func verifySecretKeysForTLS(output *logging.OutputSpec, conds logging.NamedConditions, secret *corev1.Secret) bool {
 	fail := func(c status.Condition) bool {
 		conds.Set(output.Name, c)
@@ -456,15 +982,114 @@ func verifySecretKeysForTLS(output *logging.OutputSpec, conds logging.NamedCondi
 	}
 	return true
 }
+
+// verifySecretKeysForSplunk verifies the secret has the HEC token and a valid TLS configuration
+func verifySecretKeysForSplunk(output *logging.OutputSpec, conds logging.NamedConditions, secret *corev1.Secret) bool {
+	if len(secret.Data[constants.SplunkHECTokenKey]) == 0 {
+		conds.Set(output.Name, condMissing("%v is required", constants.SplunkHECTokenKey))
+		return false
+	}
+	return verifySecretKeysForTLS(output, conds, secret)
+}
+
+// verifySecretKeysForKafka verifies the SASL mechanism of the secret has the credentials it authenticates with
+// and a valid TLS configuration
+func verifySecretKeysForKafka(output *logging.OutputSpec, conds logging.NamedConditions, secret *corev1.Secret) bool {
+	if mechanism, ok := secret.Data[constants.SaslMechanism]; ok {
+		switch string(mechanism) {
+		case constants.SaslMechanismPlain, constants.SaslMechanismScramSHA256, constants.SaslMechanismScramSHA512:
+		default:
+			conds.Set(output.Name, condInvalid("%v %q is not one of %v, %v, %v", constants.SaslMechanism, mechanism,
+				constants.SaslMechanismPlain, constants.SaslMechanismScramSHA256, constants.SaslMechanismScramSHA512))
+			return false
+		}
+		if len(secret.Data[constants.ClientUsername]) == 0 || len(secret.Data[constants.ClientPassword]) == 0 {
+			conds.Set(output.Name, condMissing("%v requires %v and %v", constants.SaslMechanism, constants.ClientUsername, constants.ClientPassword))
+			return false
+		}
+	}
+	return verifySecretKeysForTLS(output, conds, secret)
+}
+
+// verifySecretKeysForElasticsearch verifies the secret has either an API key or a username and password,
+// and a valid TLS configuration
+func verifySecretKeysForElasticsearch(output *logging.OutputSpec, conds logging.NamedConditions, secret *corev1.Secret) bool {
+	if len(secret.Data[constants.ElasticsearchAPIKey]) > 0 && len(secret.Data[constants.ClientUsername]) > 0 {
+		conds.Set(output.Name, condInvalid("cannot have %v with %v", constants.ElasticsearchAPIKey, constants.ClientUsername))
+		return false
+	}
+	return verifySecretKeysForTLS(output, conds, secret)
+}
+
+// verifyOutputGoogleCloudLogging verifies the target and log ID of a Google Cloud Logging output
+func (clusterRequest *ClusterLoggingRequest) verifyOutputGoogleCloudLogging(output *logging.OutputSpec, conds logging.NamedConditions) bool {
+	fail := func(format string, args ...interface{}) bool {
+		conds.Set(output.Name, condInvalid("output %q: %s", output.Name, fmt.Sprintf(format, args...)))
+		return false
+	}
+	gcl := output.GoogleCloudLogging
+	if gcl == nil {
+		return fail("Google Cloud Logging output requires type spec")
+	}
+	targets := 0
+	for _, id := range []string{gcl.ProjectID, gcl.FolderID, gcl.OrganizationID, gcl.BillingAccountID} {
+		if id != "" {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return fail("exactly one of projectId, folderId, organizationId or billingAccountId is required")
+	}
+	if gcl.LogID == "" {
+		return fail("logId is required")
+	}
+	if output.Secret == nil {
+		return fail("Google Cloud Logging output requires a secret with key %q", constants.GoogleApplicationCredentialsKey)
+	}
+	if !clusterRequest.isVectorCollector() {
+		if gcl.ProjectID == "" {
+			return fail("only projectId is supported by the fluentd collector")
+		}
+		// The fluentd collector reads the credentials of all Google Cloud Logging outputs from one file
+		for _, o := range clusterRequest.ForwarderSpec.Outputs {
+			if o.Type == logging.OutputTypeGoogleCloudLogging && o.Secret != nil && o.Secret.Name != output.Secret.Name {
+				return fail("Google Cloud Logging outputs must use the same secret with the fluentd collector")
+			}
+		}
+	}
+	return true
+}
+
+// verifySecretKeysForGoogleCloudLogging verifies the secret has service account JSON credentials
+func verifySecretKeysForGoogleCloudLogging(output *logging.OutputSpec, conds logging.NamedConditions, secret *corev1.Secret) bool {
+	credentials := secret.Data[constants.GoogleApplicationCredentialsKey]
+	if len(credentials) == 0 {
+		conds.Set(output.Name, condMissing("%v is required", constants.GoogleApplicationCredentialsKey))
+		return false
+	}
+	if !json.Valid(credentials) {
+		conds.Set(output.Name, condInvalid("%v is not valid JSON", constants.GoogleApplicationCredentialsKey))
+		return false
+	}
+	return verifySecretKeysForTLS(output, conds, secret)
+}
+
 func verifySecretKeysForCloudwatch(output *logging.OutputSpec, conds logging.NamedConditions, secret *corev1.Secret) bool {
 	log.V(3).Info("V")
 	fail := func(c status.Condition) bool {
 		conds.Set(output.Name, c)
 		return false
 	}
+	// A role assumed with a web identity token takes precedence over static keys
+	if roleARN := strings.TrimSpace(string(secret.Data[constants.AWSWebIdentityRoleKey])); roleARN != "" {
+		if !awsRoleARNRegex.MatchString(roleARN) {
+			return fail(condInvalid("%v is not a valid IAM role ARN: %q", constants.AWSWebIdentityRoleKey, roleARN))
+		}
+		return true
+	}
 	hasID := len(secret.Data[constants.AWSAccessKeyID]) > 0
 	hasKey := len(secret.Data[constants.AWSSecretAccessKey]) > 0
-	missingMessage := "aws_access_key_id and aws_secret_access_key are required"
+	missingMessage := "aws_access_key_id and aws_secret_access_key are required, or role_arn to assume a role with a web identity token"
 	if !hasID || !hasKey {
 		return fail(condMissing(missingMessage))
 	}
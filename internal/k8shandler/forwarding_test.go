@@ -14,9 +14,11 @@ import (
 	"github.com/openshift/cluster-logging-operator/test"
 	. "github.com/openshift/cluster-logging-operator/test"
 	. "github.com/openshift/cluster-logging-operator/test/matchers"
+	"github.com/openshift/cluster-logging-operator/internal/utils"
 	core "k8s.io/api/core/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -104,6 +106,194 @@ var _ = Describe("Normalizing forwarder", func() {
 			}
 		})
 
+		Context("inputs", func() {
+			It("should drop inputs that select unknown infrastructure or audit sources", func() {
+				request.ForwarderSpec.Inputs = []logging.InputSpec{
+					{
+						Name:           "infra",
+						Infrastructure: &logging.Infrastructure{Sources: []string{logging.InfrastructureSourceNode, "kernel"}},
+					},
+					{
+						Name:  "etcd-audit",
+						Audit: &logging.Audit{Sources: []string{"etcd"}},
+					},
+				}
+				request.ForwarderSpec.Pipelines = []logging.PipelineSpec{
+					{
+						Name:       "aPipeline",
+						OutputRefs: []string{output.Name},
+						InputRefs:  []string{"infra", "etcd-audit"},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Inputs).To(BeEmpty(), "Exp. inputs with unknown sources to be dropped")
+				Expect(spec.Pipelines).To(BeEmpty())
+				Expect(status.Pipelines["aPipeline"]).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, `inputs:.*\[etcd-audit infra]`))
+			})
+
+			It("should drop application inputs with selector operators the fluentd collector does not support", func() {
+				request.ForwarderSpec.Inputs = []logging.InputSpec{
+					{
+						Name: "myapp",
+						Application: &logging.Application{
+							Selector: &metav1.LabelSelector{
+								MatchExpressions: []metav1.LabelSelectorRequirement{
+									{Key: "team", Operator: metav1.LabelSelectorOpExists},
+								},
+							},
+						},
+					},
+				}
+				request.ForwarderSpec.Pipelines = []logging.PipelineSpec{
+					{
+						Name:       "aPipeline",
+						OutputRefs: []string{output.Name},
+						InputRefs:  []string{"myapp"},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Inputs).To(BeEmpty(), "Exp. inputs with unsupported selectors to be dropped")
+				Expect(status.Pipelines["aPipeline"]).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, `inputs:.*\[myapp]`))
+
+				request.Cluster.Spec.Collection = &logging.CollectionSpec{
+					Logs: logging.LogCollectionSpec{Type: logging.LogCollectionTypeVector},
+				}
+				spec, _ = request.NormalizeForwarder()
+				Expect(spec.Inputs).To(HaveLen(1), "Exp. the vector collector to support all selector operators")
+			})
+
+			It("should drop application inputs with invalid namespaces", func() {
+				request.ForwarderSpec.Inputs = []logging.InputSpec{
+					{
+						Name: "myapp",
+						Application: &logging.Application{
+							Namespaces:        []string{"team-a-*"},
+							ExcludeNamespaces: []string{"team-a, team-b"},
+						},
+					},
+				}
+				request.ForwarderSpec.Pipelines = []logging.PipelineSpec{
+					{
+						Name:       "aPipeline",
+						OutputRefs: []string{output.Name},
+						InputRefs:  []string{"myapp"},
+					},
+				}
+				spec, _ := request.NormalizeForwarder()
+				Expect(spec.Inputs).To(BeEmpty(), "Exp. inputs with invalid namespaces to be dropped")
+
+				request.ForwarderSpec.Inputs[0].Application.ExcludeNamespaces = []string{"team-a-*-test"}
+				spec, _ = request.NormalizeForwarder()
+				Expect(spec.Inputs).To(HaveLen(1))
+			})
+
+			It("should accept application inputs with In and NotIn selectors", func() {
+				request.ForwarderSpec.Inputs = []logging.InputSpec{
+					{
+						Name: "myapp",
+						Application: &logging.Application{
+							Selector: &metav1.LabelSelector{
+								MatchExpressions: []metav1.LabelSelectorRequirement{
+									{Key: "app.kubernetes.io/part-of", Operator: metav1.LabelSelectorOpIn, Values: []string{"x", "y"}},
+									{Key: "tier", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"test"}},
+								},
+							},
+						},
+					},
+				}
+				request.ForwarderSpec.Pipelines = []logging.PipelineSpec{
+					{
+						Name:       "aPipeline",
+						OutputRefs: []string{output.Name},
+						InputRefs:  []string{"myapp"},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Inputs).To(HaveLen(1))
+				Expect(status.Inputs["myapp"]).To(HaveCondition(logging.ConditionReady, true, "", ""))
+			})
+
+			It("should drop application inputs detecting exceptions when logs are collected by vector", func() {
+				request.ForwarderSpec.Inputs = []logging.InputSpec{
+					{
+						Name: "myapp",
+						Application: &logging.Application{
+							DetectExceptions: &logging.DetectExceptions{
+								Languages: []logging.ExceptionLanguage{"java"},
+							},
+						},
+					},
+				}
+				request.ForwarderSpec.Pipelines = []logging.PipelineSpec{
+					{
+						Name:       "aPipeline",
+						OutputRefs: []string{output.Name},
+						InputRefs:  []string{"myapp"},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Inputs).To(HaveLen(1))
+				Expect(status.Inputs["myapp"]).To(HaveCondition(logging.ConditionReady, true, "", ""))
+
+				request.Cluster.Spec.Collection = &logging.CollectionSpec{
+					Logs: logging.LogCollectionSpec{Type: logging.LogCollectionTypeVector},
+				}
+				spec, status = request.NormalizeForwarder()
+				Expect(spec.Inputs).To(BeEmpty(), "Exp. inputs detecting exceptions to be dropped")
+				Expect(status.Pipelines["aPipeline"]).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, `inputs:.*\[myapp]`))
+			})
+
+			It("should drop application inputs with an invalid rate limit", func() {
+				request.ForwarderSpec.Inputs = []logging.InputSpec{
+					{
+						Name: "myapp",
+						Application: &logging.Application{
+							RateLimit: &logging.RateLimit{RecordsPerSecond: 100, Burst: 10},
+						},
+					},
+				}
+				request.ForwarderSpec.Pipelines = []logging.PipelineSpec{
+					{
+						Name:       "aPipeline",
+						OutputRefs: []string{output.Name},
+						InputRefs:  []string{"myapp"},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Inputs).To(BeEmpty(), "Exp. inputs with a burst less than the rate to be dropped")
+				Expect(status.Pipelines["aPipeline"]).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, `inputs:.*\[myapp]`))
+
+				request.ForwarderSpec.Inputs[0].Application.RateLimit.Burst = 500
+				spec, _ = request.NormalizeForwarder()
+				Expect(spec.Inputs).To(HaveLen(1))
+
+				request.Cluster.Spec.Collection = &logging.CollectionSpec{
+					Logs: logging.LogCollectionSpec{Type: logging.LogCollectionTypeVector},
+				}
+				spec, _ = request.NormalizeForwarder()
+				Expect(spec.Inputs).To(BeEmpty(), "Exp. inputs with a rate limit to be dropped by the vector collector")
+			})
+
+			It("should accept inputs that select infrastructure or audit sources", func() {
+				request.ForwarderSpec.Inputs = []logging.InputSpec{
+					{
+						Name:  "kube-audit",
+						Audit: &logging.Audit{Sources: []string{logging.AuditSourceKube}},
+					},
+				}
+				request.ForwarderSpec.Pipelines = []logging.PipelineSpec{
+					{
+						Name:       "aPipeline",
+						OutputRefs: []string{output.Name},
+						InputRefs:  []string{"kube-audit"},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Inputs).To(HaveLen(1))
+				Expect(status.Inputs["kube-audit"]).To(HaveCondition(logging.ConditionReady, true, "", ""))
+			})
+		})
+
 		Context("pipelines", func() {
 			It("should only include inputs if there is at least one valid pipeline", func() {
 				request.ForwarderSpec.Pipelines = []logging.PipelineSpec{
@@ -188,6 +378,224 @@ var _ = Describe("Normalizing forwarder", func() {
 				Expect(conds).To(HaveCondition(logging.ConditionDegraded, true, "Invalid", "aMissingOutput"), YAMLString(status))
 				Expect(conds).To(HaveCondition(logging.ConditionReady, true, "", ""))
 			})
+
+			It("should drop pipelines that have filters with invalid patterns", func() {
+				request.ForwarderSpec.Pipelines = append(request.ForwarderSpec.Pipelines,
+					logging.PipelineSpec{
+						Name:       "someDefinedPipeline",
+						OutputRefs: []string{output.Name},
+						InputRefs:  []string{logging.InputNameApplication},
+						Filters: []logging.FilterSpec{
+							{Action: logging.FilterActionDrop, Field: "message", Pattern: "health("},
+						},
+					})
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Pipelines).To(HaveLen(1))
+				conds := status.Pipelines["someDefinedPipeline"]
+				Expect(conds).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, "invalid pattern"))
+			})
+
+			It("should drop pipelines that have filters without a field or levels", func() {
+				request.ForwarderSpec.Pipelines = append(request.ForwarderSpec.Pipelines,
+					logging.PipelineSpec{
+						Name:       "someDefinedPipeline",
+						OutputRefs: []string{output.Name},
+						InputRefs:  []string{logging.InputNameApplication},
+						Filters: []logging.FilterSpec{
+							{Action: logging.FilterActionKeep, Pattern: "health"},
+						},
+					})
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Pipelines).To(HaveLen(1))
+				conds := status.Pipelines["someDefinedPipeline"]
+				Expect(conds).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, "one of field or levels must be set"))
+			})
+
+			It("should drop pipelines that have filters with patterns not supported by Ruby", func() {
+				for _, pattern := range []string{`(?P<path>/healthz)`, `\Q/healthz\E`, `(?s)GET.*`, `(?i:get)(?U)x+`} {
+					request.ForwarderSpec.Pipelines[0].Filters = []logging.FilterSpec{
+						{Action: logging.FilterActionDrop, Field: "message", Pattern: pattern},
+					}
+					spec, status := request.NormalizeForwarder()
+					Expect(spec.Pipelines).To(BeEmpty(), pattern)
+					Expect(status.Pipelines["aPipeline"]).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, "not supported by Ruby"), pattern)
+				}
+			})
+
+			It("should accept pipelines that have valid filters", func() {
+				filters := []logging.FilterSpec{
+					{Action: logging.FilterActionDrop, Field: "message", Pattern: "^GET /healthz"},
+					{Action: logging.FilterActionDrop, Levels: []string{"debug"}},
+					{Action: logging.FilterActionKeep, Field: "kubernetes.labels.app.kubernetes.io/name", Pattern: `(?i)^front\(?s\)`},
+				}
+				request.ForwarderSpec.Pipelines[0].Filters = filters
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Pipelines).To(HaveLen(1))
+				Expect(spec.Pipelines[0].Filters).To(Equal(filters))
+				Expect(status.Pipelines["aPipeline"]).To(HaveCondition(logging.ConditionReady, true, "", ""))
+
+				request.Cluster.Spec.Collection = &logging.CollectionSpec{
+					Logs: logging.LogCollectionSpec{Type: logging.LogCollectionTypeVector},
+				}
+				spec, status = request.NormalizeForwarder()
+				Expect(spec.Pipelines).To(BeEmpty(), "Exp. pipelines with filters to be dropped by the vector collector")
+				Expect(status.Pipelines["aPipeline"]).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, "not supported by the vector collector"))
+			})
+
+			It("should drop pipelines that prune fields required by an output", func() {
+				request.ForwarderSpec.Outputs = append(request.ForwarderSpec.Outputs, logging.OutputSpec{
+					Name: "loki",
+					Type: logging.OutputTypeLoki,
+					URL:  "https://loki.example.com",
+					OutputTypeSpec: logging.OutputTypeSpec{
+						Loki: &logging.Loki{
+							Labels: map[string]string{"app": "{.kubernetes.labels.app}"},
+						},
+					},
+				})
+				request.ForwarderSpec.Pipelines = append(request.ForwarderSpec.Pipelines,
+					logging.PipelineSpec{
+						Name:       "someDefinedPipeline",
+						OutputRefs: []string{"loki"},
+						InputRefs:  []string{logging.InputNameApplication},
+						Fields: &logging.FieldsSpec{
+							Prune: []string{"kubernetes.labels"},
+						},
+					})
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Pipelines).To(HaveLen(1))
+				conds := status.Pipelines["someDefinedPipeline"]
+				Expect(conds).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, `field "kubernetes.labels.app" required by output "loki" is pruned`))
+
+				request.ForwarderSpec.Pipelines[1].Fields = &logging.FieldsSpec{
+					Keep: []string{"message", "kubernetes.labels"},
+				}
+				_, status = request.NormalizeForwarder()
+				conds = status.Pipelines["someDefinedPipeline"]
+				Expect(conds).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, `field "kubernetes.container_name" required by output "loki" is not kept`))
+			})
+
+			It("should compare the keys of labels as single fields", func() {
+				request.ForwarderSpec.Outputs = append(request.ForwarderSpec.Outputs, logging.OutputSpec{
+					Name: "loki",
+					Type: logging.OutputTypeLoki,
+					URL:  "https://loki.example.com",
+					OutputTypeSpec: logging.OutputTypeSpec{
+						Loki: &logging.Loki{
+							Labels: map[string]string{"app": "{.kubernetes.labels.app.kubernetes.io/name}"},
+						},
+					},
+				})
+				request.ForwarderSpec.Pipelines = append(request.ForwarderSpec.Pipelines,
+					logging.PipelineSpec{
+						Name:       "someDefinedPipeline",
+						OutputRefs: []string{"loki"},
+						InputRefs:  []string{logging.InputNameApplication},
+						Fields: &logging.FieldsSpec{
+							Prune: []string{"kubernetes.labels.app"},
+						},
+					})
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Pipelines).To(HaveLen(2))
+				Expect(status.Pipelines["someDefinedPipeline"]).To(HaveCondition(logging.ConditionReady, true, "", ""))
+
+				request.ForwarderSpec.Pipelines[1].Fields = &logging.FieldsSpec{
+					Prune: []string{"kubernetes.labels.app.kubernetes.io/name"},
+				}
+				_, status = request.NormalizeForwarder()
+				Expect(status.Pipelines["someDefinedPipeline"]).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, `field "kubernetes.labels.app.kubernetes.io/name" required by output "loki" is pruned`))
+			})
+
+			It("should drop pipelines that prune fields referenced by syslog values", func() {
+				request.ForwarderSpec.Outputs = append(request.ForwarderSpec.Outputs, logging.OutputSpec{
+					Name: "syslog",
+					Type: logging.OutputTypeSyslog,
+					URL:  "udp://syslog.example.com:514",
+					OutputTypeSpec: logging.OutputTypeSpec{
+						Syslog: &logging.Syslog{AppName: "$.kubernetes.namespace_name"},
+					},
+				})
+				request.ForwarderSpec.Pipelines = append(request.ForwarderSpec.Pipelines,
+					logging.PipelineSpec{
+						Name:       "someDefinedPipeline",
+						OutputRefs: []string{"syslog"},
+						InputRefs:  []string{logging.InputNameApplication},
+						Fields: &logging.FieldsSpec{
+							Prune: []string{"kubernetes.namespace_name"},
+						},
+					})
+				_, status := request.NormalizeForwarder()
+				Expect(status.Pipelines["someDefinedPipeline"]).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, `field "kubernetes.namespace_name" required by output "syslog" is pruned`))
+			})
+
+			DescribeTable("should reject fields removing the fields referenced by the templates of an output", func(output logging.OutputSpec, required string) {
+				outputs := map[string]*logging.OutputSpec{output.Name: &output}
+				err := verifyPipelineFields(&logging.FieldsSpec{Prune: []string{required}}, sets.NewString(output.Name), outputs)
+				Expect(err).To(MatchError(fmt.Sprintf("field %q required by output %q is pruned", required, output.Name)))
+			},
+				Entry("splunk index", logging.OutputSpec{Name: "splunk", Type: logging.OutputTypeSplunk,
+					OutputTypeSpec: logging.OutputTypeSpec{Splunk: &logging.Splunk{Index: "app-{.kubernetes.namespace_name}"}}}, "kubernetes.namespace_name"),
+				Entry("splunk source", logging.OutputSpec{Name: "splunk", Type: logging.OutputTypeSplunk,
+					OutputTypeSpec: logging.OutputTypeSpec{Splunk: &logging.Splunk{Source: "{.kubernetes.pod_name}"}}}, "kubernetes.pod_name"),
+				Entry("splunk sourcetype", logging.OutputSpec{Name: "splunk", Type: logging.OutputTypeSplunk,
+					OutputTypeSpec: logging.OutputTypeSpec{Splunk: &logging.Splunk{SourceType: "{.log_type}"}}}, "log_type"),
+				Entry("google cloud logging log id", logging.OutputSpec{Name: "gcl", Type: logging.OutputTypeGoogleCloudLogging,
+					OutputTypeSpec: logging.OutputTypeSpec{GoogleCloudLogging: &logging.GoogleCloudLogging{LogID: "app-{.kubernetes.namespace_name}"}}}, "kubernetes.namespace_name"),
+				Entry("kafka topic", logging.OutputSpec{Name: "kafka", Type: logging.OutputTypeKafka,
+					OutputTypeSpec: logging.OutputTypeSpec{Kafka: &logging.Kafka{Topic: "app-{.kubernetes.namespace_name}"}}}, "kubernetes.namespace_name"),
+				Entry("elasticsearch index", logging.OutputSpec{Name: "es", Type: logging.OutputTypeElasticsearch,
+					OutputTypeSpec: logging.OutputTypeSpec{Elasticsearch: &logging.Elasticsearch{Index: "app-{.kubernetes.namespace_name}"}}}, "kubernetes.namespace_name"),
+				Entry("syslog record accessor", logging.OutputSpec{Name: "syslog", Type: logging.OutputTypeSyslog,
+					OutputTypeSpec: logging.OutputTypeSpec{Syslog: &logging.Syslog{MsgID: "$.kubernetes.pod_name"}}}, "kubernetes.pod_name"),
+				Entry("syslog payload key", logging.OutputSpec{Name: "syslog", Type: logging.OutputTypeSyslog,
+					OutputTypeSpec: logging.OutputTypeSpec{Syslog: &logging.Syslog{PayloadKey: "message"}}}, "message"),
+			)
+
+			It("should drop pipelines that do not keep the fields required by elasticsearch", func() {
+				request.ForwarderSpec.Pipelines[0].Fields = &logging.FieldsSpec{
+					Keep: []string{"message", "kubernetes", "viaq_index_name", "@timestamp"},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Pipelines).To(BeEmpty())
+				Expect(status.Pipelines["aPipeline"]).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, `field "viaq_msg_id" required by output`))
+
+				request.ForwarderSpec.Pipelines[0].Fields = &logging.FieldsSpec{
+					Prune: []string{"@timestamp"},
+				}
+				_, status = request.NormalizeForwarder()
+				Expect(status.Pipelines["aPipeline"]).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, `field "@timestamp" required by output`))
+			})
+
+			It("should drop pipelines with fields which are not record accessors", func() {
+				request.ForwarderSpec.Pipelines[0].Fields = &logging.FieldsSpec{
+					Prune: []string{"kubernetes.labels.it's"},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Pipelines).To(BeEmpty())
+				Expect(status.Pipelines["aPipeline"]).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, "invalid field"))
+			})
+
+			It("should drop pipelines that rename fields to nested fields", func() {
+				request.ForwarderSpec.Pipelines[0].Fields = &logging.FieldsSpec{
+					Rename: []logging.RenameField{{From: "kubernetes.flat_labels", To: "kubernetes.labels"}},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Pipelines).To(BeEmpty())
+				Expect(status.Pipelines["aPipeline"]).To(HaveCondition(logging.ConditionReady, false, logging.ReasonInvalid, "must be renamed to a top level field"))
+			})
+
+			It("should accept pipelines that have valid fields", func() {
+				fields := &logging.FieldsSpec{
+					Keep:   []string{"message", "kubernetes", "level", "viaq_index_name", "viaq_msg_id", "@timestamp"},
+					Prune:  []string{"kubernetes.flat_labels", "kubernetes.pod_id"},
+					Rename: []logging.RenameField{{From: "kubernetes.labels", To: "labels"}},
+				}
+				request.ForwarderSpec.Pipelines[0].Fields = fields
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Pipelines).To(HaveLen(1))
+				Expect(spec.Pipelines[0].Fields).To(Equal(fields))
+				Expect(status.Pipelines["aPipeline"]).To(HaveCondition(logging.ConditionReady, true, "", ""))
+			})
 		})
 
 		Context("outputs", func() {
@@ -280,6 +688,192 @@ var _ = Describe("Normalizing forwarder", func() {
 				Expect(status.Outputs["cw"]).To(HaveCondition("Ready", false, "Invalid", "Cloudwatch output requires type spec"))
 			})
 
+			It("should drop Cloudwatch output grouped by key without a group by key", func() {
+				request.ForwarderSpec.Outputs = []logging.OutputSpec{
+					{
+						Name: "cw",
+						Type: logging.OutputTypeCloudwatch,
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Cloudwatch: &logging.Cloudwatch{GroupBy: logging.LogGroupByKey},
+						},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Outputs).To(BeEmpty())
+				Expect(status.Outputs["cw"]).To(HaveCondition("Ready", false, "Invalid", `groupBy "key" requires groupByKey`))
+			})
+
+			It("should drop Cloudwatch output with a group by key not grouped by key", func() {
+				request.ForwarderSpec.Outputs = []logging.OutputSpec{
+					{
+						Name: "cw",
+						Type: logging.OutputTypeCloudwatch,
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Cloudwatch: &logging.Cloudwatch{
+								GroupBy:    logging.LogGroupByNamespaceName,
+								GroupByKey: "kubernetes.labels.app",
+							},
+						},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Outputs).To(BeEmpty())
+				Expect(status.Outputs["cw"]).To(HaveCondition("Ready", false, "Invalid", `groupByKey requires groupBy "key"`))
+			})
+
+			It("should accept Cloudwatch output grouped by key", func() {
+				request.ForwarderSpec.Outputs = []logging.OutputSpec{
+					{
+						Name: "cw",
+						Type: logging.OutputTypeCloudwatch,
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Cloudwatch: &logging.Cloudwatch{
+								GroupBy:    logging.LogGroupByKey,
+								GroupByKey: "kubernetes.labels.app.kubernetes.io/name",
+								StreamName: logging.LogStreamNameByContainerName,
+							},
+						},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Outputs).To(HaveLen(1))
+				Expect(status.Outputs["cw"]).To(HaveCondition("Ready", true, "", ""))
+			})
+
+			It("should drop Cloudwatch output not creating log groups when logs are collected by fluentd", func() {
+				request.ForwarderSpec.Outputs = []logging.OutputSpec{
+					{
+						Name: "cw",
+						Type: logging.OutputTypeCloudwatch,
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Cloudwatch: &logging.Cloudwatch{CreateLogGroup: utils.GetBool(false)},
+						},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Outputs).To(BeEmpty())
+				Expect(status.Outputs["cw"]).To(HaveCondition("Ready", false, "Invalid", "createLogGroup false is not supported by the fluentd collector"))
+			})
+
+			It("should accept Cloudwatch output not creating log groups when logs are collected by vector", func() {
+				request.Cluster.Spec.Collection = &logging.CollectionSpec{
+					Logs: logging.LogCollectionSpec{Type: logging.LogCollectionTypeVector},
+				}
+				request.ForwarderSpec.Outputs = []logging.OutputSpec{
+					{
+						Name: "cw",
+						Type: logging.OutputTypeCloudwatch,
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Cloudwatch: &logging.Cloudwatch{CreateLogGroup: utils.GetBool(false)},
+						},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Outputs).To(HaveLen(1))
+				Expect(status.Outputs["cw"]).To(HaveCondition("Ready", true, "", ""))
+			})
+
+			It("should drop Cloudwatch output with an invalid retention", func() {
+				retention := int32(2)
+				request.ForwarderSpec.Outputs = []logging.OutputSpec{
+					{
+						Name: "cw",
+						Type: logging.OutputTypeCloudwatch,
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Cloudwatch: &logging.Cloudwatch{CreateLogGroup: utils.GetBool(true), RetentionInDays: &retention},
+						},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Outputs).To(BeEmpty())
+				Expect(status.Outputs["cw"]).To(HaveCondition("Ready", false, "Invalid", "retentionInDays 2 is not a valid Cloudwatch retention"))
+			})
+
+			It("should drop Cloudwatch output with a retention when logs are collected by vector", func() {
+				retention := int32(30)
+				request.Cluster.Spec.Collection = &logging.CollectionSpec{
+					Logs: logging.LogCollectionSpec{Type: logging.LogCollectionTypeVector},
+				}
+				request.ForwarderSpec.Outputs = []logging.OutputSpec{
+					{
+						Name: "cw",
+						Type: logging.OutputTypeCloudwatch,
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Cloudwatch: &logging.Cloudwatch{CreateLogGroup: utils.GetBool(true), RetentionInDays: &retention},
+						},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Outputs).To(BeEmpty())
+				Expect(status.Outputs["cw"]).To(HaveCondition("Ready", false, "Invalid", "retentionInDays is not supported by the vector collector"))
+			})
+
+			It("should accept Cloudwatch output with a custom endpoint creating log groups with a retention", func() {
+				retention := int32(30)
+				request.ForwarderSpec.Outputs = []logging.OutputSpec{
+					{
+						Name: "cw",
+						Type: logging.OutputTypeCloudwatch,
+						URL:  "https://logs.us-gov-east-1.amazonaws.com",
+						OutputTypeSpec: logging.OutputTypeSpec{
+							Cloudwatch: &logging.Cloudwatch{
+								Region:          "us-gov-east-1",
+								RetentionInDays: &retention,
+							},
+						},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Outputs).To(HaveLen(1))
+				Expect(status.Outputs["cw"]).To(HaveCondition("Ready", true, "", ""))
+			})
+
+			It("should drop outputs that have invalid buffer tuning", func() {
+				request.ForwarderSpec.Outputs = []logging.OutputSpec{
+					{
+						Name:   "aName",
+						Type:   logging.OutputTypeFluentdForward,
+						URL:    "tcp://here:24224",
+						Buffer: &logging.FluentdBufferSpec{ChunkLimitSize: "8mb"},
+					},
+					{
+						Name:   "bName",
+						Type:   logging.OutputTypeFluentdForward,
+						URL:    "tcp://there:24224",
+						Buffer: &logging.FluentdBufferSpec{RetryTimeout: "forever"},
+					},
+					{
+						Name:   "cName",
+						Type:   logging.OutputTypeFluentdForward,
+						URL:    "tcp://elsewhere:24224",
+						Buffer: &logging.FluentdBufferSpec{OverflowAction: "ignore"},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Outputs).To(BeEmpty(), "Exp. outputs with invalid buffer tuning to be dropped")
+				Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "chunkLimitSize \"8mb\""))
+				Expect(status.Outputs["bName"]).To(HaveCondition("Ready", false, "Invalid", "retryTimeout \"forever\""))
+				Expect(status.Outputs["cName"]).To(HaveCondition("Ready", false, "Invalid", "overflowAction \"ignore\""))
+			})
+
+			It("should accept outputs with valid buffer tuning", func() {
+				request.ForwarderSpec.Outputs = []logging.OutputSpec{
+					{
+						Name: "aName",
+						Type: logging.OutputTypeFluentdForward,
+						URL:  "tcp://here:24224",
+						Buffer: &logging.FluentdBufferSpec{
+							ChunkLimitSize: "8m",
+							RetryTimeout:   "5m",
+							OverflowAction: logging.DropOldestChunkAction,
+						},
+					},
+				}
+				spec, status := request.NormalizeForwarder()
+				Expect(spec.Outputs).To(HaveLen(1))
+				Expect(status.Outputs["aName"]).To(HaveCondition("Ready", true, "", ""))
+			})
+
 			It("should allow specific outputs that do not require URL", func() {
 				request.ForwarderSpec.Outputs = []logging.OutputSpec{
 					{
@@ -397,6 +991,394 @@ var _ = Describe("Normalizing forwarder", func() {
 						Expect(spec.Outputs).To(HaveLen(len(request.ForwarderSpec.Outputs)))
 						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", true, "", ""))
 					})
+					It("should accept outputs with secrets that have a role_arn instead of access keys", func() {
+						secret.Data["role_arn"] = []byte("arn:aws:iam::123456789012:role/my-role\n")
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(HaveLen(len(request.ForwarderSpec.Outputs)))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", true, "", ""))
+					})
+					It("should drop outputs with secrets that have a malformed role_arn", func() {
+						secret.Data["role_arn"] = []byte("my-role")
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty(), fmt.Sprintf("secret %+v", secret))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "role_arn is not a valid IAM role ARN"))
+					})
+					It("should drop outputs with secrets that have a role_arn when logs are collected by vector", func() {
+						secret.Data["role_arn"] = []byte("arn:aws:iam::123456789012:role/my-role")
+						request.Cluster.Spec.Collection = &logging.CollectionSpec{
+							Logs: logging.LogCollectionSpec{Type: logging.LogCollectionTypeVector},
+						}
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty(), fmt.Sprintf("secret %+v", secret))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "role_arn is not supported by the vector collector"))
+					})
+				})
+
+				Context("for writing to syslog over TLS", func() {
+					BeforeEach(func() {
+						output = logging.OutputSpec{
+							Name:   "aName",
+							Type:   logging.OutputTypeSyslog,
+							URL:    "tls://syslog.example.com:6514",
+							Secret: &logging.OutputSecretSpec{Name: secret.Name},
+						}
+						request.ForwarderSpec.Outputs = []logging.OutputSpec{output}
+					})
+					It("should accept outputs without a secret, verifying the server with the system trust store", func() {
+						request.ForwarderSpec.Outputs[0].Secret = nil
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(HaveLen(len(request.ForwarderSpec.Outputs)))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", true, "", ""))
+					})
+					It("should accept outputs with secrets that are missing ca-bundle.crt", func() {
+						secret.Data["tls.crt"] = []byte{0, 1, 2}
+						secret.Data["tls.key"] = []byte{0, 1, 2}
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(HaveLen(len(request.ForwarderSpec.Outputs)), fmt.Sprintf("secret %+v", secret))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", true, "", ""))
+					})
+					It("should drop outputs with secrets that have no trust material", func() {
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty(), fmt.Sprintf("secret %+v", secret))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "MissingResource", "syslog over tls requires a secret with key ca-bundle.crt, or keys tls.crt and tls.key"))
+					})
+					It("should drop outputs with secrets that have an empty ca-bundle.crt", func() {
+						secret.Data["ca-bundle.crt"] = []byte{}
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty(), fmt.Sprintf("secret %+v", secret))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "MissingResource", "syslog over tls requires a secret with key ca-bundle.crt"))
+					})
+					It("should drop outputs with secrets that have a client certificate without its key", func() {
+						secret.Data["ca-bundle.crt"] = []byte{0, 1, 2}
+						secret.Data["tls.crt"] = []byte{0, 1, 2}
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty(), fmt.Sprintf("secret %+v", secret))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "MissingResource", "cannot have tls.crt without tls.key"))
+					})
+					It("should drop outputs with secrets that have a client key without its certificate", func() {
+						secret.Data["tls.key"] = []byte{0, 1, 2}
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty(), fmt.Sprintf("secret %+v", secret))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "MissingResource", "cannot have tls.key without tls.crt"))
+					})
+					It("should accept outputs with secrets that have ca-bundle.crt and a client certificate", func() {
+						secret.Data["ca-bundle.crt"] = []byte{0, 1, 2}
+						secret.Data["tls.crt"] = []byte{0, 1, 2}
+						secret.Data["tls.key"] = []byte{0, 1, 2}
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(HaveLen(len(request.ForwarderSpec.Outputs)))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", true, "", ""))
+					})
+					It("should drop outputs with values evaluating ruby", func() {
+						request.ForwarderSpec.Outputs[0].Secret = nil
+						request.ForwarderSpec.Outputs[0].Syslog = &logging.Syslog{AppName: "$.kubernetes.namespace_name", Tag: `"#{ENV['HOME']}"`}
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty())
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "tag"))
+					})
+					It("should accept outputs with values that are record accessors and tag expressions", func() {
+						request.ForwarderSpec.Outputs[0].Secret = nil
+						request.ForwarderSpec.Outputs[0].Syslog = &logging.Syslog{AppName: "$.kubernetes.namespace_name", Tag: "app-${tag[1]}"}
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(HaveLen(1))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", true, "", ""))
+					})
+					It("should drop outputs using framing over udps", func() {
+						secret.Data["ca-bundle.crt"] = []byte{0, 1, 2}
+						request.Client = fake.NewFakeClient(secret)
+						request.ForwarderSpec.Outputs[0].URL = "udps://syslog.example.com:6514"
+						request.ForwarderSpec.Outputs[0].Syslog = &logging.Syslog{Framing: logging.SyslogFramingOctetCounting}
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty())
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "framing is not supported over udps"))
+					})
+				})
+
+				Context("for writing to Splunk", func() {
+					BeforeEach(func() {
+						output = logging.OutputSpec{
+							Name:   "aName",
+							Type:   logging.OutputTypeSplunk,
+							URL:    "https://splunk.example.com:8088",
+							Secret: &logging.OutputSecretSpec{Name: secret.Name},
+						}
+						request.ForwarderSpec.Outputs = []logging.OutputSpec{output}
+					})
+					It("should drop outputs without a secret", func() {
+						request.ForwarderSpec.Outputs[0].Secret = nil
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty())
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "Splunk output requires a secret"))
+					})
+					It("should drop outputs with secrets that are missing hecToken", func() {
+						secret.Data["ca-bundle.crt"] = []byte{0, 1, 2}
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty(), fmt.Sprintf("secret %+v", secret))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "MissingResource", "hecToken is required"))
+					})
+					It("should drop outputs with secrets that have hecToken and an incomplete TLS configuration", func() {
+						secret.Data["hecToken"] = []byte{0, 1, 2}
+						secret.Data["tls.crt"] = []byte{0, 1, 2}
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty(), fmt.Sprintf("secret %+v", secret))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "MissingResource", "cannot have.*without"))
+					})
+					It("should accept outputs with secrets that have hecToken", func() {
+						secret.Data["hecToken"] = []byte{0, 1, 2}
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(HaveLen(len(request.ForwarderSpec.Outputs)))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", true, "", ""))
+					})
+					It("should drop outputs with a URL scheme other than http or https", func() {
+						secret.Data["hecToken"] = []byte{0, 1, 2}
+						request.Client = fake.NewFakeClient(secret)
+						request.ForwarderSpec.Outputs[0].URL = "tcp://splunk.example.com:8088"
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty())
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", `URL scheme "tcp" is not supported for output type splunk`))
+					})
+				})
+
+				Context("for writing to Elasticsearch", func() {
+					BeforeEach(func() {
+						output = logging.OutputSpec{
+							Name:   "aName",
+							Type:   logging.OutputTypeElasticsearch,
+							URL:    "https://es.example.com:9200",
+							Secret: &logging.OutputSecretSpec{Name: secret.Name},
+						}
+						request.ForwarderSpec.Outputs = []logging.OutputSpec{output}
+					})
+					It("should drop outputs with data streams and no index", func() {
+						request.ForwarderSpec.Outputs[0].Secret = nil
+						request.ForwarderSpec.Outputs[0].Elasticsearch = &logging.Elasticsearch{DataStream: true}
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty())
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "dataStream requires the index"))
+					})
+					It("should drop outputs with secrets that have an API key and a username", func() {
+						secret.Data["apiKey"] = []byte("a2V5")
+						secret.Data["username"] = []byte("user")
+						secret.Data["password"] = []byte("pass")
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty())
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "cannot have apiKey with username"))
+					})
+					It("should drop outputs with an index evaluating ruby outside of record field references", func() {
+						request.ForwarderSpec.Outputs[0].Secret = nil
+						request.ForwarderSpec.Outputs[0].Elasticsearch = &logging.Elasticsearch{Index: "app-{.log_type}-#{`id`}"}
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty())
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "index \"app-{.log_type}-#{`id`}\" may only be literal text"))
+					})
+					It("should accept outputs with data streams and secrets that have an API key", func() {
+						request.ForwarderSpec.Outputs[0].Elasticsearch = &logging.Elasticsearch{
+							Index:      "logs-{.log_type}-default",
+							DataStream: true,
+						}
+						secret.Data["apiKey"] = []byte("a2V5")
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(HaveLen(1))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", true, "", ""))
+					})
+				})
+
+				Context("for writing to Loki", func() {
+					BeforeEach(func() {
+						output = logging.OutputSpec{
+							Name: "aName",
+							Type: logging.OutputTypeLoki,
+							URL:  "https://gateway.example.com/api/logs/v1/application",
+						}
+						request.ForwarderSpec.Outputs = []logging.OutputSpec{output}
+					})
+					It("should drop outputs with invalid label names", func() {
+						request.ForwarderSpec.Outputs[0].Loki = &logging.Loki{
+							Labels: map[string]string{"app.kubernetes.io/name": "{.kubernetes.labels.app}"},
+						}
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty())
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "invalid Loki label name"))
+					})
+					It("should drop outputs with label values evaluating ruby outside of record field references", func() {
+						request.ForwarderSpec.Outputs[0].Loki = &logging.Loki{
+							Labels: map[string]string{"app": "a#{File.read('/etc/shadow')}-${`id`}"},
+						}
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty())
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "may only be literal text and log record field references"))
+					})
+					It("should accept outputs with valid label names", func() {
+						request.ForwarderSpec.Outputs[0].Loki = &logging.Loki{
+							Labels: map[string]string{"cluster": "east", "app": "{.kubernetes.labels.app}"},
+						}
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(HaveLen(1))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", true, "", ""))
+					})
+				})
+
+				Context("for writing to Kafka", func() {
+					BeforeEach(func() {
+						output = logging.OutputSpec{
+							Name:   "aName",
+							Type:   logging.OutputTypeKafka,
+							URL:    "tls://broker1-kafka.svc.messaging.cluster.local:9092/topic",
+							Secret: &logging.OutputSecretSpec{Name: secret.Name},
+						}
+						request.ForwarderSpec.Outputs = []logging.OutputSpec{output}
+					})
+					It("should drop outputs with secrets that have an unknown SASL mechanism", func() {
+						secret.Data["username"] = []byte("user")
+						secret.Data["password"] = []byte("pass")
+						secret.Data["sasl_mechanism"] = []byte("GSSAPI")
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty())
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "sasl_mechanism \"GSSAPI\" is not one of"))
+					})
+					It("should drop outputs with secrets that have a SASL mechanism without credentials", func() {
+						secret.Data["sasl_mechanism"] = []byte("SCRAM-SHA-512")
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty())
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "MissingResource", "sasl_mechanism requires username and password"))
+					})
+					It("should drop outputs with a key that is not a single record field", func() {
+						request.ForwarderSpec.Outputs[0].Secret = nil
+						request.ForwarderSpec.Outputs[0].Kafka = &logging.Kafka{Key: "pod-{.kubernetes.pod_id}"}
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty())
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "must reference a single log record field"))
+					})
+					It("should drop outputs with a header that is not static or a single record field", func() {
+						request.ForwarderSpec.Outputs[0].Secret = nil
+						request.ForwarderSpec.Outputs[0].Kafka = &logging.Kafka{
+							Headers: map[string]string{"ns": "{.kubernetes.namespace_name}-{.log_type}"},
+						}
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty())
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "header \"ns\" must be static or reference a single log record field"))
+					})
+					It("should accept outputs with a record field key and headers", func() {
+						request.ForwarderSpec.Outputs[0].Secret = nil
+						request.ForwarderSpec.Outputs[0].Kafka = &logging.Kafka{
+							Key:     "{.kubernetes.pod_id}",
+							Headers: map[string]string{"ns": "{.kubernetes.namespace_name}", "cluster": "east"},
+						}
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(HaveLen(1))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", true, "", ""))
+					})
+					It("should accept outputs with secrets that have a SCRAM mechanism and credentials", func() {
+						secret.Data["username"] = []byte("user")
+						secret.Data["password"] = []byte("pass")
+						secret.Data["sasl_mechanism"] = []byte("SCRAM-SHA-256")
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(HaveLen(len(request.ForwarderSpec.Outputs)))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", true, "", ""))
+					})
+				})
+
+				Context("for writing to Google Cloud Logging", func() {
+					BeforeEach(func() {
+						request.Cluster.Spec.Collection = &logging.CollectionSpec{
+							Logs: logging.LogCollectionSpec{Type: logging.LogCollectionTypeVector},
+						}
+						output = logging.OutputSpec{
+							Name: "aName",
+							Type: logging.OutputTypeGoogleCloudLogging,
+							OutputTypeSpec: logging.OutputTypeSpec{
+								GoogleCloudLogging: &logging.GoogleCloudLogging{
+									ProjectID: "my-project",
+									LogID:     "app-{.kubernetes.namespace_name}",
+								},
+							},
+							Secret: &logging.OutputSecretSpec{Name: secret.Name},
+						}
+						request.ForwarderSpec.Outputs = []logging.OutputSpec{output}
+					})
+					It("should drop outputs with secrets that are missing the service account credentials", func() {
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty(), fmt.Sprintf("secret %+v", secret))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "MissingResource", "google-application-credentials.json is required"))
+					})
+					It("should drop outputs with secrets that have malformed service account credentials", func() {
+						secret.Data["google-application-credentials.json"] = []byte("{not json")
+						request.Client = fake.NewFakeClient(secret)
+						spec, status := request.NormalizeForwarder()
+						Expect(spec.Outputs).To(BeEmpty(), fmt.Sprintf("secret %+v", secret))
+						Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "google-application-credentials.json is not valid JSON"))
+					})
+					Context("with service account credentials", func() {
+						BeforeEach(func() {
+							secret.Data["google-application-credentials.json"] = []byte(`{"type":"service_account"}`)
+							request.Client = fake.NewFakeClient(secret)
+						})
+						It("should accept outputs targeting exactly one resource", func() {
+							spec, status := request.NormalizeForwarder()
+							Expect(spec.Outputs).To(HaveLen(len(request.ForwarderSpec.Outputs)))
+							Expect(status.Outputs["aName"]).To(HaveCondition("Ready", true, "", ""))
+						})
+						It("should drop outputs targeting more than one resource", func() {
+							request.ForwarderSpec.Outputs[0].GoogleCloudLogging.FolderID = "my-folder"
+							spec, status := request.NormalizeForwarder()
+							Expect(spec.Outputs).To(BeEmpty())
+							Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "exactly one of projectId, folderId, organizationId or billingAccountId is required"))
+						})
+						It("should drop outputs without a log ID", func() {
+							request.ForwarderSpec.Outputs[0].GoogleCloudLogging.LogID = ""
+							spec, status := request.NormalizeForwarder()
+							Expect(spec.Outputs).To(BeEmpty())
+							Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "logId is required"))
+						})
+						Context("when logs are collected by fluentd", func() {
+							BeforeEach(func() {
+								request.Cluster.Spec.Collection.Logs.Type = logging.LogCollectionTypeFluentd
+							})
+							It("should accept outputs targeting a project", func() {
+								spec, status := request.NormalizeForwarder()
+								Expect(spec.Outputs).To(HaveLen(len(request.ForwarderSpec.Outputs)))
+								Expect(status.Outputs["aName"]).To(HaveCondition("Ready", true, "", ""))
+							})
+							It("should drop outputs targeting a folder", func() {
+								request.ForwarderSpec.Outputs[0].GoogleCloudLogging.ProjectID = ""
+								request.ForwarderSpec.Outputs[0].GoogleCloudLogging.FolderID = "my-folder"
+								spec, status := request.NormalizeForwarder()
+								Expect(spec.Outputs).To(BeEmpty())
+								Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "only projectId is supported by the fluentd collector"))
+							})
+							It("should drop outputs using different secrets", func() {
+								other := secret.DeepCopy()
+								other.Name = "other-secret"
+								request.Client = fake.NewFakeClient(secret, other)
+								otherOutput := *output.DeepCopy()
+								otherOutput.Name = "otherName"
+								otherOutput.Secret = &logging.OutputSecretSpec{Name: other.Name}
+								request.ForwarderSpec.Outputs = append(request.ForwarderSpec.Outputs, otherOutput)
+								spec, status := request.NormalizeForwarder()
+								Expect(spec.Outputs).To(BeEmpty())
+								Expect(status.Outputs["aName"]).To(HaveCondition("Ready", false, "Invalid", "must use the same secret with the fluentd collector"))
+								Expect(status.Outputs["otherName"]).To(HaveCondition("Ready", false, "Invalid", "must use the same secret with the fluentd collector"))
+							})
+						})
+					})
 				})
 
 				Context("with certs", func() {
@@ -0,0 +1,133 @@
+package k8shandler
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ViaQ/logerr/log"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	"github.com/openshift/cluster-logging-operator/internal/utils"
+	"github.com/pmezard/go-difflib/difflib"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// PreviewCollectorConfig annotates a ClusterLogForwarder to render the collector configuration
+	// to the preview configmap instead of rolling it out to the collectors. The configuration is shared
+	// by all the forwarders, it is previewed if any ClusterLogForwarder is annotated. The annotation of
+	// a LogForwarder is ignored since a namespace can not hold the rollout of the other forwarders.
+	PreviewCollectorConfig = "clusterlogging.openshift.io/dryrun"
+
+	// CollectorPreviewName is the name of the configmap holding the previewed collector configuration
+	CollectorPreviewName = constants.CollectorName + "-preview"
+
+	previewDiffSuffix = ".diff"
+)
+
+// isDryRun checks if the collector configuration is only to be previewed
+func (clusterRequest *ClusterLoggingRequest) isDryRun() bool {
+	if isDryRunForwarder(clusterRequest.ForwarderRequest) {
+		return true
+	}
+	for _, instance := range clusterRequest.ForwarderInstances {
+		if instance.Kind == logging.ClusterLogForwarderKind && isDryRunForwarder(instance) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDryRunForwarder(forwarder *logging.ClusterLogForwarder) bool {
+	if forwarder == nil {
+		return false
+	}
+	enabled, found := forwarder.Annotations[PreviewCollectorConfig]
+	return found && enabled == "enabled"
+}
+
+// collectorConfigKey returns the key of the collector configuration in the collector configmap
+func (clusterRequest *ClusterLoggingRequest) collectorConfigKey() string {
+	if clusterRequest.isVectorCollector() {
+		return vectorConfigKey
+	}
+	return "fluent.conf"
+}
+
+// createOrUpdatePreviewConfigMap writes the generated collector configuration and a unified diff against
+// the configuration of the collector configmap to the preview configmap
+func (clusterRequest *ClusterLoggingRequest) createOrUpdatePreviewConfigMap(collectorConfig string) error {
+	configKey := clusterRequest.collectorConfigKey()
+	liveConfig := ""
+	live := &v1.ConfigMap{}
+	if err := clusterRequest.Get(constants.CollectorName, live); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("Failed to get %v configmap for %q: %v", constants.CollectorName, clusterRequest.Cluster.Name, err)
+		}
+	} else {
+		liveConfig = live.Data[configKey]
+	}
+
+	diff, err := previewDiff(configKey, liveConfig, collectorConfig)
+	if err != nil {
+		return fmt.Errorf("Failure comparing collector configuration: %v", err)
+	}
+
+	previewConfigMap := NewConfigMap(
+		CollectorPreviewName,
+		clusterRequest.Cluster.Namespace,
+		map[string]string{
+			configKey:                     collectorConfig,
+			configKey + previewDiffSuffix: diff,
+		},
+	)
+
+	utils.AddOwnerRefToObject(previewConfigMap, utils.AsOwner(clusterRequest.Cluster))
+
+	err = clusterRequest.Create(previewConfigMap)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("Failure constructing collector preview configmap: %v", err)
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &v1.ConfigMap{}
+		if err = clusterRequest.Get(previewConfigMap.Name, current); err != nil {
+			if errors.IsNotFound(err) {
+				log.V(2).Info("Returning nil. The configmap was not found even though create previously failed.  Was it culled?", "configmap name", previewConfigMap.Name)
+				return nil
+			}
+			return fmt.Errorf("Failed to get %v configmap for %q: %v", previewConfigMap.Name, clusterRequest.Cluster.Name, err)
+		}
+		if reflect.DeepEqual(previewConfigMap.Data, current.Data) {
+			return nil
+		}
+		current.Data = previewConfigMap.Data
+		return clusterRequest.Update(current)
+	})
+}
+
+// previewDiff returns the unified diff of the live and the generated collector configuration,
+// empty if they are the same
+func previewDiff(configKey, liveConfig, collectorConfig string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(liveConfig),
+		B:        splitLines(collectorConfig),
+		FromFile: constants.CollectorName + "/" + configKey,
+		ToFile:   CollectorPreviewName + "/" + configKey,
+		Context:  3,
+	})
+}
+
+// splitLines splits a configuration into its lines, keeping their line breaks. Unlike difflib.SplitLines
+// there is no empty last line if the configuration ends with a line break
+func splitLines(config string) []string {
+	lines := strings.SplitAfter(config, "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += "\n"
+	}
+	return lines
+}
@@ -0,0 +1,148 @@
+package k8shandler
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Previewing the collector configuration", func() {
+	defer GinkgoRecover()
+
+	_ = logging.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	var (
+		fakeClient     client.Client
+		clusterRequest *ClusterLoggingRequest
+
+		collectorKey = types.NamespacedName{Name: constants.CollectorName, Namespace: constants.OpenshiftNS}
+		previewKey   = types.NamespacedName{Name: CollectorPreviewName, Namespace: constants.OpenshiftNS}
+	)
+
+	getConfigMap := func(key types.NamespacedName) *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(context.TODO(), key, cm)).To(Succeed())
+		return cm
+	}
+	dryRunForwarder := func(kind, namespace string) *logging.ClusterLogForwarder {
+		return &logging.ClusterLogForwarder{
+			TypeMeta: metav1.TypeMeta{Kind: kind},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        constants.SingletonName,
+				Namespace:   namespace,
+				Annotations: map[string]string{PreviewCollectorConfig: "enabled"},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		cluster := &logging.ClusterLogging{
+			ObjectMeta: metav1.ObjectMeta{Name: constants.SingletonName, Namespace: constants.OpenshiftNS},
+			Spec: logging.ClusterLoggingSpec{
+				ManagementState: logging.ManagementStateManaged,
+				Collection: &logging.CollectionSpec{
+					Logs: logging.LogCollectionSpec{Type: logging.LogCollectionTypeFluentd},
+				},
+			},
+		}
+		fakeClient = fake.NewFakeClient(
+			cluster,
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: constants.CollectorTrustedCAName, Namespace: constants.OpenshiftNS},
+				Data:       map[string]string{constants.TrustedCABundleKey: "-----BEGIN CERTIFICATE-----"},
+			},
+		)
+		clusterRequest = &ClusterLoggingRequest{
+			Client:  fakeClient,
+			Cluster: cluster,
+		}
+		Expect(clusterRequest.reconcileFluentdConfig("<source>\n</source>\n", "live")).To(Succeed())
+	})
+
+	Describe("#createOrUpdatePreviewConfigMap", func() {
+		It("should write the configuration and its diff against the live configuration", func() {
+			Expect(clusterRequest.createOrUpdatePreviewConfigMap("<source>\n  @type tail\n</source>\n")).To(Succeed())
+			Expect(getConfigMap(previewKey).Data).To(Equal(map[string]string{
+				"fluent.conf": "<source>\n  @type tail\n</source>\n",
+				"fluent.conf.diff": "--- collector/fluent.conf\n+++ collector-preview/fluent.conf\n" +
+					"@@ -1,2 +1,3 @@\n <source>\n+  @type tail\n </source>\n",
+			}))
+
+			By("updating the preview")
+			Expect(clusterRequest.createOrUpdatePreviewConfigMap("<source>\n</source>\n")).To(Succeed())
+			Expect(getConfigMap(previewKey).Data).To(Equal(map[string]string{
+				"fluent.conf":      "<source>\n</source>\n",
+				"fluent.conf.diff": "",
+			}))
+		})
+
+		It("should diff against an empty configuration when there is no live configuration", func() {
+			Expect(fakeClient.Delete(context.TODO(), getConfigMap(collectorKey))).To(Succeed())
+			Expect(clusterRequest.createOrUpdatePreviewConfigMap("<source>\n</source>\n")).To(Succeed())
+			Expect(getConfigMap(previewKey).Data["fluent.conf.diff"]).To(HaveSuffix("@@ -0,0 +1,2 @@\n+<source>\n+</source>\n"))
+		})
+
+		It("should preview the vector configuration when logs are collected by vector", func() {
+			clusterRequest.Cluster.Spec.Collection.Logs.Type = logging.LogCollectionTypeVector
+			Expect(clusterRequest.createOrUpdatePreviewConfigMap("[sources]\n")).To(Succeed())
+			preview := getConfigMap(previewKey)
+			Expect(preview.Data).To(HaveKeyWithValue(vectorConfigKey, "[sources]\n"))
+			Expect(preview.Data[vectorConfigKey+previewDiffSuffix]).To(HavePrefix("--- collector/vector.toml\n+++ collector-preview/vector.toml\n"))
+		})
+	})
+
+	Describe("#reconcileFluentdConfig", func() {
+		It("should preview the configuration without rolling it out when dry-run is enabled", func() {
+			live := getConfigMap(collectorKey)
+			ds := &appsv1.DaemonSet{}
+			Expect(fakeClient.Get(context.TODO(), collectorKey, ds)).To(Succeed())
+
+			clusterRequest.ForwarderRequest = dryRunForwarder(logging.ClusterLogForwarderKind, constants.OpenshiftNS)
+			Expect(clusterRequest.reconcileFluentdConfig("<source>\n  @type tail\n</source>\n", "preview")).To(Succeed())
+
+			Expect(getConfigMap(previewKey).Data["fluent.conf"]).To(Equal("<source>\n  @type tail\n</source>\n"))
+			Expect(getConfigMap(collectorKey).Data).To(Equal(live.Data))
+			current := &appsv1.DaemonSet{}
+			Expect(fakeClient.Get(context.TODO(), collectorKey, current)).To(Succeed())
+			Expect(current.Spec).To(Equal(ds.Spec))
+		})
+
+		It("should roll out the configuration and remove the preview once dry-run is disabled", func() {
+			clusterRequest.ForwarderRequest = dryRunForwarder(logging.ClusterLogForwarderKind, constants.OpenshiftNS)
+			Expect(clusterRequest.reconcileFluentdConfig("<source>\n  @type tail\n</source>\n", "preview")).To(Succeed())
+
+			delete(clusterRequest.ForwarderRequest.Annotations, PreviewCollectorConfig)
+			Expect(clusterRequest.reconcileFluentdConfig("<source>\n  @type tail\n</source>\n", "preview")).To(Succeed())
+
+			Expect(fakeClient.Get(context.TODO(), previewKey, &corev1.ConfigMap{})).NotTo(Succeed())
+			Expect(getConfigMap(collectorKey).Data["fluent.conf"]).To(Equal("<source>\n  @type tail\n</source>\n"))
+		})
+	})
+
+	Describe("#isDryRun", func() {
+		It("should be enabled by the annotation of any ClusterLogForwarder", func() {
+			Expect(clusterRequest.isDryRun()).To(BeFalse())
+			clusterRequest.ForwarderInstances = []*logging.ClusterLogForwarder{
+				dryRunForwarder(logging.ClusterLogForwarderKind, constants.OpenshiftNS),
+			}
+			Expect(clusterRequest.isDryRun()).To(BeTrue())
+		})
+
+		It("should ignore the annotation of a LogForwarder", func() {
+			clusterRequest.ForwarderInstances = []*logging.ClusterLogForwarder{
+				dryRunForwarder(logging.LogForwarderKind, "app-ns"),
+			}
+			Expect(clusterRequest.isDryRun()).To(BeFalse())
+		})
+	})
+})
@@ -34,6 +34,7 @@ func Reconcile(requestCluster *logging.ClusterLogging, requestClient client.Clie
 		clusterLoggingRequest.ForwarderRequest = forwarder
 		clusterLoggingRequest.ForwarderSpec = forwarder.Spec
 	}
+	clusterLoggingRequest.ForwarderInstances = clusterLoggingRequest.getForwarderInstances()
 
 	if clusterLoggingRequest.IncludesManagedStorage() {
 		// Reconcile certs
@@ -88,34 +89,63 @@ func removeManagedStorage(clusterRequest ClusterLoggingRequest) {
 	}
 }
 
+// ReconcileForClusterLogForwarder reconciles the collection for all forwarders and sets the status of forwarder.
+// A nil forwarder reconciles the collection after a forwarder was removed.
 func ReconcileForClusterLogForwarder(forwarder *logging.ClusterLogForwarder, requestClient client.Client) (err error) {
-	clusterLoggingRequest := ClusterLoggingRequest{
-		Client: requestClient,
+	clusterLoggingRequest, err := reconcileForForwarders(requestClient)
+	if clusterLoggingRequest == nil || forwarder == nil {
+		return err
 	}
-	if forwarder != nil {
-		clusterLoggingRequest.ForwarderRequest = forwarder
-		clusterLoggingRequest.ForwarderSpec = forwarder.Spec
+	if forwarder.Name == constants.SingletonName {
+		forwarder.Status = clusterLoggingRequest.ForwarderRequest.Status
+	} else if instance := clusterLoggingRequest.getForwarderInstance(logging.ClusterLogForwarderKind, forwarder.Namespace, forwarder.Name); instance != nil {
+		forwarder.Status = instance.Status
+	}
+	return err
+}
+
+// ReconcileForLogForwarder reconciles the collection for all forwarders and sets the status of forwarder.
+// A nil forwarder reconciles the collection after a forwarder was removed.
+func ReconcileForLogForwarder(forwarder *logging.LogForwarder, requestClient client.Client) (err error) {
+	clusterLoggingRequest, err := reconcileForForwarders(requestClient)
+	if clusterLoggingRequest == nil || forwarder == nil {
+		return err
+	}
+	if instance := clusterLoggingRequest.getForwarderInstance(logging.LogForwarderKind, forwarder.Namespace, forwarder.Name); instance != nil {
+		forwarder.Status = instance.Status
+	}
+	return err
+}
+
+// reconcileForForwarders reconciles the collection for the singleton ClusterLogForwarder and the forwarder
+// instances. It returns a nil request if there is no managed ClusterLogging instance.
+func reconcileForForwarders(requestClient client.Client) (*ClusterLoggingRequest, error) {
+	clusterLoggingRequest := &ClusterLoggingRequest{
+		Client: requestClient,
 	}
 
 	clusterLogging := clusterLoggingRequest.getClusterLogging()
 	if clusterLogging == nil {
-		return nil
+		return nil, nil
 	}
 	clusterLoggingRequest.Cluster = clusterLogging
 
 	if clusterLogging.Spec.ManagementState == logging.ManagementStateUnmanaged {
-		return nil
+		return nil, nil
 	}
 
+	forwarder := clusterLoggingRequest.getLogForwarder()
+	clusterLoggingRequest.ForwarderRequest = forwarder
+	clusterLoggingRequest.ForwarderSpec = forwarder.Spec
+	clusterLoggingRequest.ForwarderInstances = clusterLoggingRequest.getForwarderInstances()
+
 	// Reconcile Collection
-	err = clusterLoggingRequest.CreateOrUpdateCollection()
-	forwarder.Status = clusterLoggingRequest.ForwarderRequest.Status
-	if err != nil {
+	if err := clusterLoggingRequest.CreateOrUpdateCollection(); err != nil {
 		msg := fmt.Sprintf("Unable to reconcile collection for %q: %v", clusterLoggingRequest.Cluster.Name, err)
 		log.Error(err, msg)
-		return errors.New(msg)
+		return clusterLoggingRequest, errors.New(msg)
 	}
-	return nil
+	return clusterLoggingRequest, nil
 }
 
 func ReconcileForGlobalProxy(proxyConfig *configv1.Proxy, requestClient client.Client) (err error) {
@@ -140,6 +170,7 @@ func ReconcileForGlobalProxy(proxyConfig *configv1.Proxy, requestClient client.C
 		clusterLoggingRequest.ForwarderRequest = forwarder
 		clusterLoggingRequest.ForwarderSpec = forwarder.Spec
 	}
+	clusterLoggingRequest.ForwarderInstances = clusterLoggingRequest.getForwarderInstances()
 
 	// Reconcile Collection
 	if err = clusterLoggingRequest.CreateOrUpdateCollection(); err != nil {
@@ -0,0 +1,472 @@
+package k8shandler
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/log"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	"github.com/prometheus/common/expfmt"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// CollectorCanaryName is the name of the daemonset of the canary collectors and of the configmap
+	// holding their configuration
+	CollectorCanaryName = constants.CollectorName + "-canary"
+
+	// The rollout state is kept in annotations of the collector daemonset
+	rolloutPhaseAnnotation        = "logging.openshift.io/rollout-phase"
+	rolloutCanaryHashAnnotation   = "logging.openshift.io/canary-conf-hash"
+	rolloutCanaryNodesAnnotation  = "logging.openshift.io/canary-nodes"
+	rolloutCanaryStartAnnotation  = "logging.openshift.io/canary-start"
+	rolloutLastGoodHashAnnotation = "logging.openshift.io/last-good-conf-hash"
+	rolloutRevertedHashAnnotation = "logging.openshift.io/reverted-conf-hash"
+
+	rolloutPhaseCanary = "Canary"
+
+	reasonCanaryRollout logging.ConditionReason = "CanaryRollout"
+	reasonReverted      logging.ConditionReason = "Reverted"
+
+	defaultCanaryPercent      = 10
+	defaultHealthCheckSeconds = 300
+
+	outputErrorsMetric = "fluentd_output_status_num_errors"
+)
+
+var rolloutAnnotations = []string{
+	rolloutPhaseAnnotation,
+	rolloutCanaryHashAnnotation,
+	rolloutCanaryNodesAnnotation,
+	rolloutCanaryStartAnnotation,
+	rolloutLastGoodHashAnnotation,
+	rolloutRevertedHashAnnotation,
+}
+
+// collectorOutputErrors returns the number of output errors reported by the metrics of a collector pod
+var collectorOutputErrors = func(pod *v1.Pod) (float64, error) {
+	httpClient := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			// The metrics are served with a certificate of the service CA. Only the error
+			// counters are read, nothing is sent to the collector.
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // #nosec G402
+		},
+	}
+	metricsURL := fmt.Sprintf("https://%s/metrics", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(metricsPort))))
+	resp, err := httpClient.Get(metricsURL)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %q from %s", resp.Status, metricsURL)
+	}
+	families, err := (&expfmt.TextParser{}).TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	errs := 0.0
+	if family, found := families[outputErrorsMetric]; found {
+		for _, metric := range family.Metric {
+			errs += metric.GetGauge().GetValue() + metric.GetCounter().GetValue() + metric.GetUntyped().GetValue()
+		}
+	}
+	return errs, nil
+}
+
+// rolloutPolicy returns the rollout policy of the collector configuration, nil to roll out changes at once.
+// Changes of the vector configuration are always rolled out at once.
+func (clusterRequest *ClusterLoggingRequest) rolloutPolicy() *logging.RolloutPolicySpec {
+	if clusterRequest.Cluster == nil || clusterRequest.Cluster.Spec.Collection == nil || clusterRequest.isVectorCollector() {
+		return nil
+	}
+	return clusterRequest.Cluster.Spec.Collection.Logs.RolloutPolicy
+}
+
+// rolloutFluentd rolls out a change of the collector configuration to the collectors of the canary nodes,
+// and to all collectors once the canary collectors stayed healthy for the health check period.
+// Unhealthy canary collectors are reverted to the last good configuration.
+// The canary collectors are run by their own daemonset pinned to the canary nodes, the collector daemonset
+// keeps running the last good configuration on all other nodes.
+func (clusterRequest *ClusterLoggingRequest) rolloutFluentd(policy *logging.RolloutPolicySpec, collectorConfig, collectorConfHash string) error {
+	current := &apps.DaemonSet{}
+	if err := clusterRequest.Get(constants.CollectorName, current); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("Failed to get collector daemonset: %v", err)
+		}
+		// There are no collectors yet to break
+		return clusterRequest.promoteFluentdConfig(collectorConfig, collectorConfHash)
+	}
+
+	lastGoodHash := current.Annotations[rolloutLastGoodHashAnnotation]
+	if lastGoodHash == "" {
+		lastGoodHash = fluentConfHash(&current.Spec.Template.Spec)
+	}
+	revertedHash := current.Annotations[rolloutRevertedHashAnnotation]
+
+	switch {
+	case collectorConfHash == lastGoodHash:
+		return clusterRequest.promoteFluentdConfig(collectorConfig, collectorConfHash)
+	case collectorConfHash == revertedHash:
+		// Keep the last good configuration until the configuration changes again
+		if err := clusterRequest.RemoveDaemonset(CollectorCanaryName); err != nil {
+			return err
+		}
+		return clusterRequest.updateFluentdRollout(lastGoodHash, nil, map[string]string{
+			rolloutLastGoodHashAnnotation: lastGoodHash,
+			rolloutRevertedHashAnnotation: revertedHash,
+		})
+	case current.Annotations[rolloutPhaseAnnotation] != rolloutPhaseCanary || current.Annotations[rolloutCanaryHashAnnotation] != collectorConfHash:
+		return clusterRequest.startFluentdCanary(policy, collectorConfig, collectorConfHash, lastGoodHash, revertedHash)
+	}
+
+	// Keep the daemonsets in sync with changes other than the configuration
+	state := map[string]string{}
+	for _, name := range rolloutAnnotations {
+		if value, found := current.Annotations[name]; found {
+			state[name] = value
+		}
+	}
+	canaryNodes := sets.NewString(strings.Split(state[rolloutCanaryNodesAnnotation], ",")...)
+	if err := clusterRequest.updateFluentdRollout(lastGoodHash, canaryNodes, state); err != nil {
+		return err
+	}
+	if err := clusterRequest.updateFluentdCanary(collectorConfig, collectorConfHash, canaryNodes); err != nil {
+		return err
+	}
+
+	ready, err := clusterRequest.checkFluentdCanary(policy, collectorConfHash, canaryNodes)
+	if err != nil {
+		return clusterRequest.revertFluentdConfig(collectorConfHash, lastGoodHash, err.Error())
+	}
+
+	start, err := time.Parse(time.RFC3339, state[rolloutCanaryStartAnnotation])
+	if err != nil {
+		return clusterRequest.startFluentdCanary(policy, collectorConfig, collectorConfHash, lastGoodHash, revertedHash)
+	}
+	healthCheck := time.Duration(policy.HealthCheckSeconds) * time.Second
+	if policy.HealthCheckSeconds == 0 {
+		healthCheck = defaultHealthCheckSeconds * time.Second
+	}
+	if time.Since(start) < healthCheck {
+		log.V(3).Info("Waiting for canary collectors to pass the health check", "hash", collectorConfHash, "ready", ready)
+		return nil
+	}
+	if !ready {
+		return clusterRequest.revertFluentdConfig(collectorConfHash, lastGoodHash, fmt.Sprintf("canary collectors not ready after %v", healthCheck))
+	}
+	return clusterRequest.promoteFluentdConfig(collectorConfig, collectorConfHash)
+}
+
+// startFluentdCanary rolls out the configuration to the collectors of the canary nodes
+func (clusterRequest *ClusterLoggingRequest) startFluentdCanary(policy *logging.RolloutPolicySpec, collectorConfig, collectorConfHash, lastGoodHash, revertedHash string) error {
+	canaryNodes, err := clusterRequest.getCanaryNodes(policy)
+	if err != nil {
+		return err
+	}
+	if canaryNodes.Len() == 0 {
+		return fmt.Errorf("No collector runs on a canary node, unable to roll out the collector configuration")
+	}
+
+	state := map[string]string{
+		rolloutPhaseAnnotation:        rolloutPhaseCanary,
+		rolloutCanaryHashAnnotation:   collectorConfHash,
+		rolloutCanaryNodesAnnotation:  strings.Join(canaryNodes.List(), ","),
+		rolloutCanaryStartAnnotation:  time.Now().UTC().Format(time.RFC3339),
+		rolloutLastGoodHashAnnotation: lastGoodHash,
+	}
+	if revertedHash != "" {
+		state[rolloutRevertedHashAnnotation] = revertedHash
+	}
+	// The collectors of the other nodes keep running the last good configuration
+	if err = clusterRequest.updateFluentdRollout(lastGoodHash, canaryNodes, state); err != nil {
+		return err
+	}
+	if err = clusterRequest.updateFluentdCanary(collectorConfig, collectorConfHash, canaryNodes); err != nil {
+		return err
+	}
+	if _, err = clusterRequest.checkFluentdCanary(policy, collectorConfHash, canaryNodes); err != nil {
+		log.V(2).Info("Canary collectors are not healthy yet", "hash", collectorConfHash, "reason", err.Error())
+	}
+
+	log.Info("Rolling out collector configuration to canary nodes", "hash", collectorConfHash, "nodes", canaryNodes.List())
+	return clusterRequest.updateRolloutCondition(v1.ConditionTrue, reasonCanaryRollout,
+		fmt.Sprintf("rolling out collector configuration %s to canary nodes: %s", collectorConfHash, strings.Join(canaryNodes.List(), ", ")))
+}
+
+// checkFluentdCanary returns true if the collectors of all canary nodes run the canary configuration and are ready,
+// an error if a canary collector restarted or reported more output errors than tolerated.
+func (clusterRequest *ClusterLoggingRequest) checkFluentdCanary(policy *logging.RolloutPolicySpec, collectorConfHash string, canaryNodes sets.String) (bool, error) {
+	podList, err := clusterRequest.GetPodList(map[string]string{"component": constants.CollectorName})
+	if err != nil {
+		return false, fmt.Errorf("Failed to list collector pods: %v", err)
+	}
+
+	ready := true
+	found := sets.NewString()
+	outputErrors := 0.0
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !canaryNodes.Has(pod.Spec.NodeName) || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if fluentConfHash(&pod.Spec) != collectorConfHash {
+			// the collector is yet to be replaced by a canary collector
+			ready = false
+			continue
+		}
+		found.Insert(pod.Spec.NodeName)
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == constants.CollectorName && status.RestartCount > 0 {
+				return false, fmt.Errorf("canary collector restarted on node %s", pod.Spec.NodeName)
+			}
+		}
+		if len(pod.Status.ContainerStatuses) == 0 || !isPodReady(*pod) {
+			ready = false
+			continue
+		}
+		errs, err := collectorOutputErrors(pod)
+		if err != nil {
+			log.V(3).Info("Unable to read metrics of canary collector", "pod", pod.Name, "error", err.Error())
+			ready = false
+			continue
+		}
+		outputErrors += errs
+	}
+	if outputErrors > float64(policy.MaxOutputErrors) {
+		return false, fmt.Errorf("canary collectors reported %v output errors", outputErrors)
+	}
+	return ready && found.Equal(canaryNodes), nil
+}
+
+// promoteFluentdConfig rolls out the configuration to all collectors and records it as the last good configuration
+func (clusterRequest *ClusterLoggingRequest) promoteFluentdConfig(collectorConfig, collectorConfHash string) error {
+	if err := clusterRequest.createOrUpdateFluentdConfigMap(constants.CollectorName, collectorConfig); err != nil {
+		return err
+	}
+	if err := clusterRequest.removeFluentdCanary(); err != nil {
+		return err
+	}
+	if err := clusterRequest.updateFluentdRollout(collectorConfHash, nil, map[string]string{
+		rolloutLastGoodHashAnnotation: collectorConfHash,
+	}); err != nil {
+		return err
+	}
+	if clusterRequest.Cluster.Status.Conditions.RemoveCondition(logging.CollectorRollout) {
+		log.Info("Rolled out collector configuration to all nodes", "hash", collectorConfHash)
+		return clusterRequest.UpdateStatus(clusterRequest.Cluster)
+	}
+	return nil
+}
+
+// revertFluentdConfig reverts the canary collectors to the last good configuration
+func (clusterRequest *ClusterLoggingRequest) revertFluentdConfig(collectorConfHash, lastGoodHash, reason string) error {
+	log.Info("Reverting collector configuration", "hash", collectorConfHash, "lastGoodHash", lastGoodHash, "reason", reason)
+	if err := clusterRequest.removeFluentdCanary(); err != nil {
+		return err
+	}
+	if err := clusterRequest.updateFluentdRollout(lastGoodHash, nil, map[string]string{
+		rolloutLastGoodHashAnnotation: lastGoodHash,
+		rolloutRevertedHashAnnotation: collectorConfHash,
+	}); err != nil {
+		return err
+	}
+	return clusterRequest.updateRolloutCondition(v1.ConditionTrue, reasonReverted,
+		fmt.Sprintf("reverted collector configuration %s to %s: %s", collectorConfHash, lastGoodHash, reason))
+}
+
+// updateFluentdRollout updates the collector daemonset to run the configuration with the given hash, except on
+// the canary nodes, keeping the rollout state in its annotations
+func (clusterRequest *ClusterLoggingRequest) updateFluentdRollout(collectorConfHash string, canaryNodes sets.String, state map[string]string) error {
+	fluentdDaemonset, err := clusterRequest.newFluentdDaemonset(collectorConfHash)
+	if err != nil {
+		return err
+	}
+	if canaryNodes.Len() > 0 {
+		requireNodes(&fluentdDaemonset.Spec.Template.Spec, v1.NodeSelectorOpNotIn, canaryNodes)
+	}
+	fluentdDaemonset.Annotations = state
+	return clusterRequest.applyFluentdDaemonset(fluentdDaemonset)
+}
+
+// updateFluentdCanary updates the canary daemonset to run the configuration with the given hash on the canary nodes.
+// The canary collectors share the buffers and the positions of the tailed logs of a node with the collector they
+// replace, they only run on the canary nodes where the collector of the collector daemonset is gone
+func (clusterRequest *ClusterLoggingRequest) updateFluentdCanary(collectorConfig, collectorConfHash string, canaryNodes sets.String) error {
+	if err := clusterRequest.createOrUpdateFluentdConfigMap(CollectorCanaryName, collectorConfig); err != nil {
+		return err
+	}
+	collectorNodes, err := clusterRequest.getPodNodes(map[string]string{"logging-infra": constants.CollectorName})
+	if err != nil {
+		return err
+	}
+	nodes := canaryNodes.Difference(collectorNodes)
+	if nodes.Len() == 0 {
+		log.V(3).Info("Waiting for the collectors of the canary nodes to terminate", "nodes", canaryNodes.List())
+		return clusterRequest.RemoveDaemonset(CollectorCanaryName)
+	}
+	canaryDaemonset, err := clusterRequest.newFluentdDaemonset(collectorConfHash)
+	if err != nil {
+		return err
+	}
+	canaryDaemonset.Name = CollectorCanaryName
+	// The selectors of the daemonsets must not overlap, the canary pods keep the component label to be
+	// served by the collector metrics service
+	canaryDaemonset.Labels = withCanaryLabel(canaryDaemonset.Labels)
+	canaryDaemonset.Spec.Selector = &metav1.LabelSelector{MatchLabels: withCanaryLabel(canaryDaemonset.Spec.Selector.MatchLabels)}
+	canaryDaemonset.Spec.Template.Labels = withCanaryLabel(canaryDaemonset.Spec.Template.Labels)
+	volumes := canaryDaemonset.Spec.Template.Spec.Volumes
+	for i := range volumes {
+		if volumes[i].Name == config && volumes[i].ConfigMap != nil {
+			volumes[i].ConfigMap.Name = CollectorCanaryName
+		}
+	}
+	requireNodes(&canaryDaemonset.Spec.Template.Spec, v1.NodeSelectorOpIn, nodes)
+	return clusterRequest.applyFluentdDaemonset(canaryDaemonset)
+}
+
+// withCanaryLabel returns a copy of the labels of the collector daemonset with the logging-infra label
+// of the canary daemonset
+func withCanaryLabel(labels map[string]string) map[string]string {
+	canary := map[string]string{}
+	for key, value := range labels {
+		canary[key] = value
+	}
+	canary["logging-infra"] = CollectorCanaryName
+	return canary
+}
+
+// getPodNodes returns the nodes of the pods with the given labels, including terminating pods
+func (clusterRequest *ClusterLoggingRequest) getPodNodes(selector map[string]string) (sets.String, error) {
+	podList, err := clusterRequest.GetPodList(selector)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list collector pods: %v", err)
+	}
+	nodes := sets.NewString()
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName != "" {
+			nodes.Insert(pod.Spec.NodeName)
+		}
+	}
+	return nodes, nil
+}
+
+// removeFluentdCanary removes the canary daemonset and its configuration
+func (clusterRequest *ClusterLoggingRequest) removeFluentdCanary() error {
+	if err := clusterRequest.RemoveDaemonset(CollectorCanaryName); err != nil {
+		return err
+	}
+	return clusterRequest.RemoveConfigMap(CollectorCanaryName)
+}
+
+// requireNodes adds a node affinity requiring the pods to run, or not to run, on the given nodes to
+// every node selector term of the pod spec
+func requireNodes(podSpec *v1.PodSpec, operator v1.NodeSelectorOperator, nodes sets.String) {
+	requirement := v1.NodeSelectorRequirement{Key: "metadata.name", Operator: operator, Values: nodes.List()}
+	affinity := podSpec.Affinity.DeepCopy()
+	if affinity == nil {
+		affinity = &v1.Affinity{}
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &v1.NodeAffinity{}
+	}
+	required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		required = &v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{{}}}
+	}
+	for i := range required.NodeSelectorTerms {
+		required.NodeSelectorTerms[i].MatchFields = append(required.NodeSelectorTerms[i].MatchFields, requirement)
+	}
+	affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = required
+	podSpec.Affinity = affinity
+}
+
+func (clusterRequest *ClusterLoggingRequest) updateRolloutCondition(status v1.ConditionStatus, reason logging.ConditionReason, message string) error {
+	return clusterRequest.UpdateCondition(logging.CollectorRollout, message, reason, status)
+}
+
+// getCanaryNodes returns the nodes running a collector which are selected by the canary node selector,
+// or the given percentage of them
+func (clusterRequest *ClusterLoggingRequest) getCanaryNodes(policy *logging.RolloutPolicySpec) (sets.String, error) {
+	collectorNodes, err := clusterRequest.getPodNodes(map[string]string{"component": constants.CollectorName})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(policy.CanaryNodeSelector) > 0 {
+		nodeList := &v1.NodeList{}
+		if err = clusterRequest.Client.List(context.TODO(), nodeList, client.MatchingLabels(policy.CanaryNodeSelector)); err != nil {
+			return nil, fmt.Errorf("Failed to list canary nodes: %v", err)
+		}
+		canaryNodes := sets.NewString()
+		for _, node := range nodeList.Items {
+			if collectorNodes.Has(node.Name) {
+				canaryNodes.Insert(node.Name)
+			}
+		}
+		return canaryNodes, nil
+	}
+
+	percent := policy.CanaryPercent
+	if percent == 0 {
+		percent = defaultCanaryPercent
+	}
+	nodes := collectorNodes.List()
+	sort.Strings(nodes)
+	count := int(math.Ceil(float64(len(nodes)) * float64(percent) / 100))
+	return sets.NewString(nodes[:count]...), nil
+}
+
+// fluentConfHash returns the hash of the configuration run by the collector container of a pod spec
+func fluentConfHash(podSpec *v1.PodSpec) string {
+	for _, container := range podSpec.Containers {
+		if container.Name != constants.CollectorName {
+			continue
+		}
+		for _, env := range container.Env {
+			if env.Name == "FLUENT_CONF_HASH" {
+				return env.Value
+			}
+		}
+	}
+	return ""
+}
+
+// isRolloutStateSame compares the rollout state annotations of the collector daemonsets
+func isRolloutStateSame(current, desired *apps.DaemonSet) bool {
+	for _, name := range rolloutAnnotations {
+		if current.Annotations[name] != desired.Annotations[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// setRolloutState sets the rollout state annotations of the current collector daemonset to the desired ones
+func setRolloutState(current, desired *apps.DaemonSet) {
+	for _, name := range rolloutAnnotations {
+		if value, found := desired.Annotations[name]; found {
+			if current.Annotations == nil {
+				current.Annotations = map[string]string{}
+			}
+			current.Annotations[name] = value
+		} else {
+			delete(current.Annotations, name)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package k8shandler
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Rolling out the collector configuration", func() {
+	defer GinkgoRecover()
+
+	_ = logging.SchemeBuilder.AddToScheme(scheme.Scheme)
+
+	var (
+		fakeClient     client.Client
+		clusterRequest *ClusterLoggingRequest
+		outputErrors   float64
+
+		collectorKey = types.NamespacedName{Name: constants.CollectorName, Namespace: constants.OpenshiftNS}
+		canaryKey    = types.NamespacedName{Name: CollectorCanaryName, Namespace: constants.OpenshiftNS}
+	)
+
+	newCollectorPod := func(node, hash string, restarts int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      constants.CollectorName + "-" + node + "-" + hash,
+				Namespace: constants.OpenshiftNS,
+				Labels:    map[string]string{"component": constants.CollectorName, "logging-infra": constants.CollectorName},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: node,
+				Containers: []corev1.Container{
+					{Name: constants.CollectorName, Env: []corev1.EnvVar{{Name: "FLUENT_CONF_HASH", Value: hash}}},
+				},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: constants.CollectorName, Ready: true, RestartCount: restarts},
+				},
+			},
+		}
+	}
+	getDaemonSet := func() *appsv1.DaemonSet {
+		ds := &appsv1.DaemonSet{}
+		Expect(fakeClient.Get(context.TODO(), collectorKey, ds)).To(Succeed())
+		return ds
+	}
+	requiredNodes := func(ds *appsv1.DaemonSet) []corev1.NodeSelectorRequirement {
+		affinity := ds.Spec.Template.Spec.Affinity
+		if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+			return nil
+		}
+		return affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchFields
+	}
+	getConfig := func(key types.NamespacedName) string {
+		cm := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(context.TODO(), key, cm)).To(Succeed())
+		return cm.Data["fluent.conf"]
+	}
+	configMapName := func(ds *appsv1.DaemonSet) string {
+		for _, volume := range ds.Spec.Template.Spec.Volumes {
+			if volume.Name == config {
+				return volume.ConfigMap.Name
+			}
+		}
+		return ""
+	}
+	// startCanaryPod replaces the collector of the canary node by a canary collector, as the daemonset controllers do
+	startCanaryPod := func(restarts int32) {
+		Expect(fakeClient.Delete(context.TODO(), newCollectorPod("node-a", "good", 0))).To(Succeed())
+		pod := newCollectorPod("node-a", "new", restarts)
+		pod.Labels["logging-infra"] = CollectorCanaryName
+		Expect(fakeClient.Create(context.TODO(), pod)).To(Succeed())
+	}
+	expireHealthCheck := func() {
+		ds := getDaemonSet()
+		ds.Annotations[rolloutCanaryStartAnnotation] = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		Expect(fakeClient.Update(context.TODO(), ds)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		outputErrors = 0
+		collectorOutputErrors = func(pod *corev1.Pod) (float64, error) {
+			return outputErrors, nil
+		}
+		cluster := &logging.ClusterLogging{
+			ObjectMeta: metav1.ObjectMeta{Name: constants.SingletonName, Namespace: constants.OpenshiftNS},
+			Spec: logging.ClusterLoggingSpec{
+				ManagementState: logging.ManagementStateManaged,
+				Collection: &logging.CollectionSpec{
+					Logs: logging.LogCollectionSpec{
+						Type:          logging.LogCollectionTypeFluentd,
+						RolloutPolicy: &logging.RolloutPolicySpec{CanaryPercent: 50, HealthCheckSeconds: 60},
+					},
+				},
+			},
+		}
+		fakeClient = fake.NewFakeClient(
+			cluster,
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: constants.CollectorTrustedCAName, Namespace: constants.OpenshiftNS},
+				Data:       map[string]string{constants.TrustedCABundleKey: "-----BEGIN CERTIFICATE-----"},
+			},
+			newCollectorPod("node-a", "good", 0),
+			newCollectorPod("node-b", "good", 0),
+		)
+		clusterRequest = &ClusterLoggingRequest{
+			Client:  fakeClient,
+			Cluster: cluster,
+		}
+		Expect(clusterRequest.reconcileFluentdConfig("good conf", "good")).To(Succeed())
+		Expect(getDaemonSet().Annotations[rolloutLastGoodHashAnnotation]).To(Equal("good"))
+		Expect(clusterRequest.reconcileFluentdConfig("new conf", "new")).To(Succeed())
+	})
+	AfterEach(func() {
+		collectorOutputErrors = nil
+	})
+
+	It("should roll out a change to the canary nodes only", func() {
+		ds := getDaemonSet()
+		Expect(ds.Spec.UpdateStrategy.Type).To(Equal(appsv1.RollingUpdateDaemonSetStrategyType))
+		Expect(fluentConfHash(&ds.Spec.Template.Spec)).To(Equal("good"), "Exp. the other nodes to keep the last good configuration")
+		Expect(configMapName(ds)).To(Equal(constants.CollectorName))
+		Expect(requiredNodes(ds)).To(Equal([]corev1.NodeSelectorRequirement{
+			{Key: "metadata.name", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"node-a"}},
+		}))
+		Expect(ds.Annotations).To(HaveKeyWithValue(rolloutPhaseAnnotation, rolloutPhaseCanary))
+		Expect(ds.Annotations).To(HaveKeyWithValue(rolloutCanaryNodesAnnotation, "node-a"))
+
+		Expect(getConfig(collectorKey)).To(Equal("good conf"))
+		Expect(getConfig(canaryKey)).To(Equal("new conf"))
+		Expect(clusterRequest.Cluster.Status.Conditions.IsTrueFor(logging.CollectorRollout)).To(BeTrue())
+
+		By("waiting for the collector of the canary node to terminate")
+		Expect(fakeClient.Get(context.TODO(), canaryKey, &appsv1.DaemonSet{})).NotTo(Succeed())
+		Expect(fakeClient.Delete(context.TODO(), newCollectorPod("node-a", "good", 0))).To(Succeed())
+		Expect(clusterRequest.reconcileFluentdConfig("new conf", "new")).To(Succeed())
+
+		canary := &appsv1.DaemonSet{}
+		Expect(fakeClient.Get(context.TODO(), canaryKey, canary)).To(Succeed())
+		Expect(fluentConfHash(&canary.Spec.Template.Spec)).To(Equal("new"))
+		Expect(configMapName(canary)).To(Equal(CollectorCanaryName))
+		Expect(requiredNodes(canary)).To(Equal([]corev1.NodeSelectorRequirement{
+			{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-a"}},
+		}))
+
+		By("selecting pods which are not selected by the collector daemonset")
+		ds = getDaemonSet()
+		Expect(canary.Labels).To(HaveKeyWithValue("logging-infra", CollectorCanaryName))
+		Expect(canary.Spec.Selector.MatchLabels).To(HaveKeyWithValue("logging-infra", CollectorCanaryName))
+		Expect(canary.Spec.Template.Labels).To(HaveKeyWithValue("logging-infra", CollectorCanaryName))
+		Expect(canary.Spec.Template.Labels).To(HaveKeyWithValue("component", constants.CollectorName))
+		Expect(ds.Spec.Selector.MatchLabels).To(HaveKeyWithValue("logging-infra", constants.CollectorName))
+		Expect(ds.Spec.Template.Labels).To(HaveKeyWithValue("logging-infra", constants.CollectorName))
+	})
+
+	It("should select the canary nodes running a collector by their labels", func() {
+		for name, labels := range map[string]map[string]string{
+			"node-a": {},
+			"node-b": {"canary": "true"},
+			"node-c": {"canary": "true"},
+		} {
+			Expect(fakeClient.Create(context.TODO(), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}})).To(Succeed())
+		}
+		nodes, err := clusterRequest.getCanaryNodes(&logging.RolloutPolicySpec{CanaryNodeSelector: map[string]string{"canary": "true"}})
+		Expect(err).To(BeNil())
+		Expect(nodes.List()).To(Equal([]string{"node-b"}), "Exp. only canary nodes running a collector")
+	})
+
+	It("should roll out a change to all nodes once the canary collectors are healthy", func() {
+		startCanaryPod(0)
+		Expect(clusterRequest.reconcileFluentdConfig("new conf", "new")).To(Succeed())
+		Expect(getDaemonSet().Annotations).To(HaveKeyWithValue(rolloutPhaseAnnotation, rolloutPhaseCanary), "Exp. to wait for the health check")
+
+		expireHealthCheck()
+		Expect(clusterRequest.reconcileFluentdConfig("new conf", "new")).To(Succeed())
+
+		ds := getDaemonSet()
+		Expect(ds.Spec.UpdateStrategy.Type).To(Equal(appsv1.RollingUpdateDaemonSetStrategyType))
+		Expect(fluentConfHash(&ds.Spec.Template.Spec)).To(Equal("new"))
+		Expect(configMapName(ds)).To(Equal(constants.CollectorName))
+		Expect(requiredNodes(ds)).To(BeEmpty())
+		Expect(ds.Annotations).To(Equal(map[string]string{rolloutLastGoodHashAnnotation: "new"}))
+		Expect(getConfig(collectorKey)).To(Equal("new conf"))
+		Expect(fakeClient.Get(context.TODO(), canaryKey, &corev1.ConfigMap{})).NotTo(Succeed())
+		Expect(fakeClient.Get(context.TODO(), canaryKey, &appsv1.DaemonSet{})).NotTo(Succeed())
+		Expect(clusterRequest.Cluster.Status.Conditions.GetCondition(logging.CollectorRollout)).To(BeNil())
+	})
+
+	It("should revert to the last good configuration on output errors", func() {
+		startCanaryPod(0)
+		outputErrors = 1
+		Expect(clusterRequest.reconcileFluentdConfig("new conf", "new")).To(Succeed())
+
+		ds := getDaemonSet()
+		Expect(ds.Spec.UpdateStrategy.Type).To(Equal(appsv1.RollingUpdateDaemonSetStrategyType))
+		Expect(fluentConfHash(&ds.Spec.Template.Spec)).To(Equal("good"))
+		Expect(configMapName(ds)).To(Equal(constants.CollectorName))
+		Expect(requiredNodes(ds)).To(BeEmpty())
+		Expect(ds.Annotations).To(Equal(map[string]string{
+			rolloutLastGoodHashAnnotation: "good",
+			rolloutRevertedHashAnnotation: "new",
+		}))
+		Expect(getConfig(collectorKey)).To(Equal("good conf"))
+		Expect(fakeClient.Get(context.TODO(), canaryKey, &appsv1.DaemonSet{})).NotTo(Succeed())
+		cond := clusterRequest.Cluster.Status.Conditions.GetCondition(logging.CollectorRollout)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(reasonReverted))
+		Expect(cond.Message).To(Equal("reverted collector configuration new to good: canary collectors reported 1 output errors"))
+
+		By("keeping the last good configuration until the configuration changes")
+		Expect(clusterRequest.reconcileFluentdConfig("new conf", "new")).To(Succeed())
+		Expect(fluentConfHash(&getDaemonSet().Spec.Template.Spec)).To(Equal("good"))
+		Expect(clusterRequest.reconcileFluentdConfig("newer conf", "newer")).To(Succeed())
+		Expect(getDaemonSet().Annotations).To(HaveKeyWithValue(rolloutCanaryHashAnnotation, "newer"))
+	})
+
+	It("should revert to the last good configuration when a canary collector restarts", func() {
+		startCanaryPod(1)
+		Expect(clusterRequest.reconcileFluentdConfig("new conf", "new")).To(Succeed())
+		Expect(getDaemonSet().Annotations).To(HaveKeyWithValue(rolloutRevertedHashAnnotation, "new"))
+		Expect(clusterRequest.Cluster.Status.Conditions.GetCondition(logging.CollectorRollout).Message).To(HaveSuffix("canary collector restarted on node node-a"))
+	})
+
+	It("should revert to the last good configuration when the canary collectors are not ready in time", func() {
+		expireHealthCheck()
+		Expect(clusterRequest.reconcileFluentdConfig("new conf", "new")).To(Succeed())
+		Expect(fluentConfHash(&getDaemonSet().Spec.Template.Spec)).To(Equal("good"))
+		Expect(clusterRequest.Cluster.Status.Conditions.GetCondition(logging.CollectorRollout).Message).To(HaveSuffix("canary collectors not ready after 1m0s"))
+	})
+})
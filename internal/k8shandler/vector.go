@@ -0,0 +1,245 @@
+package k8shandler
+
+import (
+	"fmt"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	vectoroutput "github.com/openshift/cluster-logging-operator/internal/generator/vector/output"
+	"github.com/openshift/cluster-logging-operator/internal/utils"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	vectorConfigKey        = "vector.toml"
+	vectorConfigValue      = "/etc/vector"
+	vectorDataDir          = "datadir"
+	vectorDataDirValue     = "/var/lib/vector"
+	vectorConfHashEnvVar   = "VECTOR_CONF_HASH"
+	vectorSelfNodeEnvVar   = "VECTOR_SELF_NODE_NAME"
+	vectorSelfPodEnvVar    = "VECTOR_SELF_POD_NAME"
+	vectorSelfPodNamespace = "VECTOR_SELF_POD_NAMESPACE"
+)
+
+// createOrUpdateVectorConfigMap creates or updates the configmap holding the vector.toml of the collector
+func (clusterRequest *ClusterLoggingRequest) createOrUpdateVectorConfigMap(vectorConf string) error {
+	return clusterRequest.createOrUpdateCollectorConfigMap(constants.CollectorName, map[string]string{
+		vectorConfigKey: vectorConf,
+	})
+}
+
+// createOrUpdateVectorDaemonset creates or updates the collector daemonset running vector
+func (clusterRequest *ClusterLoggingRequest) createOrUpdateVectorDaemonset(pipelineConfHash string) error {
+	cluster := clusterRequest.Cluster
+
+	vectorTrustBundle, err := clusterRequest.createOrGetTrustedCABundleConfigMap(constants.CollectorTrustedCAName)
+	if err != nil {
+		return err
+	}
+
+	vectorPodSpec := newVectorPodSpec(cluster, vectorTrustBundle, clusterRequest.ForwarderSpec)
+
+	vectorDaemonset := NewDaemonSet(constants.CollectorName, cluster.Namespace, constants.CollectorName, constants.CollectorName, vectorPodSpec)
+	vectorDaemonset.Spec.Template.Spec.Containers[0].Env = updateEnvVar(v1.EnvVar{Name: vectorConfHashEnvVar, Value: pipelineConfHash}, vectorDaemonset.Spec.Template.Spec.Containers[0].Env)
+	if cluster.Spec.Collection != nil {
+		customizeFluentdDaemonset(vectorDaemonset, cluster.Spec.Collection.Logs.FluentdSpec)
+	}
+
+	trustedCAHashValue, err := clusterRequest.getTrustedCABundleHash()
+	if err != nil {
+		return err
+	}
+	vectorDaemonset.Spec.Template.Annotations[constants.TrustedCABundleHashName] = trustedCAHashValue
+
+	uid := getServiceAccountLogCollectorUID()
+	if len(uid) == 0 {
+		utils.AddOwnerRefToObject(vectorDaemonset, utils.AsOwner(cluster))
+	} else {
+		utils.AddOwnerRefToObject(vectorDaemonset, NewLogCollectorServiceAccountRef(uid))
+	}
+
+	return clusterRequest.applyFluentdDaemonset(vectorDaemonset)
+}
+
+// newVectorPodSpec returns the pod spec of the collector running vector. The resources, scheduling
+// and pod customization are taken from the fluentd spec of the collection, which applies to any collector.
+func newVectorPodSpec(cluster *logging.ClusterLogging, trustedCABundleCM *v1.ConfigMap, pipelineSpec logging.ClusterLogForwarderSpec) v1.PodSpec {
+	collectionSpec := logging.CollectionSpec{}
+	if cluster.Spec.Collection != nil {
+		collectionSpec = *cluster.Spec.Collection
+	}
+	collectorSpec := collectionSpec.Logs.FluentdSpec
+	resources := collectorSpec.Resources
+	if resources == nil {
+		resources = &v1.ResourceRequirements{
+			Limits: v1.ResourceList{v1.ResourceMemory: defaultFluentdMemory},
+			Requests: v1.ResourceList{
+				v1.ResourceMemory: defaultFluentdMemory,
+				v1.ResourceCPU:    defaultFluentdCpuRequest,
+			},
+		}
+	}
+	vectorContainer := NewContainer(constants.CollectorName, constants.VectorName, v1.PullIfNotPresent, *resources)
+	vectorContainer.Command = []string{"vector", "--config-toml", fmt.Sprintf("%s/%s", vectorConfigValue, vectorConfigKey)}
+
+	exporterContainer := NewContainer(constants.LogfilesmetricexporterName, constants.LogfilesmetricexporterName, v1.PullIfNotPresent, v1.ResourceRequirements{})
+	exporterContainer.Ports = []v1.ContainerPort{
+		{
+			Name:          exporterPortName,
+			ContainerPort: exporterPort,
+			Protocol:      v1.ProtocolTCP,
+		},
+	}
+	exporterContainer.VolumeMounts = []v1.VolumeMount{
+		{Name: logVolumeMountName, MountPath: logVolumePath},
+		{Name: metricsVolumeName, MountPath: metricsVolumeValue},
+	}
+	exporterContainer.Command = []string{"/usr/local/bin/log-file-metric-exporter", "  -verbosity=2", " -dir=/var/log/containers", " -http=:2112", " -keyFile=/etc/fluent/metrics/tls.key", " -crtFile=/etc/fluent/metrics/tls.crt"}
+
+	vectorContainer.Env = []v1.EnvVar{
+		{Name: vectorSelfNodeEnvVar, ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "spec.nodeName"}}},
+		{Name: vectorSelfPodEnvVar, ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "metadata.name"}}},
+		{Name: vectorSelfPodNamespace, ValueFrom: &v1.EnvVarSource{FieldRef: &v1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "metadata.namespace"}}},
+	}
+	vectorContainer.Env = append(vectorContainer.Env, utils.GetProxyEnvVars()...)
+	envNames := sets.NewString()
+	for _, env := range vectorContainer.Env {
+		envNames.Insert(env.Name)
+	}
+	for _, env := range collectorSpec.Env {
+		if !envNames.Has(env.Name) {
+			vectorContainer.Env = append(vectorContainer.Env, env)
+		}
+	}
+
+	vectorContainer.VolumeMounts = []v1.VolumeMount{
+		{Name: logContainers, ReadOnly: true, MountPath: logContainersValue},
+		{Name: logPods, ReadOnly: true, MountPath: logPodsValue},
+		{Name: logJournal, ReadOnly: true, MountPath: logJournalValue},
+		{Name: logAudit, ReadOnly: true, MountPath: logAuditValue},
+		{Name: logOvn, ReadOnly: true, MountPath: logOvnValue},
+		{Name: logOauthapiserver, ReadOnly: true, MountPath: logOauthapiserverValue},
+		{Name: logOpenshiftapiserver, ReadOnly: true, MountPath: logOpenshiftapiserverValue},
+		{Name: logKubeapiserver, ReadOnly: true, MountPath: logKubeapiserverValue},
+		{Name: config, ReadOnly: true, MountPath: vectorConfigValue},
+		{Name: certs, ReadOnly: true, MountPath: certsValue},
+		{Name: localtime, ReadOnly: true, MountPath: localtimeValue},
+		{Name: vectorDataDir, MountPath: vectorDataDirValue},
+		{Name: tmp, MountPath: tmpValue},
+	}
+
+	// List of _unique_ output secret names, several outputs may use the same secret.
+	unique := sets.NewString()
+	for _, o := range pipelineSpec.Outputs {
+		if o.Secret != nil && o.Secret.Name != "" {
+			unique.Insert(o.Secret.Name)
+		}
+	}
+	secretNames := unique.List()
+	for _, name := range secretNames {
+		vectorContainer.VolumeMounts = append(vectorContainer.VolumeMounts, v1.VolumeMount{Name: name, ReadOnly: true, MountPath: fmt.Sprintf("%s/%s", constants.CollectorSecretsDir, name)})
+		// credentials are interpolated from the environment so they are not written to the configmap
+		for _, key := range vectoroutput.SecretEnvKeys {
+			vectorContainer.Env = append(vectorContainer.Env, v1.EnvVar{
+				Name: vectoroutput.SecretEnvVar(name, key),
+				ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: name},
+					Key:                  key,
+					Optional:             utils.GetBool(true),
+				}},
+			})
+		}
+	}
+
+	addTrustedCAVolume := trustedCABundleCM != nil && hasTrustedCABundle(trustedCABundleCM)
+	if addTrustedCAVolume {
+		vectorContainer.VolumeMounts = append(vectorContainer.VolumeMounts,
+			v1.VolumeMount{
+				Name:      constants.CollectorTrustedCAName,
+				ReadOnly:  true,
+				MountPath: constants.TrustedCABundleMountDir,
+			})
+	}
+
+	securityContext := &v1.SecurityContext{
+		SELinuxOptions: &v1.SELinuxOptions{
+			Type: "spc_t",
+		},
+		ReadOnlyRootFilesystem:   utils.GetBool(true),
+		AllowPrivilegeEscalation: utils.GetBool(false),
+	}
+	vectorContainer.SecurityContext = securityContext
+	exporterContainer.SecurityContext = securityContext.DeepCopy()
+
+	tolerations := utils.AppendTolerations(
+		collectorSpec.Tolerations,
+		[]v1.Toleration{
+			{
+				Key:      "node-role.kubernetes.io/master",
+				Operator: v1.TolerationOpExists,
+				Effect:   v1.TaintEffectNoSchedule,
+			},
+			{
+				Key:      "node.kubernetes.io/disk-pressure",
+				Operator: v1.TolerationOpExists,
+				Effect:   v1.TaintEffectNoSchedule,
+			},
+		},
+	)
+
+	vectorPodSpec := NewPodSpec(
+		"logcollector",
+		[]v1.Container{vectorContainer, exporterContainer},
+		[]v1.Volume{
+			{Name: logVolumeMountName, VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: logVolumePath}}},
+			{Name: logContainers, VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: logContainersValue}}},
+			{Name: logPods, VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: logPodsValue}}},
+			{Name: logJournal, VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: logJournalValue}}},
+			{Name: logAudit, VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: logAuditValue}}},
+			{Name: logOvn, VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: logOvnValue}}},
+			{Name: logOauthapiserver, VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: logOauthapiserverValue}}},
+			{Name: logOpenshiftapiserver, VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: logOpenshiftapiserverValue}}},
+			{Name: logKubeapiserver, VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: logKubeapiserverValue}}},
+			{Name: config, VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: constants.CollectorName}}}},
+			{Name: certs, VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: constants.CollectorName, Optional: utils.GetBool(true)}}},
+			{Name: localtime, VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: localtimeValue}}},
+			{Name: vectorDataDir, VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: vectorDataDirValue}}},
+			{Name: metricsVolumeName, VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: constants.CollectorMetricSecretName}}},
+			{Name: tmp, VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory}}},
+		},
+		collectorSpec.NodeSelector,
+		tolerations,
+	)
+	for _, name := range secretNames {
+		vectorPodSpec.Volumes = append(vectorPodSpec.Volumes, v1.Volume{Name: name, VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: name}}})
+	}
+	if addTrustedCAVolume {
+		vectorPodSpec.Volumes = append(vectorPodSpec.Volumes,
+			v1.Volume{
+				Name: constants.CollectorTrustedCAName,
+				VolumeSource: v1.VolumeSource{
+					ConfigMap: &v1.ConfigMapVolumeSource{
+						LocalObjectReference: v1.LocalObjectReference{
+							Name: constants.CollectorTrustedCAName,
+						},
+						Items: []v1.KeyToPath{
+							{
+								Key:  constants.TrustedCABundleKey,
+								Path: constants.TrustedCABundleMountFile,
+							},
+						},
+					},
+				},
+			})
+	}
+
+	vectorPodSpec.PriorityClassName = clusterLoggingPriorityClassName
+	if collectorSpec.PriorityClassName != "" {
+		vectorPodSpec.PriorityClassName = collectorSpec.PriorityClassName
+	}
+	vectorPodSpec.Affinity = collectorSpec.Affinity
+	vectorPodSpec.TerminationGracePeriodSeconds = utils.GetInt64(10)
+
+	return vectorPodSpec
+}
@@ -0,0 +1,29 @@
+package k8shandler
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/openshift/cluster-logging-operator/test/matchers"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/utils"
+	v1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("vector.go#newVectorPodSpec", func() {
+	It("should provide the credentials of the output secrets as environment vars", func() {
+		podSpec := newVectorPodSpec(&logging.ClusterLogging{}, nil, logging.ClusterLogForwarderSpec{
+			Outputs: []logging.OutputSpec{
+				{Name: "es", Type: logging.OutputTypeElasticsearch, Secret: &logging.OutputSecretSpec{Name: "es-secret"}},
+			},
+		})
+		Expect(podSpec.Containers[0].Env).To(IncludeEnvVar(v1.EnvVar{
+			Name: "VECTOR_SECRET_es_2dsecret__password",
+			ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: "es-secret"},
+				Key:                  "password",
+				Optional:             utils.GetBool(true),
+			}},
+		}))
+	})
+})
@@ -10,6 +10,7 @@ import (
 	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
 	"github.com/openshift/cluster-logging-operator/internal/generator"
 	"github.com/openshift/cluster-logging-operator/internal/k8shandler"
+	corev1 "k8s.io/api/core/v1"
 )
 
 const (
@@ -20,6 +21,11 @@ const (
 )
 
 func Generate(clfYaml string, includeDefaultLogStore, includeLegacyForward, debugOutput bool) (string, error) {
+	return GenerateWithSecrets(clfYaml, nil, includeDefaultLogStore, includeLegacyForward, debugOutput)
+}
+
+// GenerateWithSecrets generates the collector configuration like Generate, with the output secrets keyed by name
+func GenerateWithSecrets(clfYaml string, secrets map[string]*corev1.Secret, includeDefaultLogStore, includeLegacyForward, debugOutput bool) (string, error) {
 
 	var err error
 	g := generator.MakeGenerator()
@@ -72,7 +78,7 @@ func Generate(clfYaml string, includeDefaultLogStore, includeLegacyForward, debu
 	}
 	if logCollectorType == logging.LogCollectionTypeFluentd {
 
-		sections := fluentd2.Conf(&clspec, nil, spec, op)
+		sections := fluentd2.Conf(&clspec, secrets, spec, op)
 		es := generator.MergeSections(sections)
 
 		generatedConfig, err := g.GenerateConf(es...)
@@ -6,6 +6,7 @@ import (
 	"github.com/ViaQ/logerr/log"
 	"github.com/openshift/cluster-logging-operator/internal/utils"
 	apps "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 //AreSame compares daemonset for equality and return true equal otherwise false
@@ -20,6 +21,31 @@ func AreSame(current *apps.DaemonSet, desired *apps.DaemonSet) bool {
 		return false
 	}
 
+	if !isUpdateStrategySame(current.Spec.UpdateStrategy, desired.Spec.UpdateStrategy) {
+		log.V(3).Info("DaemonSet update strategy change", "DaemonSetName", current.Name)
+		return false
+	}
+
+	if !containsAll(current.Spec.Template.Labels, desired.Spec.Template.Labels) {
+		log.V(3).Info("DaemonSet pod labels change", "DaemonSetName", current.Name)
+		return false
+	}
+
+	if !containsAll(current.Spec.Template.Annotations, desired.Spec.Template.Annotations) {
+		log.V(3).Info("DaemonSet pod annotations change", "DaemonSetName", current.Name)
+		return false
+	}
+
+	if !reflect.DeepEqual(current.Spec.Template.Spec.Affinity, desired.Spec.Template.Spec.Affinity) {
+		log.V(3).Info("DaemonSet affinity change", "DaemonSetName", current.Name)
+		return false
+	}
+
+	if current.Spec.Template.Spec.PriorityClassName != desired.Spec.Template.Spec.PriorityClassName {
+		log.V(3).Info("DaemonSet priorityClassName change", "DaemonSetName", current.Name)
+		return false
+	}
+
 	if !utils.PodVolumeEquivalent(current.Spec.Template.Spec.Volumes, desired.Spec.Template.Spec.Volumes) {
 		log.V(3).Info("DaemonSet volumes change", "DaemonSetName", current.Name)
 		return false
@@ -66,6 +92,17 @@ func AreSame(current *apps.DaemonSet, desired *apps.DaemonSet) bool {
 	return true
 }
 
+// containsAll compares only the keys set by the operator, so that keys added by others
+// (e.g. kubectl.kubernetes.io/restartedAt) do not trigger an update
+func containsAll(current, desired map[string]string) bool {
+	for key, value := range desired {
+		if currentValue, found := current[key]; !found || currentValue != value {
+			return false
+		}
+	}
+	return true
+}
+
 func isDaemonsetImageDifference(current *apps.DaemonSet, desired *apps.DaemonSet) bool {
 
 	for _, curr := range current.Spec.Template.Spec.Containers {
@@ -81,3 +118,28 @@ func isDaemonsetImageDifference(current *apps.DaemonSet, desired *apps.DaemonSet
 
 	return false
 }
+
+// isUpdateStrategySame compares update strategies, taking the defaults of the API server into account
+func isUpdateStrategySame(current, desired apps.DaemonSetUpdateStrategy) bool {
+	currentType, desiredType := current.Type, desired.Type
+	if currentType == "" {
+		currentType = apps.RollingUpdateDaemonSetStrategyType
+	}
+	if desiredType == "" {
+		desiredType = apps.RollingUpdateDaemonSetStrategyType
+	}
+	if currentType != desiredType {
+		return false
+	}
+	if currentType != apps.RollingUpdateDaemonSetStrategyType {
+		return true
+	}
+	return maxUnavailable(current.RollingUpdate) == maxUnavailable(desired.RollingUpdate)
+}
+
+func maxUnavailable(rollingUpdate *apps.RollingUpdateDaemonSet) intstr.IntOrString {
+	if rollingUpdate == nil || rollingUpdate.MaxUnavailable == nil {
+		return intstr.FromInt(1)
+	}
+	return *rollingUpdate.MaxUnavailable
+}
@@ -5,6 +5,7 @@ import (
 	. "github.com/onsi/gomega"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/openshift/cluster-logging-operator/internal/utils/comparators/daemonsets"
 )
@@ -48,6 +49,79 @@ var _ = Describe("daemonset#AreSame", func() {
 		})
 	})
 
+	Context("when evaluating the update strategy", func() {
+
+		It("should consider the defaults of the API server to be the same", func() {
+			maxUnavailable := intstr.FromInt(1)
+			current.Spec.UpdateStrategy = apps.DaemonSetUpdateStrategy{
+				Type:          apps.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &apps.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable},
+			}
+			desired.Spec.UpdateStrategy = apps.DaemonSetUpdateStrategy{
+				Type:          apps.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &apps.RollingUpdateDaemonSet{},
+			}
+			Expect(daemonsets.AreSame(current, desired)).To(BeTrue())
+		})
+
+		It("should recognize different types", func() {
+			desired.Spec.UpdateStrategy.Type = apps.OnDeleteDaemonSetStrategyType
+			Expect(daemonsets.AreSame(current, desired)).To(BeFalse())
+		})
+
+		It("should recognize different maxUnavailable", func() {
+			maxUnavailable := intstr.FromString("25%")
+			desired.Spec.UpdateStrategy.RollingUpdate = &apps.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable}
+			Expect(daemonsets.AreSame(current, desired)).To(BeFalse())
+		})
+	})
+
+	Context("when evaluating the pod template", func() {
+
+		It("should recognize different labels", func() {
+			desired.Spec.Template.Labels = map[string]string{"foo": "bar"}
+			Expect(daemonsets.AreSame(current, desired)).To(BeFalse())
+		})
+
+		It("should recognize different annotations", func() {
+			desired.Spec.Template.Annotations = map[string]string{"foo": "bar"}
+			Expect(daemonsets.AreSame(current, desired)).To(BeFalse())
+		})
+
+		It("should ignore labels and annotations not set by the operator", func() {
+			current.Spec.Template.Labels = map[string]string{"foo": "bar", "example.com/team": "logging"}
+			current.Spec.Template.Annotations = map[string]string{"foo": "bar", "kubectl.kubernetes.io/restartedAt": "2021-01-01T00:00:00Z"}
+			desired.Spec.Template.Labels = map[string]string{"foo": "bar"}
+			desired.Spec.Template.Annotations = map[string]string{"foo": "bar"}
+			Expect(daemonsets.AreSame(current, desired)).To(BeTrue())
+		})
+
+		It("should recognize different values of the labels and annotations set by the operator", func() {
+			current.Spec.Template.Labels = map[string]string{"foo": "bar"}
+			current.Spec.Template.Annotations = map[string]string{"foo": "bar"}
+			desired.Spec.Template.Labels = map[string]string{"foo": "baz"}
+			Expect(daemonsets.AreSame(current, desired)).To(BeFalse())
+			desired.Spec.Template.Labels = current.Spec.Template.Labels
+			desired.Spec.Template.Annotations = map[string]string{"foo": "baz"}
+			Expect(daemonsets.AreSame(current, desired)).To(BeFalse())
+		})
+
+		It("should recognize different affinity", func() {
+			desired.Spec.Template.Spec.Affinity = &v1.Affinity{PodAffinity: &v1.PodAffinity{}}
+			Expect(daemonsets.AreSame(current, desired)).To(BeFalse())
+		})
+
+		It("should recognize different priority classes", func() {
+			desired.Spec.Template.Spec.PriorityClassName = "foo"
+			Expect(daemonsets.AreSame(current, desired)).To(BeFalse())
+		})
+
+		It("should recognize different environment variables", func() {
+			desired.Spec.Template.Spec.Containers[0].Env = []v1.EnvVar{{Name: "foo", Value: "bar"}}
+			Expect(daemonsets.AreSame(current, desired)).To(BeFalse())
+		})
+	})
+
 	Context("when evaluating init containers", func() {
 
 		It("should recognize the numbers are different", func() {
@@ -37,6 +37,7 @@ var COMPONENT_IMAGES = map[string]string{
 	constants.FluentdName:                constants.FluentdImageEnvVar,
 	"kibana":                             "KIBANA_IMAGE",
 	constants.LogfilesmetricexporterName: constants.LogfilesmetricImageEnvVar,
+	constants.VectorName:                 constants.VectorImageEnvVar,
 }
 
 // GetAnnotation returns the value of an annoation for a given key and true if the key was found
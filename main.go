@@ -92,6 +92,7 @@ func main() {
 		Port:               9443,
 		LeaderElection:     enableLeaderElection,
 		LeaderElectionID:   "b430cc2e.openshift.io",
+		NewCache:           forwarding.NewCache,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -118,6 +119,20 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterLogging")
 		os.Exit(1)
 	}
+	if err = (&forwarding.ReconcileLogForwarder{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("logforwarder"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "LogForwarder")
+		os.Exit(1)
+	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = forwarding.SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterLogForwarder")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	log.Info("Starting the Cmd.")
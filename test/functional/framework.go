@@ -56,6 +56,9 @@ var outputLogFile = map[string]map[string]string{
 		ovnAuditLog:    "/tmp/audit-logs",
 		k8sAuditLog:    "/tmp/audit-logs",
 	},
+	logging.OutputTypeHttp: {
+		applicationLog: ApplicationLogFile,
+	},
 	logging.OutputTypeSyslog: {
 		applicationLog: "/var/log/infra.log",
 		auditLog:       "/var/log/infra.log",
@@ -98,6 +101,7 @@ type FluentdFunctionalFramework struct {
 	image             string
 	labels            map[string]string
 	Forwarder         *logging.ClusterLogForwarder
+	Secrets           map[string]*corev1.Secret
 	Test              *client.Test
 	Pod               *corev1.Pod
 	fluentContainerId string
@@ -201,7 +205,7 @@ func (f *FluentdFunctionalFramework) DeployWithVisitors(visitors []runtime.PodBu
 	log.V(2).Info("Generating config", "forwarder", f.Forwarder)
 	clfYaml, _ := yaml.Marshal(f.Forwarder)
 	debug_output := false
-	if f.Conf, err = forwarder.Generate(string(clfYaml), false, false, debug_output); err != nil {
+	if f.Conf, err = forwarder.GenerateWithSecrets(string(clfYaml), f.Secrets, false, false, debug_output); err != nil {
 		return err
 	}
 	log.V(2).Info("Generating Certificates")
@@ -361,6 +365,10 @@ func (f *FluentdFunctionalFramework) addOutputContainers(b *runtime.PodBuilder,
 			if err := f.addES7Output(b, output); err != nil {
 				return err
 			}
+		case logging.OutputTypeHttp:
+			if err := f.addHttpOutput(b, output); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -0,0 +1,77 @@
+package normalization
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/openshift/cluster-logging-operator/internal/utils"
+	"github.com/openshift/cluster-logging-operator/test/helpers/types"
+	"github.com/openshift/cluster-logging-operator/test/matchers"
+
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/test/functional"
+)
+
+// Test for grouping the lines of multiline exception stack traces into a single log entry.
+// Every line of a stack trace is written by the container as a separate log entry, e.g.:
+// 2021-03-31T12:59:28.573159188+00:00 stdout F Exception in thread "main" java.lang.IllegalStateException: boom
+// 2021-03-31T12:59:28.573159188+00:00 stdout F     at com.example.App.main(App.java:12)
+var _ = Describe("Detecting multiline exceptions", func() {
+
+	const (
+		inputName = "java-app"
+	)
+	var (
+		framework *functional.FluentdFunctionalFramework
+		timestamp = "2021-03-31T12:59:28.573159188+00:00"
+	)
+
+	BeforeEach(func() {
+		framework = functional.NewFluentdFunctionalFramework()
+		framework.Forwarder.Spec.Inputs = []logging.InputSpec{
+			{
+				Name: inputName,
+				Application: &logging.Application{
+					Namespaces: []string{framework.Namespace},
+					DetectExceptions: &logging.DetectExceptions{
+						Languages: []logging.ExceptionLanguage{"java"},
+					},
+				},
+			},
+		}
+		functional.NewClusterLogForwarderBuilder(framework.Forwarder).
+			FromInput(inputName).
+			ToFluentForwardOutput()
+		Expect(framework.Deploy()).To(BeNil())
+	})
+	AfterEach(func() {
+		framework.Cleanup()
+	})
+
+	It("should group a stack trace into a single log", func() {
+		trace := []string{
+			`Exception in thread "main" java.lang.IllegalStateException: A test exception`,
+			`    at com.example.myproject.Book.getTitle(Book.java:16)`,
+			`    at com.example.myproject.Author.getBookTitles(Author.java:25)`,
+			`    at com.example.myproject.Bootstrap.main(Bootstrap.java:14)`,
+		}
+		for _, line := range trace {
+			msg := functional.NewCRIOLogMessage(timestamp, line, false)
+			matchers.ExpectOK(framework.WriteMessagesToApplicationLog(msg, 1),
+				"Expected no errors writing the logs")
+		}
+		//write single-line log entry
+		msg := functional.NewCRIOLogMessage(timestamp, "Exited with status 1", false)
+		matchers.ExpectOK(framework.WriteMessagesToApplicationLog(msg, 1),
+			"Expected no errors writing the logs")
+
+		raw, err := framework.ReadApplicationLogsFrom(logging.OutputTypeFluentdForward)
+		Expect(err).To(BeNil(), "Expected no errors reading the logs")
+		logs, err := types.ParseLogs(utils.ToJsonLogs(raw))
+		Expect(err).To(BeNil(), "Expected no errors parsing the logs")
+		Expect(logs).To(HaveLen(2))
+		Expect(strings.TrimSpace(logs[0].Message)).Should(Equal(strings.Join(trace, "\n")))
+		Expect(logs[1].Message).Should(Equal("Exited with status 1"))
+	})
+})
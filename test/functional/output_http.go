@@ -0,0 +1,58 @@
+package functional
+
+import (
+	"strings"
+
+	"github.com/ViaQ/logerr/log"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
+	"github.com/openshift/cluster-logging-operator/internal/utils"
+	"github.com/openshift/cluster-logging-operator/test/runtime"
+)
+
+const (
+	httpReceiverPort = "8090"
+
+	unsecureHttpConf = `
+<system>
+  log_level debug
+</system>
+<source>
+  @type http
+  port ` + httpReceiverPort + `
+  bind 0.0.0.0
+  <parse>
+    @type json
+  </parse>
+</source>
+
+<match **>
+  @type file
+  append true
+  path /tmp/app.logs
+  symlink_path /tmp/app-logs
+  <format>
+    @type json
+  </format>
+</match>`
+)
+
+func (f *FluentdFunctionalFramework) addHttpOutput(b *runtime.PodBuilder, output logging.OutputSpec) error {
+	log.V(2).Info("Adding http output", "name", output.Name)
+	name := strings.ToLower(output.Name)
+	config := runtime.NewConfigMap(b.Pod.Namespace, name, map[string]string{
+		"fluent.conf": unsecureHttpConf,
+	})
+	log.V(2).Info("Creating configmap", "namespace", config.Namespace, "name", config.Name, "fluent.conf", unsecureHttpConf)
+	if err := f.Test.Client.Create(config); err != nil {
+		return err
+	}
+
+	log.V(2).Info("Adding container", "name", name)
+	b.AddContainer(name, utils.GetComponentImage(constants.FluentdName)).
+		AddVolumeMount(config.Name, "/tmp/config", "", false).
+		WithCmd("fluentd -c /tmp/config/fluent.conf").
+		End().
+		AddConfigMapVolume(config.Name, config.Name)
+	return nil
+}
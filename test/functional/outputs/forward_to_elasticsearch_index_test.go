@@ -2,13 +2,17 @@ package outputs
 
 import (
 	"fmt"
+	"strings"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/constants"
 	"github.com/openshift/cluster-logging-operator/test/functional"
 	"github.com/openshift/cluster-logging-operator/test/helpers/types"
 	"github.com/openshift/cluster-logging-operator/test/matchers"
 	"github.com/openshift/cluster-logging-operator/test/runtime"
+	corev1 "k8s.io/api/core/v1"
 )
 
 var _ = Describe("[Functional][Outputs][ElasticSearch][Index] FluentdForward Output to specific ElasticSearch index", func() {
@@ -193,6 +197,138 @@ var _ = Describe("[Functional][Outputs][ElasticSearch][Index] FluentdForward Out
 				Expect(outputTestLog).To(matchers.FitLogFormatTemplate(outputLogTemplate))
 			})
 		})
+		Context("with an index template", func() {
+			It("should send logs to the index named by the template", func() {
+				functional.NewClusterLogForwarderBuilder(framework.Forwarder).
+					FromInput(logging.InputNameApplication).
+					ToOutputWithVisitor(func(spec *logging.OutputSpec) {
+						spec.Elasticsearch = &logging.Elasticsearch{
+							Index: "functional-{.log_type}-%Y.%m",
+						}
+					}, logging.OutputTypeElasticsearch)
+				// the date is formatted from the timestamp of the record written by CreateAppLogFromJson
+				ESIndexName := "functional-application-2020.11"
+				Expect(framework.Deploy()).To(BeNil())
+
+				applicationLogLine := functional.CreateAppLogFromJson(jsonLog)
+				Expect(framework.WriteMessagesToApplicationLog(applicationLogLine, 10)).To(BeNil())
+				raw, err := framework.GetLogsFromElasticSearchIndex(logging.OutputTypeElasticsearch, ESIndexName)
+				Expect(err).To(BeNil(), "Expected no errors reading the logs")
+				Expect(raw).To(Not(BeEmpty()))
+
+				// Parse log line
+				var logs []types.ApplicationLog
+				err = types.StrictlyParseLogs(raw, &logs)
+				Expect(err).To(BeNil(), "Expected no errors parsing the logs")
+				// Compare to expected template
+				outputTestLog := logs[0]
+				outputLogTemplate.ViaqIndexName = ""
+				Expect(outputTestLog).To(matchers.FitLogFormatTemplate(outputLogTemplate))
+			})
+			It("should send logs to the data stream named by the template", func() {
+				functional.NewClusterLogForwarderBuilder(framework.Forwarder).
+					FromInput(logging.InputNameApplication).
+					ToOutputWithVisitor(func(spec *logging.OutputSpec) {
+						spec.Elasticsearch = &logging.Elasticsearch{
+							Index:      "logs-{.log_type}-functional",
+							DataStream: true,
+						}
+					}, logging.OutputTypeElasticsearch)
+				// created from the built-in logs-*-* index template
+				DataStreamName := "logs-application-functional"
+				Expect(framework.Deploy()).To(BeNil())
+
+				applicationLogLine := functional.CreateAppLogFromJson(jsonLog)
+				Expect(framework.WriteMessagesToApplicationLog(applicationLogLine, 10)).To(BeNil())
+				raw, err := framework.GetLogsFromElasticSearchIndex(logging.OutputTypeElasticsearch, DataStreamName)
+				Expect(err).To(BeNil(), "Expected no errors reading the logs")
+				Expect(raw).To(Not(BeEmpty()))
+
+				// Parse log line
+				var logs []types.ApplicationLog
+				err = types.StrictlyParseLogs(raw, &logs)
+				Expect(err).To(BeNil(), "Expected no errors parsing the logs")
+				// Compare to expected template
+				outputTestLog := logs[0]
+				outputLogTemplate.ViaqIndexName = ""
+				Expect(outputTestLog).To(matchers.FitLogFormatTemplate(outputLogTemplate))
+			})
+		})
+		Context("with API key authentication", func() {
+			const (
+				secretName     = "es-api-key"
+				apiKeyDir      = "/var/run/ocp-collector/secrets/" + secretName
+				apiKeyFile     = apiKeyDir + "/" + constants.ElasticsearchAPIKey
+				elasticPasswd  = "functional"
+				apiKeyVolume   = "api-key"
+				createKeyShell = `
+/usr/local/bin/docker-entrypoint.sh eswrapper &
+until curl -sf -u elastic:%[1]s localhost:9200 >/dev/null; do sleep 1; done
+curl -sf -u elastic:%[1]s -X POST localhost:9200/_security/api_key -H 'Content-Type: application/json' -d '{"name":"functional"}' |
+  sed -e 's/.*"id":"\([^"]*\)".*"api_key":"\([^"]*\)".*/\1:\2/' | tr -d '\n' | base64 -w0 > %[2]s.tmp
+mv %[2]s.tmp %[2]s
+wait
+`
+			)
+			// withAPIKeySecurity enables the security of elasticsearch, which creates an API key on start. The
+			// collector waits for the key before reading it, anonymous users may only read the indices
+			withAPIKeySecurity := func(b *runtime.PodBuilder) error {
+				b.AddEmptyDirVolume(apiKeyVolume)
+				for i, c := range b.Pod.Spec.Containers {
+					mount := corev1.VolumeMount{Name: apiKeyVolume, MountPath: apiKeyDir}
+					switch c.Name {
+					case strings.ToLower(logging.OutputTypeElasticsearch):
+						c.Env = append(c.Env,
+							corev1.EnvVar{Name: "xpack.security.enabled", Value: "true"},
+							corev1.EnvVar{Name: "xpack.security.authc.api_key.enabled", Value: "true"},
+							corev1.EnvVar{Name: "xpack.security.authc.anonymous.roles", Value: "viewer"},
+							corev1.EnvVar{Name: "ELASTIC_PASSWORD", Value: elasticPasswd},
+						)
+						c.Command = []string{"bash", "-c", fmt.Sprintf(createKeyShell, elasticPasswd, apiKeyFile)}
+						c.ReadinessProbe = &corev1.Probe{
+							Handler:       corev1.Handler{Exec: &corev1.ExecAction{Command: []string{"test", "-s", apiKeyFile}}},
+							PeriodSeconds: 2,
+						}
+					case constants.CollectorName:
+						mount.ReadOnly = true
+						c.Command = []string{"bash", "-c", fmt.Sprintf("until [ -s %s ]; do sleep 1; done; exec bash /opt/app-root/src/run.sh", apiKeyFile)}
+					default:
+						continue
+					}
+					c.VolumeMounts = append(c.VolumeMounts, mount)
+					b.Pod.Spec.Containers[i] = c
+				}
+				return nil
+			}
+			It("should send logs authenticated by the API key", func() {
+				functional.NewClusterLogForwarderBuilder(framework.Forwarder).
+					FromInput(logging.InputNameApplication).
+					ToOutputWithVisitor(func(spec *logging.OutputSpec) {
+						spec.URL = "http://0.0.0.0:9200"
+						spec.Secret = &logging.OutputSecretSpec{Name: secretName}
+					}, logging.OutputTypeElasticsearch)
+				// the configuration reads the API key from the secret file, its value is created by elasticsearch
+				framework.Secrets = map[string]*corev1.Secret{
+					secretName: {Data: map[string][]byte{constants.ElasticsearchAPIKey: []byte("created-on-start")}},
+				}
+				Expect(framework.DeployWithVisitors(append(framework.AddOutputContainersVisitors(), withAPIKeySecurity))).To(BeNil())
+
+				applicationLogLine := functional.CreateAppLogFromJson(jsonLog)
+				Expect(framework.WriteMessagesToApplicationLog(applicationLogLine, 10)).To(BeNil())
+				raw, err := framework.GetLogsFromElasticSearchIndex(logging.OutputTypeElasticsearch, AppIndex)
+				Expect(err).To(BeNil(), "Expected no errors reading the logs")
+				Expect(raw).To(Not(BeEmpty()))
+
+				// Parse log line
+				var logs []types.ApplicationLog
+				err = types.StrictlyParseLogs(raw, &logs)
+				Expect(err).To(BeNil(), "Expected no errors parsing the logs")
+				// Compare to expected template
+				outputTestLog := logs[0]
+				outputLogTemplate.ViaqIndexName = ""
+				Expect(outputTestLog).To(matchers.FitLogFormatTemplate(outputLogTemplate))
+			})
+		})
 		Context("if json parsing failed", func() {
 			It("should send logs to app-write", func() {
 				clfb := functional.NewClusterLogForwarderBuilder(framework.Forwarder).
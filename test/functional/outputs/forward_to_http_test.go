@@ -0,0 +1,64 @@
+package outputs
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	logging "github.com/openshift/cluster-logging-operator/apis/logging/v1"
+	"github.com/openshift/cluster-logging-operator/internal/utils"
+	"github.com/openshift/cluster-logging-operator/test/functional"
+	"github.com/openshift/cluster-logging-operator/test/helpers/types"
+)
+
+var _ = Describe("[Functional][Outputs][Http] Functional tests", func() {
+
+	var (
+		framework *functional.FluentdFunctionalFramework
+	)
+
+	BeforeEach(func() {
+		framework = functional.NewFluentdFunctionalFramework()
+	})
+	AfterEach(func() {
+		framework.Cleanup()
+	})
+
+	Context("Application Logs", func() {
+		sendsLogs := func(desc string, visit func(spec *logging.OutputSpec)) {
+			It(fmt.Sprintf("should send application logs %s", desc), func() {
+				functional.NewClusterLogForwarderBuilder(framework.Forwarder).
+					FromInput(logging.InputNameApplication).
+					ToOutputWithVisitor(func(spec *logging.OutputSpec) {
+						spec.URL = "http://localhost:8090/logs.app"
+						visit(spec)
+					}, logging.OutputTypeHttp)
+				Expect(framework.Deploy()).To(BeNil())
+
+				msg := functional.NewFullCRIOLogMessage(functional.CRIOTime(time.Now()), "This is my test message")
+				Expect(framework.WriteMessagesToApplicationLog(msg, 2)).To(BeNil())
+
+				raw, err := framework.ReadApplicationLogsFrom(logging.OutputTypeHttp)
+				Expect(err).To(BeNil(), "Expected no errors reading the logs")
+				Expect(raw).To(HaveLen(2))
+
+				var logs []types.ApplicationLog
+				Expect(types.StrictlyParseLogs(utils.ToJsonLogs(raw), &logs)).To(Succeed(), "Expected no errors parsing the logs")
+				Expect(logs[0].Message).To(Equal("This is my test message"))
+			})
+		}
+
+		sendsLogs("as newline delimited json", func(spec *logging.OutputSpec) {})
+		sendsLogs("as a gzip compressed json array using PUT", func(spec *logging.OutputSpec) {
+			spec.Http = &logging.Http{
+				Method:      "PUT",
+				Format:      "json",
+				Compression: "gzip",
+				Headers: map[string]string{
+					"X-Test": "functional",
+				},
+			}
+		})
+	})
+})
@@ -107,6 +107,16 @@ func (builder *PodBuilder) AddConfigMapVolume(name, configMapName string) *PodBu
 	return builder
 }
 
+func (builder *PodBuilder) AddEmptyDirVolume(name string) *PodBuilder {
+	builder.Pod.Spec.Volumes = append(builder.Pod.Spec.Volumes, corev1.Volume{
+		Name: name,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	return builder
+}
+
 func (builder *ContainerBuilder) AddRunAsUser(uid int64) *ContainerBuilder {
 	builder.container.SecurityContext = &corev1.SecurityContext{
 		RunAsUser: &uid,